@@ -121,5 +121,6 @@ var commonInitialisms = map[string]bool{
 
 	// Custom added for dagger
 	"FS":  true,
+	"LFS": true,
 	"SDK": true,
 }
@@ -0,0 +1,208 @@
+package templates
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	. "github.com/dave/jennifer/jen" // nolint:revive,stylecheck
+)
+
+// isGoEnum returns whether the given named type should be registered as an
+// enum, i.e. it's a string type declared in the module with at least one
+// exported constant of that type.
+func (ps *parseState) isGoEnum(named *types.Named) bool {
+	if named == nil {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Pkg() != ps.pkg.Types || ps.isDaggerGenerated(obj) {
+		return false
+	}
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsString == 0 {
+		return false
+	}
+	return len(ps.enumConsts(named)) > 0
+}
+
+// enumConsts returns the exported constants declared with the given named
+// type, in definition order.
+func (ps *parseState) enumConsts(named *types.Named) []*types.Const {
+	var consts []*types.Const
+	scope := ps.pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !c.Exported() {
+			continue
+		}
+		if types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+	return consts
+}
+
+func (ps *parseState) parseGoEnum(named *types.Named) (*parsedEnumType, error) {
+	spec := &parsedEnumType{
+		name:   named.Obj().Name(),
+		goType: named,
+	}
+
+	// get the comment above the type (if any)
+	astSpec, err := ps.astSpecForNamedType(named)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find decl for named type %s: %w", spec.name, err)
+	}
+	spec.doc = astSpec.Doc.Text()
+
+	for _, c := range ps.enumConsts(named) {
+		if c.Val().Kind() != constant.String {
+			return nil, fmt.Errorf("enum value %s must be a string constant", c.Name())
+		}
+		valueSpec := &enumValueSpec{
+			value: constant.StringVal(c.Val()),
+		}
+		if astSpec := ps.astSpecForConst(c); astSpec != nil {
+			comment := strings.TrimSpace(astSpec.Doc.Text())
+			if comment == "" {
+				comment = strings.TrimSpace(astSpec.Comment.Text())
+			}
+			valueSpec.doc = comment
+		}
+		spec.values = append(spec.values, valueSpec)
+	}
+
+	return spec, nil
+}
+
+// astSpecForConst returns the *ast* value spec for the given constant, so
+// that its comments can be parsed.
+func (ps *parseState) astSpecForConst(c *types.Const) *ast.ValueSpec {
+	tokenFile := ps.fset.File(c.Pos())
+	if tokenFile == nil {
+		return nil
+	}
+	for _, f := range ps.pkg.Syntax {
+		if ps.fset.File(f.Pos()) != tokenFile {
+			continue
+		}
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for _, name := range valueSpec.Names {
+					if name.Pos() != c.Pos() {
+						continue
+					}
+					if valueSpec.Doc == nil && len(genDecl.Specs) == 1 {
+						valueSpec.Doc = genDecl.Doc
+					}
+					return valueSpec
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parsedEnumType is a parsed enum type along with all of its values
+type parsedEnumType struct {
+	name string
+	doc  string
+
+	values []*enumValueSpec
+
+	goType *types.Named
+}
+
+type enumValueSpec struct {
+	value string
+	doc   string
+}
+
+var _ NamedParsedType = &parsedEnumType{}
+
+func (spec *parsedEnumType) TypeDefCode() (*Statement, error) {
+	withEnumArgsCode := []Code{
+		Lit(spec.name),
+	}
+	if spec.doc != "" {
+		withEnumArgsCode = append(withEnumArgsCode, Id("TypeDefWithEnumOpts").Values(
+			Id("Description").Op(":").Lit(strings.TrimSpace(spec.doc)),
+		))
+	}
+
+	typeDefCode := Qual("dag", "TypeDef").Call().Dot("WithEnum").Call(withEnumArgsCode...)
+
+	for _, val := range spec.values {
+		withEnumValueArgsCode := []Code{
+			Lit(val.value),
+		}
+		if val.doc != "" {
+			withEnumValueArgsCode = append(withEnumValueArgsCode, Id("TypeDefWithEnumValueOpts").Values(
+				Id("Description").Op(":").Lit(val.doc),
+			))
+		}
+		typeDefCode = dotLine(typeDefCode, "WithEnumValue").Call(withEnumValueArgsCode...)
+	}
+
+	return typeDefCode, nil
+}
+
+func (spec *parsedEnumType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedEnumType) GoSubTypes() []types.Type {
+	return nil
+}
+
+func (spec *parsedEnumType) Name() string {
+	return spec.name
+}
+
+// parsedEnumTypeReference is a parsed enum type that is referred to just by name rather
+// than with the full type definition
+type parsedEnumTypeReference struct {
+	name   string
+	isPtr  bool
+	goType types.Type
+}
+
+var _ NamedParsedType = &parsedEnumTypeReference{}
+
+func (spec *parsedEnumTypeReference) TypeDefCode() (*Statement, error) {
+	def := Qual("dag", "TypeDef").Call().Dot("WithEnum").Call(
+		Lit(spec.name),
+	)
+	if spec.isPtr {
+		def = def.Dot("WithOptional").Call(Lit(true))
+	}
+	return def, nil
+}
+
+func (spec *parsedEnumTypeReference) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedEnumTypeReference) GoSubTypes() []types.Type {
+	// because this is a *reference* to a named type, we return the goType itself as a subtype too
+	return []types.Type{spec.goType}
+}
+
+func (spec *parsedEnumTypeReference) Name() string {
+	return spec.name
+}
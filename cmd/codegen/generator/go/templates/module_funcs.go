@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/types"
 	"maps"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -128,15 +129,9 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 			argOptsCode = append(argOptsCode, Id("Description").Op(":").Lit(argSpec.description))
 		}
 		if argSpec.defaultValue != "" {
-			var jsonEnc string
-			if argSpec.typeSpec.GoType().String() == "string" {
-				enc, err := json.Marshal(argSpec.defaultValue)
-				if err != nil {
-					return nil, fmt.Errorf("failed to marshal default value: %w", err)
-				}
-				jsonEnc = string(enc)
-			} else {
-				jsonEnc = argSpec.defaultValue
+			jsonEnc, err := defaultValueJSON(argSpec.typeSpec.GoType(), argSpec.defaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode default value for arg %q: %w", argSpec.name, err)
 			}
 			argOptsCode = append(argOptsCode, Id("DefaultValue").Op(":").Id("JSON").Call(Lit(jsonEnc)))
 		}
@@ -218,6 +213,11 @@ func (ps *parseState) parseParamSpecs(parentType *types.Named, fn *types.Func) (
 				if err != nil {
 					return nil, err
 				}
+				// a `default:"..."` struct tag is equivalent to a +default pragma,
+				// which takes precedence if both are set
+				if v, ok := reflect.StructTag(paramType.Tag(f)).Lookup("default"); ok && spec.defaultValue == "" {
+					spec.defaultValue = v
+				}
 				spec.parent = parent
 				specs = append(specs, spec)
 			}
@@ -330,6 +330,24 @@ func (ps *parseState) parseParamSpecVar(field *types.Var, docComment string, lin
 	}, nil
 }
 
+// defaultValueJSON encodes the default value of an arg as JSON. Defaults of
+// string types (including enums and scalars backed by a string) are written
+// bare, so they're quoted here; everything else is expected to already be
+// valid JSON.
+func defaultValueJSON(goType types.Type, value string) (string, error) {
+	if basic, ok := goType.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+		enc, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(enc), nil
+	}
+	if !json.Valid([]byte(value)) {
+		return "", fmt.Errorf("invalid JSON: %s", value)
+	}
+	return value, nil
+}
+
 type paramSpec struct {
 	name        string
 	description string
@@ -0,0 +1,110 @@
+package templates
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	. "github.com/dave/jennifer/jen" // nolint:revive,stylecheck
+)
+
+// isGoScalar returns whether the given named type should be registered as a
+// custom scalar, i.e. it's a basic type declared in the module that isn't an
+// enum, like `type Semver string`.
+func (ps *parseState) isGoScalar(named *types.Named) bool {
+	if named == nil {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Pkg() != ps.pkg.Types || ps.isDaggerGenerated(obj) || !obj.Exported() {
+		return false
+	}
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok || basic.Info()&(types.IsString|types.IsInteger|types.IsBoolean|types.IsFloat) == 0 {
+		return false
+	}
+	return !ps.isGoEnum(named)
+}
+
+func (ps *parseState) parseGoScalar(named *types.Named) (*parsedScalarType, error) {
+	spec := &parsedScalarType{
+		name:   named.Obj().Name(),
+		goType: named,
+	}
+
+	// get the comment above the type (if any)
+	astSpec, err := ps.astSpecForNamedType(named)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find decl for named type %s: %w", spec.name, err)
+	}
+	spec.doc = astSpec.Doc.Text()
+
+	return spec, nil
+}
+
+// parsedScalarType is a parsed custom scalar type
+type parsedScalarType struct {
+	name string
+	doc  string
+
+	goType *types.Named
+}
+
+var _ NamedParsedType = &parsedScalarType{}
+
+func (spec *parsedScalarType) TypeDefCode() (*Statement, error) {
+	withScalarArgsCode := []Code{
+		Lit(spec.name),
+	}
+	if spec.doc != "" {
+		withScalarArgsCode = append(withScalarArgsCode, Id("TypeDefWithScalarOpts").Values(
+			Id("Description").Op(":").Lit(strings.TrimSpace(spec.doc)),
+		))
+	}
+	return Qual("dag", "TypeDef").Call().Dot("WithScalar").Call(withScalarArgsCode...), nil
+}
+
+func (spec *parsedScalarType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedScalarType) GoSubTypes() []types.Type {
+	return nil
+}
+
+func (spec *parsedScalarType) Name() string {
+	return spec.name
+}
+
+// parsedScalarTypeReference is a parsed scalar type that is referred to just by name rather
+// than with the full type definition
+type parsedScalarTypeReference struct {
+	name   string
+	isPtr  bool
+	goType types.Type
+}
+
+var _ NamedParsedType = &parsedScalarTypeReference{}
+
+func (spec *parsedScalarTypeReference) TypeDefCode() (*Statement, error) {
+	def := Qual("dag", "TypeDef").Call().Dot("WithScalar").Call(
+		Lit(spec.name),
+	)
+	if spec.isPtr {
+		def = def.Dot("WithOptional").Call(Lit(true))
+	}
+	return def, nil
+}
+
+func (spec *parsedScalarTypeReference) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedScalarTypeReference) GoSubTypes() []types.Type {
+	// because this is a *reference* to a named type, we return the goType itself as a subtype too
+	return []types.Type{spec.goType}
+}
+
+func (spec *parsedScalarTypeReference) Name() string {
+	return spec.name
+}
@@ -60,6 +60,20 @@ func (ps *parseState) parseGoTypeReference(typ types.Type, named *types.Named, i
 		if t.Kind() == types.Invalid {
 			return nil, fmt.Errorf("invalid type: %+v", t)
 		}
+		if ps.isGoEnum(named) {
+			return &parsedEnumTypeReference{
+				name:   named.Obj().Name(),
+				isPtr:  isPtr,
+				goType: named,
+			}, nil
+		}
+		if ps.isGoScalar(named) {
+			return &parsedScalarTypeReference{
+				name:   named.Obj().Name(),
+				isPtr:  isPtr,
+				goType: named,
+			}, nil
+		}
 		parsedType := &parsedPrimitiveType{goType: t, isPtr: isPtr}
 		if named != nil {
 			parsedType.alias = named.Obj().Name()
@@ -118,6 +132,8 @@ func (spec *parsedPrimitiveType) TypeDefCode() (*Statement, error) {
 		kind = Id("IntegerKind")
 	case types.IsBoolean:
 		kind = Id("BooleanKind")
+	case types.IsFloat:
+		kind = Id("FloatKind")
 	default:
 		return nil, fmt.Errorf("unsupported basic type: %+v", spec.goType)
 	}
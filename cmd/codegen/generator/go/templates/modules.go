@@ -201,6 +201,37 @@ func (funcs goTemplateFuncs) moduleMainSrc() (string, error) {
 				// If the object has any extra sub-types (e.g. for function return
 				// values), add them to the list of types to process
 				nextTps = append(nextTps, ifaceTypeSpec.GoSubTypes()...)
+
+			case *types.Basic:
+				switch {
+				case ps.isGoEnum(named):
+					enumTypeSpec, err := ps.parseGoEnum(named)
+					if err != nil {
+						return "", err
+					}
+
+					// Add the enum to the module
+					enumTypeDefCode, err := enumTypeSpec.TypeDefCode()
+					if err != nil {
+						return "", fmt.Errorf("failed to generate type def code for %s: %w", obj.Name(), err)
+					}
+					createMod = dotLine(createMod, "WithEnum").Call(Add(Line(), enumTypeDefCode))
+					added[obj.Name()] = struct{}{}
+
+				case ps.isGoScalar(named):
+					scalarTypeSpec, err := ps.parseGoScalar(named)
+					if err != nil {
+						return "", err
+					}
+
+					// Add the scalar to the module
+					scalarTypeDefCode, err := scalarTypeSpec.TypeDefCode()
+					if err != nil {
+						return "", fmt.Errorf("failed to generate type def code for %s: %w", obj.Name(), err)
+					}
+					createMod = dotLine(createMod, "WithScalar").Call(Add(Line(), scalarTypeDefCode))
+					added[obj.Name()] = struct{}{}
+				}
 			}
 		}
 
@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"go/types"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -74,3 +75,65 @@ func TestParsePragmaComment(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultValueJSON(t *testing.T) {
+	modeType := types.NewNamed(types.NewTypeName(0, nil, "Mode", nil), types.Typ[types.String], nil)
+
+	tests := []struct {
+		name     string
+		goType   types.Type
+		value    string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "string",
+			goType:   types.Typ[types.String],
+			value:    "hello world",
+			expected: `"hello world"`,
+		},
+		{
+			name:     "named string",
+			goType:   modeType,
+			value:    "DEBUG",
+			expected: `"DEBUG"`,
+		},
+		{
+			name:     "int",
+			goType:   types.Typ[types.Int],
+			value:    "42",
+			expected: "42",
+		},
+		{
+			name:     "bool",
+			goType:   types.Typ[types.Bool],
+			value:    "true",
+			expected: "true",
+		},
+		{
+			name:     "string slice",
+			goType:   types.NewSlice(types.Typ[types.String]),
+			value:    `["a", "b"]`,
+			expected: `["a", "b"]`,
+		},
+		{
+			name:   "invalid",
+			goType: types.Typ[types.Int],
+			value:  "forty-two",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := defaultValueJSON(test.goType, test.value)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -42,9 +42,17 @@ func (g *TypeScriptGenerator) Generate(_ context.Context, schema *introspection.
 		})
 	}
 
+	// subscriptions are streamed by hand-written clients instead
+	types := make(introspection.Types, 0, len(schema.Types))
+	for _, t := range schema.Types {
+		if t.Name != schema.SubscriptionType.Name {
+			types = append(types, t)
+		}
+	}
+
 	tmpl := templates.New()
 	var b bytes.Buffer
-	err := tmpl.ExecuteTemplate(&b, "api", schema.Types)
+	err := tmpl.ExecuteTemplate(&b, "api", types)
 	if err != nil {
 		return nil, err
 	}
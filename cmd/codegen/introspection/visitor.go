@@ -51,6 +51,10 @@ func (v *Visitor) visit(kind TypeKind, ignore map[string]any) []*Type {
 			if strings.HasPrefix(t.Name, "__") {
 				continue
 			}
+			// subscriptions are streamed by hand-written clients instead
+			if t.Name == v.schema.SubscriptionType.Name {
+				continue
+			}
 			if ignore != nil {
 				if _, ok := ignore[t.Name]; ok {
 					continue
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dagger/dagger/engine/client"
+	"github.com/docker/go-units"
+	"github.com/juju/ansiterm/tabwriter"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	engineCmd.AddCommand(engineCacheCmd)
+	engineCacheCmd.AddCommand(engineCacheLsCmd)
+	engineCacheCmd.AddCommand(engineCacheRmCmd)
+}
+
+var engineCmd = &cobra.Command{
+	Use:   "engine",
+	Short: "Manage the Dagger Engine",
+}
+
+var engineCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache volumes of the Dagger Engine",
+}
+
+var engineCacheLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List cache volumes and the size of their contents",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			caches, err := engineClient.Dagger().CacheVolumes(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list cache volumes: %w", err)
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', tabwriter.DiscardEmptyColumns)
+			fmt.Fprintf(tw, "%s\t%s\n",
+				termenv.String("Key").Bold(),
+				termenv.String("Size").Bold(),
+			)
+			for _, cache := range caches {
+				key, err := cache.Key(ctx)
+				if err != nil {
+					return err
+				}
+				size, err := cache.Size(ctx)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(tw, "%s\t%s\n", key, units.BytesSize(float64(size)))
+			}
+			return tw.Flush()
+		})
+	},
+}
+
+var engineCacheRmCmd = &cobra.Command{
+	Use:   "rm KEY...",
+	Short: "Clear the contents of cache volumes",
+	Long: `Clear the contents of cache volumes.
+
+Contents currently mounted by a running container are kept.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, keys []string) error {
+		ctx := cmd.Context()
+
+		return withEngineAndTUI(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			dag := engineClient.Dagger()
+			for _, key := range keys {
+				if _, err := dag.CacheVolume(key).Prune(ctx); err != nil {
+					return fmt.Errorf("failed to remove cache volume %q: %w", key, err)
+				}
+			}
+			return nil
+		})
+	},
+}
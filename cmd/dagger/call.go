@@ -19,6 +19,10 @@ var callCmd = &FuncCommand{
 	Short: "Call a module function",
 	Long: `Call a module function and print the result
 
+If no module is specified and none is found in the current directory,
+functions are called on the core API instead (for example,
+*dagger call container from --address alpine with-exec --args echo,hi stdout*).
+
 If the last argument is either Container, Directory, or File, the pipeline
 will be evaluated (the result of calling *sync*) without presenting any output.
 Providing the --output option (shorthand: -o) is equivalent to calling *export*
@@ -29,6 +33,7 @@ appending it to the end of the command (for example, *stdout*, *entries*, or
 	Init: func(cmd *cobra.Command) {
 		cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Present result as JSON")
 		cmd.PersistentFlags().StringVarP(&outputPath, "output", "o", "", "Path in the host to save the result to")
+		addWatchFlag(cmd.PersistentFlags())
 	},
 	OnSelectObjectLeaf: func(c *FuncCommand, name string) error {
 		switch name {
@@ -66,6 +71,9 @@ appending it to the end of the command (for example, *stdout*, *entries*, or
 		if outputPath != "" {
 			return fmt.Errorf("running shell with --output is not supported")
 		}
+		if watchMode {
+			return fmt.Errorf("running shell with --watch is not supported")
+		}
 		return nil
 	},
 	AfterResponse: func(c *FuncCommand, cmd *cobra.Command, modType *modTypeDef, response any) error {
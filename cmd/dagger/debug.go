@@ -3,15 +3,76 @@ package main
 import (
 	"expvar"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"runtime"
+	"strings"
 
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
 	"golang.org/x/net/trace"
 )
 
+var debugIDRefOnly bool
+
+func init() {
+	debugCmd.AddCommand(debugIDCmd)
+	debugIDCmd.Flags().BoolVar(&debugIDRefOnly, "ref", false, "Only print the short reference to the ID")
+}
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Debug Dagger internals",
+}
+
+var debugIDCmd = &cobra.Command{
+	Use:   "id [ID]",
+	Short: "Show the pipeline that an ID refers to",
+	Long: `Show the pipeline that an ID refers to, along with the short reference
+that can be passed in its stead to the session that returned it, for as long as
+the session remembers it.
+
+The ID is read from stdin if not given as an argument.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var enc string
+		if len(args) > 0 {
+			enc = args[0]
+		} else {
+			bytes, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			enc = strings.TrimSpace(string(bytes))
+		}
+		if idproto.IsRef(enc) {
+			return fmt.Errorf("references can only be resolved by the session that returned them; pass the full ID instead")
+		}
+
+		var id idproto.ID
+		if err := id.Decode(enc); err != nil {
+			return err
+		}
+		ref, err := id.Ref()
+		if err != nil {
+			return err
+		}
+		if debugIDRefOnly {
+			fmt.Fprintln(cmd.OutOrStdout(), ref)
+			return nil
+		}
+
+		out := termenv.NewOutput(cmd.OutOrStdout(), termenv.WithProfile(termenv.EnvColorProfile()))
+		fmt.Fprintln(out, out.String(ref).Faint())
+		return idtui.DebugRenderID(out, nil, &id, 0)
+	},
+}
+
 func setupDebugHandlers(addr string) error {
 	m := http.NewServeMux()
 	m.Handle("/debug/vars", expvar.Handler())
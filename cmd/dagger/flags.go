@@ -447,6 +447,168 @@ func (v *portForwardValue) Get(_ context.Context, c *dagger.Client) (any, error)
 	}, nil
 }
 
+// scalarValue is a pflag.Value for a custom scalar, which is passed
+// through to the API as a string.
+type scalarValue struct {
+	name  string
+	value string
+}
+
+func (v *scalarValue) Type() string {
+	return v.name
+}
+
+func (v *scalarValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *scalarValue) String() string {
+	return v.value
+}
+
+func (v *scalarValue) Get(_ context.Context, _ *dagger.Client) (any, error) {
+	return v.value, nil
+}
+
+// scalarSliceValue is a pflag.Value that builds a slice of custom scalar
+// values.
+type scalarSliceValue struct {
+	name  string
+	value []string
+}
+
+func (v *scalarSliceValue) Type() string {
+	return v.name + "Slice"
+}
+
+func (v *scalarSliceValue) Set(s string) error {
+	ss, err := readAsCSV(s)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	for _, s := range ss {
+		v.value = append(v.value, strings.TrimSpace(s))
+	}
+	return nil
+}
+
+func (v *scalarSliceValue) String() string {
+	out, _ := writeAsCSV(v.value)
+	return "[" + out + "]"
+}
+
+func (v *scalarSliceValue) Get(_ context.Context, _ *dagger.Client) (any, error) {
+	return v.value, nil
+}
+
+// enumValue is a pflag.Value that only accepts the values of a
+// dagger.EnumTypeDef.
+type enumValue struct {
+	enum  *modEnum
+	value string
+}
+
+func newEnumValue(enum *modEnum, defaultValue string) *enumValue {
+	return &enumValue{
+		enum:  enum,
+		value: defaultValue,
+	}
+}
+
+func (v *enumValue) Type() string {
+	return v.enum.Name
+}
+
+func (v *enumValue) Set(s string) error {
+	for _, name := range v.enum.ValueNames() {
+		if s == name {
+			v.value = s
+			return nil
+		}
+	}
+	return fmt.Errorf("value should be one of %s", strings.Join(v.enum.ValueNames(), ","))
+}
+
+func (v *enumValue) String() string {
+	return v.value
+}
+
+func (v *enumValue) Get(_ context.Context, _ *dagger.Client) (any, error) {
+	if v.value == "" {
+		return nil, nil
+	}
+	return enumLiteral(v.value), nil
+}
+
+// enumLiteral is an enum value that the query builder renders unquoted.
+type enumLiteral string
+
+func (enumLiteral) IsEnum() {}
+
+// enumSliceValue is a pflag.Value that builds a slice of enum values.
+type enumSliceValue struct {
+	enum  *modEnum
+	value []*enumValue
+}
+
+func newEnumSliceValue(enum *modEnum, defaultValues []string) *enumSliceValue {
+	v := &enumSliceValue{enum: enum}
+	for _, s := range defaultValues {
+		v.value = append(v.value, newEnumValue(enum, s))
+	}
+	return v
+}
+
+func (v *enumSliceValue) Type() string {
+	return v.enum.Name + "Slice"
+}
+
+func (v *enumSliceValue) String() string {
+	ss := []string{}
+	for _, v := range v.value {
+		ss = append(ss, v.String())
+	}
+	out, _ := writeAsCSV(ss)
+	return "[" + out + "]"
+}
+
+func (v *enumSliceValue) Get(ctx context.Context, c *dagger.Client) (any, error) {
+	out := make([]any, len(v.value))
+	for i, v := range v.value {
+		outV, err := v.Get(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = outV
+	}
+	return out, nil
+}
+
+func (v *enumSliceValue) Set(s string) error {
+	ss, err := readAsCSV(s)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	for _, s := range ss {
+		val := newEnumValue(v.enum, "")
+		if err := val.Set(strings.TrimSpace(s)); err != nil {
+			return err
+		}
+		v.value = append(v.value, val)
+	}
+	return nil
+}
+
+// enumUsage appends the possible values of an enum to a flag's usage.
+func enumUsage(usage string, enum *modEnum) string {
+	values := fmt.Sprintf("(possible values: %s)", strings.Join(enum.ValueNames(), ", "))
+	if usage == "" {
+		return values
+	}
+	return usage + " " + values
+}
+
 // AddFlag adds a flag appropriate for the argument type. Should return a
 // pointer to the value.
 func (r *modFunctionArg) AddFlag(flags *pflag.FlagSet, dag *dagger.Client) (any, error) {
@@ -470,6 +632,26 @@ func (r *modFunctionArg) AddFlag(flags *pflag.FlagSet, dag *dagger.Client) (any,
 		val, _ := getDefaultValue[bool](r)
 		return flags.Bool(name, val, usage), nil
 
+	case dagger.FloatKind:
+		val, _ := getDefaultValue[float64](r)
+		return flags.Float64(name, val, usage), nil
+
+	case dagger.ScalarKind:
+		defVal, _ := getDefaultValue[string](r)
+		val := &scalarValue{name: r.TypeDef.AsScalar.Name, value: defVal}
+		flags.Var(val, name, usage)
+		return val, nil
+
+	case dagger.EnumKind:
+		enum := r.TypeDef.AsEnum
+		if enum == nil || len(enum.Values) == 0 {
+			return nil, fmt.Errorf("missing values for enum type of flag: %s", name)
+		}
+		defVal, _ := getDefaultValue[string](r)
+		val := newEnumValue(enum, defVal)
+		flags.Var(val, name, enumUsage(usage, enum))
+		return val, nil
+
 	case dagger.ObjectKind:
 		objName := r.TypeDef.AsObject.Name
 
@@ -508,6 +690,26 @@ func (r *modFunctionArg) AddFlag(flags *pflag.FlagSet, dag *dagger.Client) (any,
 			val, _ := getDefaultValue[[]bool](r)
 			return flags.BoolSlice(name, val, usage), nil
 
+		case dagger.FloatKind:
+			val, _ := getDefaultValue[[]float64](r)
+			return flags.Float64Slice(name, val, usage), nil
+
+		case dagger.ScalarKind:
+			defVal, _ := getDefaultValue[[]string](r)
+			val := &scalarSliceValue{name: elementType.AsScalar.Name, value: defVal}
+			flags.Var(val, name, usage)
+			return val, nil
+
+		case dagger.EnumKind:
+			enum := elementType.AsEnum
+			if enum == nil || len(enum.Values) == 0 {
+				return nil, fmt.Errorf("missing values for list of enum type of flag: %s", name)
+			}
+			defVal, _ := getDefaultValue[[]string](r)
+			val := newEnumSliceValue(enum, defVal)
+			flags.Var(val, name, enumUsage(usage, enum))
+			return val, nil
+
 		case dagger.ObjectKind:
 			objName := elementType.AsObject.Name
 
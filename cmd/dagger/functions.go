@@ -27,6 +27,7 @@ const (
 	Service     string = "Service"
 	Terminal    string = "Terminal"
 	PortForward string = "PortForward"
+	QueryType   string = "Query"
 )
 
 var funcGroup = &cobra.Group{
@@ -269,7 +270,12 @@ func (fc *FuncCommand) Command() *cobra.Command {
 
 			// Between PreRunE and RunE, flags are validated.
 			RunE: func(c *cobra.Command, a []string) error {
-				return withEngineAndTUI(c.Context(), client.Params{}, func(ctx context.Context, engineClient *client.Client) (rerr error) {
+				var watcher hostWatcher
+				var params client.Params
+				if watchMode {
+					params.LocalImportCallback = watcher.Record
+				}
+				return withEngineAndTUI(c.Context(), params, func(ctx context.Context, engineClient *client.Client) (rerr error) {
 					fc.c = engineClient
 
 					// withEngineAndTUI changes the context.
@@ -280,6 +286,9 @@ func (fc *FuncCommand) Command() *cobra.Command {
 					// sub-command.
 					c.SilenceErrors = true
 
+					if watchMode {
+						return fc.watch(c, a, &watcher)
+					}
 					return fc.execute(c, a)
 				})
 			},
@@ -292,6 +301,46 @@ func (fc *FuncCommand) Command() *cobra.Command {
 	return fc.cmd
 }
 
+// watch executes the command, then executes it again in the same session
+// every time the host directories it loaded change.
+//
+// The module is reloaded every time, so each iteration after the first one
+// starts from a fresh copy of the command without any of the sub-commands or
+// flags that were added for the previous module.
+func (fc *FuncCommand) watch(c *cobra.Command, a []string, watcher *hostWatcher) error {
+	next, nextCmd := fc, c
+	return watcher.Loop(c.Context(), func(ctx context.Context) error {
+		if nextCmd == nil {
+			next = &FuncCommand{
+				Name:               fc.Name,
+				Aliases:            fc.Aliases,
+				Short:              fc.Short,
+				Long:               fc.Long,
+				Example:            fc.Example,
+				Init:               fc.Init,
+				Execute:            fc.Execute,
+				BeforeParse:        fc.BeforeParse,
+				OnSelectObjectLeaf: fc.OnSelectObjectLeaf,
+				BeforeRequest:      fc.BeforeRequest,
+				AfterResponse:      fc.AfterResponse,
+				c:                  fc.c,
+			}
+			nextCmd = next.Command()
+			nextCmd.PersistentFlags().AddFlagSet(c.PersistentFlags())
+			nextCmd.PersistentFlags().AddFlagSet(c.InheritedFlags())
+			nextCmd.SilenceErrors = true
+			nextCmd.SetContext(ctx)
+			if err := nextCmd.PreRunE(nextCmd, a); err != nil {
+				return err
+			}
+		}
+		nextCmd.SetContext(ctx)
+		err := next.execute(nextCmd, a)
+		nextCmd = nil
+		return err
+	})
+}
+
 func (fc *FuncCommand) execute(c *cobra.Command, a []string) (rerr error) {
 	ctx := c.Context()
 	rec := progrock.FromContext(ctx)
@@ -299,7 +348,7 @@ func (fc *FuncCommand) execute(c *cobra.Command, a []string) (rerr error) {
 	// NB: Don't print full os.Args in Vertex name because we don't know which
 	// flags hold a secret value yet and don't want to risk exposing them.
 	// We'll print just the command path when we have the leaf command.
-	loader := rec.Vertex("cmd-func-loader", "load "+c.Name())
+	loader := rec.Vertex(watchVertexID(ctx, "cmd-func-loader"), "load "+c.Name())
 	setCmdOutput(c, loader)
 
 	cmd, flags, err := fc.load(c, a, loader)
@@ -308,7 +357,7 @@ func (fc *FuncCommand) execute(c *cobra.Command, a []string) (rerr error) {
 		return err
 	}
 
-	vtx := rec.Vertex("cmd-func-exec", cmd.CommandPath(), progrock.Focused())
+	vtx := rec.Vertex(watchVertexID(ctx, "cmd-func-exec"), cmd.CommandPath(), progrock.Focused())
 	setCmdOutput(c, vtx)
 
 	defer func() {
@@ -386,9 +435,8 @@ func (fc *FuncCommand) load(c *cobra.Command, a []string, vtx *progrock.VertexRe
 	if err != nil {
 		return nil, nil, err
 	}
-	if mod == nil {
-		return nil, nil, fmt.Errorf("no module specified and no default module found in current directory")
-	}
+	// If there's no module, functions are called on the core API's Query
+	// type instead, which is loaded along with the other core types.
 
 	load = vtx.Task("loading objects")
 	modDef, err := loadModTypeDefs(ctx, dag, mod)
@@ -415,7 +463,7 @@ func (fc *FuncCommand) load(c *cobra.Command, a []string, vtx *progrock.VertexRe
 			return nil, nil, err
 		}
 		fc.selectFunc(obj.Name, obj.Constructor, c, dag)
-	} else {
+	} else if mod != nil {
 		fc.Select(obj.Name)
 	}
 
@@ -581,9 +629,14 @@ func (fc *FuncCommand) addArgsForFunction(cmd *cobra.Command, cmdArgs []string,
 		if err != nil {
 			return err
 		}
-		if !arg.TypeDef.Optional {
+		if arg.IsRequired() {
 			cmd.MarkFlagRequired(arg.FlagName())
 		}
+		if enum := arg.EnumTypeDef(); enum != nil {
+			cmd.RegisterFlagCompletionFunc(arg.FlagName(), func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+				return enum.ValueNames(), cobra.ShellCompDirectiveNoFileComp
+			})
+		}
 	}
 
 	if fc.BeforeParse != nil {
@@ -617,32 +670,20 @@ func (fc *FuncCommand) selectFunc(selectName string, fn *modFunction, cmd *cobra
 	fc.Select(selectName)
 
 	for _, arg := range fn.Args {
-		var val any
-
 		flag := cmd.Flags().Lookup(arg.FlagName())
 		if flag == nil {
 			return fmt.Errorf("no flag for %q", arg.FlagName())
 		}
 
-		// Don't send optional arguments that weren't set.
-		if arg.TypeDef.Optional && !flag.Changed {
+		// Don't send optional arguments that weren't set, or arguments
+		// with a default value, which the API will apply itself.
+		if !arg.IsRequired() && !flag.Changed {
 			continue
 		}
 
-		val = flag.Value
-
-		switch v := val.(type) {
-		case DaggerValue:
-			obj, err := v.Get(cmd.Context(), dag)
-			if err != nil {
-				return fmt.Errorf("failed to get value for argument %q: %w", arg.Name, err)
-			}
-			if obj == nil {
-				return fmt.Errorf("no value for argument: %s", arg.Name)
-			}
-			val = obj
-		case pflag.SliceValue:
-			val = v.GetSlice()
+		val, err := getFlagValue(cmd.Context(), flag, dag)
+		if err != nil {
+			return fmt.Errorf("failed to get value for argument %q: %w", arg.Name, err)
 		}
 
 		fc.Arg(arg.Name, val)
@@ -651,6 +692,25 @@ func (fc *FuncCommand) selectFunc(selectName string, fn *modFunction, cmd *cobra
 	return nil
 }
 
+// getFlagValue returns the value of a flag to use as an argument in a query.
+func getFlagValue(ctx context.Context, flag *pflag.Flag, dag *dagger.Client) (any, error) {
+	switch v := flag.Value.(type) {
+	case DaggerValue:
+		obj, err := v.Get(ctx, dag)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil {
+			return nil, fmt.Errorf("no value for flag: %s", flag.Name)
+		}
+		return obj, nil
+	case pflag.SliceValue:
+		return v.GetSlice(), nil
+	default:
+		return v, nil
+	}
+}
+
 func (fc *FuncCommand) Select(name string) {
 	if fc.q == nil {
 		fc.q = querybuilder.Query()
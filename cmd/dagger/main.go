@@ -39,6 +39,9 @@ func init() {
 		runCmd,
 		moduleCmd,
 		sessionCmd(),
+		shellCmd,
+		engineCmd,
+		debugCmd,
 	)
 
 	funcCmds.AddParent(rootCmd)
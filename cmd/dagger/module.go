@@ -487,6 +487,9 @@ func loadMod(ctx context.Context, c *dagger.Client) (*dagger.Module, error) {
 }
 
 // loadModTypeDefs loads the objects defined by the given module in an easier to use data structure.
+//
+// If mod is nil, only the core API's types are loaded, and the main object is
+// the root Query type.
 func loadModTypeDefs(ctx context.Context, dag *dagger.Client, mod *dagger.Module) (*moduleDef, error) {
 	var res struct {
 		TypeDefs []*modTypeDef
@@ -505,6 +508,12 @@ fragment TypeDefRefParts on TypeDef {
 	asInput {
 			name
 	}
+	asEnum {
+			name
+	}
+	asScalar {
+			name
+	}
 	asList {
 			elementTypeDef {
 					kind
@@ -517,6 +526,12 @@ fragment TypeDefRefParts on TypeDef {
 					asInput {
 							name
 					}
+					asEnum {
+							name
+					}
+					asScalar {
+							name
+					}
 			}
 	}
 }
@@ -545,7 +560,7 @@ fragment FieldParts on FieldTypeDef {
 	}
 }
 
-query TypeDefs($module: ModuleID!) {
+query TypeDefs {
 	typeDefs: currentTypeDefs {
 		kind
 		optional
@@ -575,15 +590,20 @@ query TypeDefs($module: ModuleID!) {
 				...FieldParts
 			}
 		}
+		asEnum {
+			name
+			sourceModuleName
+			values {
+				name
+				description
+			}
+		}
 	}
 }
 `
 
 	err := dag.Do(ctx, &dagger.Request{
 		Query: query,
-		Variables: map[string]interface{}{
-			"module": mod,
-		},
 	}, &dagger.Response{
 		Data: &res,
 	})
@@ -591,12 +611,13 @@ query TypeDefs($module: ModuleID!) {
 		return nil, fmt.Errorf("query module objects: %w", err)
 	}
 
-	name, err := mod.Name(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("get module name: %w", err)
+	modDef := &moduleDef{}
+	if mod != nil {
+		modDef.Name, err = mod.Name(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get module name: %w", err)
+		}
 	}
-
-	modDef := &moduleDef{Name: name}
 	for _, typeDef := range res.TypeDefs {
 		switch typeDef.Kind {
 		case dagger.ObjectKind:
@@ -605,6 +626,8 @@ query TypeDefs($module: ModuleID!) {
 			modDef.Interfaces = append(modDef.Interfaces, typeDef)
 		case dagger.InputKind:
 			modDef.Inputs = append(modDef.Inputs, typeDef)
+		case dagger.EnumKind:
+			modDef.Enums = append(modDef.Enums, typeDef)
 		}
 	}
 	return modDef, nil
@@ -616,6 +639,7 @@ type moduleDef struct {
 	Objects    []*modTypeDef
 	Interfaces []*modTypeDef
 	Inputs     []*modTypeDef
+	Enums      []*modTypeDef
 }
 
 func (m *moduleDef) AsFunctionProviders() []functionProvider {
@@ -660,6 +684,16 @@ func (m *moduleDef) AsInputs() []*modInput {
 	return defs
 }
 
+func (m *moduleDef) AsEnums() []*modEnum {
+	var defs []*modEnum
+	for _, typeDef := range m.Enums {
+		if typeDef.AsEnum != nil {
+			defs = append(defs, typeDef.AsEnum)
+		}
+	}
+	return defs
+}
+
 // GetObject retrieves a saved object type definition from the module.
 func (m *moduleDef) GetObject(name string) *modObject {
 	for _, obj := range m.AsObjects() {
@@ -704,7 +738,23 @@ func (m *moduleDef) GetInput(name string) *modInput {
 	return nil
 }
 
+// GetEnum retrieves a saved enum type definition from the module.
+func (m *moduleDef) GetEnum(name string) *modEnum {
+	for _, enum := range m.AsEnums() {
+		// Normalize name in case an SDK uses a different convention for enum names.
+		if gqlObjectName(enum.Name) == gqlObjectName(name) {
+			return enum
+		}
+	}
+	return nil
+}
+
+// GetMainObject returns the module's main object, or the core API's root
+// Query type if no module was loaded.
 func (m *moduleDef) GetMainObject() *modObject {
+	if m.Name == "" {
+		return m.GetObject(QueryType)
+	}
 	return m.GetObject(m.Name)
 }
 
@@ -730,6 +780,12 @@ func (m *moduleDef) LoadTypeDef(typeDef *modTypeDef) {
 			typeDef.AsInput = input
 		}
 	}
+	if typeDef.AsEnum != nil && typeDef.AsEnum.Values == nil {
+		enum := m.GetEnum(typeDef.AsEnum.Name)
+		if enum != nil {
+			typeDef.AsEnum = enum
+		}
+	}
 	if typeDef.AsList != nil {
 		m.LoadTypeDef(typeDef.AsList.ElementTypeDef)
 	}
@@ -742,6 +798,8 @@ type modTypeDef struct {
 	AsObject    *modObject
 	AsInterface *modInterface
 	AsInput     *modInput
+	AsEnum      *modEnum
+	AsScalar    *modScalar
 	AsList      *modList
 }
 
@@ -850,6 +908,33 @@ type modInput struct {
 	Fields []*modField
 }
 
+// modScalar is a representation of dagger.ScalarTypeDef.
+type modScalar struct {
+	Name string
+}
+
+// modEnum is a representation of dagger.EnumTypeDef.
+type modEnum struct {
+	Name             string
+	Values           []*modEnumValue
+	SourceModuleName string
+}
+
+// ValueNames returns the names of the enum's values.
+func (e *modEnum) ValueNames() []string {
+	names := make([]string, 0, len(e.Values))
+	for _, v := range e.Values {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// modEnumValue is a representation of dagger.EnumValueTypeDef.
+type modEnumValue struct {
+	Name        string
+	Description string
+}
+
 // modList is a representation of dagger.ListTypeDef.
 type modList struct {
 	ElementTypeDef *modTypeDef
@@ -887,6 +972,22 @@ func (r *modFunctionArg) FlagName() string {
 	return r.flagName
 }
 
+// IsRequired returns true if the argument must be set by the caller, i.e.
+// it's neither optional nor has a default value.
+func (r *modFunctionArg) IsRequired() bool {
+	return !r.TypeDef.Optional && r.DefaultValue == ""
+}
+
+// EnumTypeDef returns the enum type of the argument, or of its list's
+// elements, if any.
+func (r *modFunctionArg) EnumTypeDef() *modEnum {
+	typeDef := r.TypeDef
+	if typeDef.AsList != nil {
+		typeDef = typeDef.AsList.ElementTypeDef
+	}
+	return typeDef.AsEnum
+}
+
 func getDefaultValue[T any](r *modFunctionArg) (T, error) {
 	var val T
 	err := json.Unmarshal([]byte(r.DefaultValue), &val)
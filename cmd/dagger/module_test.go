@@ -4,7 +4,9 @@ import (
 	"net/url"
 	"testing"
 
+	"dagger.io/dagger"
 	"github.com/moby/buildkit/util/gitutil"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 )
 
@@ -222,3 +224,81 @@ func TestParseGit(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionArgDefaultValues(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		typeDef      *modTypeDef
+		defaultValue dagger.JSON
+		required     bool
+		usage        string
+	}{
+		{
+			name:     "required",
+			typeDef:  &modTypeDef{Kind: dagger.StringKind},
+			required: true,
+			usage:    "--required string",
+		},
+		{
+			name:    "optional",
+			typeDef: &modTypeDef{Kind: dagger.StringKind, Optional: true},
+			usage:   "--optional string",
+		},
+		{
+			name:         "greeting",
+			typeDef:      &modTypeDef{Kind: dagger.StringKind},
+			defaultValue: `"hello"`,
+			usage:        `--greeting string    (default "hello")`,
+		},
+		{
+			name:         "count",
+			typeDef:      &modTypeDef{Kind: dagger.IntegerKind},
+			defaultValue: `3`,
+			usage:        "--count int    (default 3)",
+		},
+		{
+			name:         "ratio",
+			typeDef:      &modTypeDef{Kind: dagger.FloatKind},
+			defaultValue: `0.5`,
+			usage:        "--ratio float    (default 0.5)",
+		},
+		{
+			name: "tags",
+			typeDef: &modTypeDef{Kind: dagger.ListKind, AsList: &modList{
+				ElementTypeDef: &modTypeDef{Kind: dagger.StringKind},
+			}},
+			defaultValue: `["a", "b"]`,
+			usage:        "--tags strings    (default [a,b])",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			arg := &modFunctionArg{
+				Name:         tc.name,
+				TypeDef:      tc.typeDef,
+				DefaultValue: tc.defaultValue,
+			}
+			require.Equal(t, tc.required, arg.IsRequired())
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			_, err := arg.AddFlag(flags, nil)
+			require.NoError(t, err)
+			require.Contains(t, flags.FlagUsages(), tc.usage)
+		})
+	}
+}
+
+func TestModuleDefMainObject(t *testing.T) {
+	objects := []*modTypeDef{
+		{Kind: dagger.ObjectKind, AsObject: &modObject{Name: "Query"}},
+		{Kind: dagger.ObjectKind, AsObject: &modObject{Name: "Container"}},
+		{Kind: dagger.ObjectKind, AsObject: &modObject{Name: "MyModule"}},
+	}
+
+	modDef := &moduleDef{Name: "my-module", Objects: objects}
+	require.Equal(t, "MyModule", modDef.GetMainObject().Name)
+
+	// without a module, functions are called on the core API
+	coreDef := &moduleDef{Objects: objects}
+	require.Equal(t, "Query", coreDef.GetMainObject().Name)
+}
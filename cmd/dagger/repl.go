@@ -0,0 +1,750 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"dagger.io/dagger"
+	"dagger.io/dagger/querybuilder"
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/dagger/dagger/dagql/idtui"
+	"github.com/dagger/dagger/engine/client"
+	"github.com/google/shlex"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const shellPrompt = "dagger> "
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Interactively chain calls to the Dagger API",
+	Long: `Start an interactive shell to chain calls to the Dagger API.
+
+Each line is a pipeline of function calls separated by "|", chained onto
+the result of the previous line, or called from the root of the API if the
+previous result has no such function. Required arguments can be passed
+positionally and optional ones as flags, like with *dagger call*:
+
+    dagger> container
+    dagger> from alpine | with-exec apk add git | directory /usr
+
+The result of a line can be saved to a variable, and used to start a
+pipeline or as an argument:
+
+    dagger> src = host | directory .
+    dagger> container | from golang | with-directory /src $src
+
+Lines starting with "." are shell commands, see ".help".
+`,
+	Hidden: true, // for now, remove once we're ready for primetime
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// The shell reads from and writes to the terminal directly, so
+		// progress can't be rendered at the same time.
+		silent = true
+
+		return withEngineAndTUI(cmd.Context(), client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			dag := engineClient.Dagger()
+
+			modDef, err := loadModTypeDefs(ctx, dag, nil)
+			if err != nil {
+				return err
+			}
+
+			sh := &shellSession{
+				dag:  dag,
+				mod:  modDef,
+				vars: map[string]*shellValue{},
+			}
+			return sh.Run(ctx, os.Stdin, os.Stdout)
+		})
+	},
+}
+
+// shellSession is the state of a `dagger shell` session.
+type shellSession struct {
+	dag *dagger.Client
+	mod *moduleDef
+
+	// current is the result of the last line, which the next line is chained
+	// onto. If nil, the next line starts from the root Query type.
+	current *shellValue
+
+	// vars are the results saved with `name = ...`.
+	vars map[string]*shellValue
+
+	out io.Writer
+}
+
+// shellValue is an object resulting from a line in the shell.
+type shellValue struct {
+	TypeName string
+	ID       *idproto.ID
+}
+
+// shellLine is a parsed line of input in the shell.
+type shellLine struct {
+	// Assign is the name of the variable to save the result to, if any.
+	Assign string
+
+	// From is the name of the variable to start the pipeline from, if any.
+	From string
+
+	// Calls are the functions to chain, each being the name of the function
+	// followed by its arguments.
+	Calls [][]string
+}
+
+// parseShellLine splits a line into its variable assignment and the
+// pipeline of calls.
+func parseShellLine(line string) (*shellLine, error) {
+	words, err := shlex.Split(line)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &shellLine{}
+
+	if len(words) >= 2 && words[1] == "=" {
+		if !isShellVarName(words[0]) {
+			return nil, fmt.Errorf("invalid variable name %q", words[0])
+		}
+		parsed.Assign = words[0]
+		words = words[2:]
+	}
+
+	call := []string{}
+	for _, word := range words {
+		if word != "|" {
+			call = append(call, word)
+			continue
+		}
+		if len(call) == 0 {
+			return nil, fmt.Errorf("unexpected \"|\"")
+		}
+		parsed.Calls = append(parsed.Calls, call)
+		call = []string{}
+	}
+	if len(call) > 0 {
+		parsed.Calls = append(parsed.Calls, call)
+	} else if len(parsed.Calls) > 0 {
+		return nil, fmt.Errorf("unexpected \"|\" at end of line")
+	}
+
+	if len(parsed.Calls) > 0 && len(parsed.Calls[0]) == 1 && strings.HasPrefix(parsed.Calls[0][0], "$") {
+		parsed.From = strings.TrimPrefix(parsed.Calls[0][0], "$")
+		parsed.Calls = parsed.Calls[1:]
+	}
+
+	if parsed.Assign != "" && parsed.From == "" && len(parsed.Calls) == 0 {
+		return nil, fmt.Errorf("missing value for variable %q", parsed.Assign)
+	}
+
+	return parsed, nil
+}
+
+func isShellVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Run reads lines from in until EOF or ".exit", writing results to out.
+//
+// If in is a terminal, lines are read with a prompt, history and completion.
+func (sh *shellSession) Run(ctx context.Context, in *os.File, out io.Writer) error {
+	if !term.IsTerminal(int(in.Fd())) {
+		sh.out = out
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			if err := sh.Eval(ctx, scanner.Text()); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+
+	oldState, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), oldState)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, out}, shellPrompt)
+	if width, height, err := term.GetSize(int(in.Fd())); err == nil {
+		t.SetSize(width, height)
+	}
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return sh.autoComplete(t, line, pos)
+	}
+	sh.out = t
+
+	for {
+		line, err := t.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := sh.Eval(ctx, line); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			fmt.Fprintln(t, "Error:", err)
+		}
+	}
+}
+
+// Eval evaluates a single line of input. It returns io.EOF if the session
+// should end.
+func (sh *shellSession) Eval(ctx context.Context, line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	if strings.HasPrefix(line, ".") {
+		return sh.builtin(line)
+	}
+
+	parsed, err := parseShellLine(line)
+	if err != nil {
+		return err
+	}
+
+	start := sh.current
+	if parsed.From != "" {
+		val, ok := sh.vars[parsed.From]
+		if !ok {
+			return fmt.Errorf("undefined variable %q", parsed.From)
+		}
+		start = val
+	} else if start != nil && len(parsed.Calls) > 0 && !sh.hasFunction(start.TypeName, parsed.Calls[0][0]) {
+		// not a function of the previous result, so start from the root
+		start = nil
+	}
+
+	result, err := sh.call(ctx, start, parsed.Calls)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		// a value was printed, nothing to chain onto
+		return nil
+	}
+
+	sh.current = result
+	if parsed.Assign != "" {
+		sh.vars[parsed.Assign] = result
+	}
+	return sh.render(result)
+}
+
+var shellBuiltins = []string{".help", ".vars", ".reset", ".exit"}
+
+func (sh *shellSession) builtin(line string) error {
+	args := strings.Fields(line)
+	switch args[0] {
+	case ".help":
+		fmt.Fprint(sh.out, `Each line chains function calls onto the result of the previous one:
+
+    [name =] [$var |] function [args...] [| function [args...]]...
+
+Commands:
+    .help     Show this help
+    .vars     List saved variables
+    .reset    Start the next line from the root of the API
+    .exit     Exit the shell
+`)
+	case ".vars":
+		names := make([]string, 0, len(sh.vars))
+		for name := range sh.vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(sh.out, "$%s: %s\n", name, sh.vars[name].TypeName)
+		}
+	case ".reset":
+		sh.current = nil
+	case ".exit":
+		return io.EOF
+	default:
+		return fmt.Errorf("unknown command %q, see .help", args[0])
+	}
+	return nil
+}
+
+// call chains the given calls onto start, or the root Query type if nil.
+//
+// If the pipeline results in an object, it's returned. Otherwise, the value
+// is printed and nil is returned.
+func (sh *shellSession) call(ctx context.Context, start *shellValue, calls [][]string) (*shellValue, error) {
+	if len(calls) == 0 {
+		return start, nil
+	}
+
+	q := querybuilder.Query()
+	typeName := QueryType
+	if start != nil {
+		id, err := start.ID.Encode()
+		if err != nil {
+			return nil, err
+		}
+		q = q.Select(fmt.Sprintf("load%sFromID", start.TypeName)).Arg("id", id)
+		typeName = start.TypeName
+	}
+
+	var returnType *modTypeDef
+	for i, call := range calls {
+		provider := sh.mod.GetFunctionProvider(typeName)
+		if provider == nil {
+			return nil, fmt.Errorf("type %q has no functions", typeName)
+		}
+		fn, err := provider.GetFunction(call[0])
+		if err != nil {
+			return nil, err
+		}
+		sh.mod.LoadTypeDef(fn.ReturnType)
+
+		q = q.Select(fn.Name)
+		args, err := sh.parseArgs(ctx, fn, call[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", call[0], err)
+		}
+		for _, arg := range fn.Args {
+			if val, ok := args[arg.Name]; ok {
+				q = q.Arg(arg.Name, val)
+			}
+		}
+
+		returnType = fn.ReturnType
+		typeName = returnType.Name()
+		if i < len(calls)-1 && returnType.AsFunctionProvider() == nil {
+			return nil, fmt.Errorf("%s: can't chain onto %s", call[0], returnType.Kind)
+		}
+	}
+
+	if returnType.AsList != nil && returnType.AsList.ElementTypeDef.AsFunctionProvider() != nil {
+		// objects can't be chained onto from a list, so just show them
+		var encoded []string
+		if err := q.Select("id").Bind(&encoded).Execute(ctx, sh.dag.GraphQLClient()); err != nil {
+			return nil, err
+		}
+		elemTypeName := returnType.AsList.ElementTypeDef.Name()
+		for _, enc := range encoded {
+			var id idproto.ID
+			if err := id.Decode(enc); err != nil {
+				return nil, err
+			}
+			if err := sh.render(&shellValue{TypeName: elemTypeName, ID: &id}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	if returnType.AsFunctionProvider() == nil {
+		var response any
+		if err := q.Bind(&response).Execute(ctx, sh.dag.GraphQLClient()); err != nil {
+			return nil, err
+		}
+		return nil, sh.print(response)
+	}
+
+	// evaluate the object if possible, so errors are shown right away
+	provider := sh.mod.GetFunctionProvider(typeName)
+	if provider != nil {
+		if _, err := provider.GetFunction("sync"); err == nil {
+			q = q.Select("sync")
+		} else {
+			q = q.Select("id")
+		}
+	} else {
+		q = q.Select("id")
+	}
+
+	var encoded string
+	if err := q.Bind(&encoded).Execute(ctx, sh.dag.GraphQLClient()); err != nil {
+		return nil, err
+	}
+	var id idproto.ID
+	if err := id.Decode(encoded); err != nil {
+		return nil, err
+	}
+	return &shellValue{TypeName: typeName, ID: &id}, nil
+}
+
+// parseArgs converts the words following a function name into its
+// arguments.
+//
+// Required arguments may be passed positionally, in order, with the last one
+// taking all the remaining words if it's a list. Any argument can be passed
+// as a flag, like in `dagger call`. Objects can be passed from a variable
+// with `$name`.
+func (sh *shellSession) parseArgs(ctx context.Context, fn *modFunction, words []string) (map[string]any, error) {
+	args := map[string]any{}
+
+	flags := pflag.NewFlagSet(fn.Name, pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	// stop at the first positional argument so that values like "-c" can be
+	// passed through to list arguments, e.g. with-exec sh -c "..."
+	flags.SetInterspersed(false)
+	flags.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		return pflag.NormalizedName(cliName(name))
+	})
+	argsByFlag := map[string]*modFunctionArg{}
+	var required []*modFunctionArg
+	for _, arg := range fn.Args {
+		sh.mod.LoadTypeDef(arg.TypeDef)
+		if _, err := arg.AddFlag(flags, sh.dag); err != nil {
+			return nil, err
+		}
+		argsByFlag[arg.FlagName()] = arg
+		if arg.IsRequired() {
+			required = append(required, arg)
+		}
+	}
+
+	// pull out variables given to flags, since they're not valid flag values
+	rest := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, "--") {
+			rest = append(rest, word)
+			continue
+		}
+		name, val, hasVal := strings.Cut(strings.TrimPrefix(word, "--"), "=")
+		if !hasVal && i+1 < len(words) {
+			val = words[i+1]
+		}
+		arg, ok := argsByFlag[cliName(name)]
+		if !ok || !strings.HasPrefix(val, "$") {
+			rest = append(rest, word)
+			continue
+		}
+		id, err := sh.varID(val)
+		if err != nil {
+			return nil, err
+		}
+		args[arg.Name] = id
+		if !hasVal {
+			i++
+		}
+	}
+
+	if err := flags.Parse(rest); err != nil {
+		return nil, err
+	}
+
+	positional := flags.Args()
+	for _, arg := range required {
+		if _, ok := args[arg.Name]; ok || flags.Changed(arg.FlagName()) {
+			continue
+		}
+		if len(positional) == 0 {
+			return nil, fmt.Errorf("missing argument %q", arg.FlagName())
+		}
+		if arg.TypeDef.Kind == dagger.ListKind && arg == required[len(required)-1] {
+			if err := sh.setListArg(args, flags, arg, positional); err != nil {
+				return nil, err
+			}
+			positional = nil
+			continue
+		}
+		val := positional[0]
+		positional = positional[1:]
+		if strings.HasPrefix(val, "$") {
+			id, err := sh.varID(val)
+			if err != nil {
+				return nil, err
+			}
+			args[arg.Name] = id
+			continue
+		}
+		if err := flags.Set(arg.FlagName(), val); err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", arg.FlagName(), err)
+		}
+	}
+	if len(positional) > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(positional, " "))
+	}
+
+	var err error
+	flags.Visit(func(flag *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		arg := argsByFlag[flag.Name]
+		if _, ok := args[arg.Name]; ok {
+			return
+		}
+		var val any
+		val, err = getFlagValue(ctx, flag, sh.dag)
+		if err != nil {
+			err = fmt.Errorf("invalid value for %q: %w", flag.Name, err)
+			return
+		}
+		args[arg.Name] = val
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// setListArg sets a list argument from positional words, each word being
+// an element of the list.
+func (sh *shellSession) setListArg(args map[string]any, flags *pflag.FlagSet, arg *modFunctionArg, words []string) error {
+	elem := arg.TypeDef.AsList.ElementTypeDef
+	if elem.Kind == dagger.StringKind {
+		// don't split strings on commas, like flags would
+		args[arg.Name] = words
+		return nil
+	}
+	if elem.AsFunctionProvider() != nil {
+		ids := make([]string, 0, len(words))
+		for _, word := range words {
+			id, err := sh.varID(word)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		args[arg.Name] = ids
+		return nil
+	}
+	for _, word := range words {
+		if err := flags.Set(arg.FlagName(), word); err != nil {
+			return fmt.Errorf("invalid value for %q: %w", arg.FlagName(), err)
+		}
+	}
+	return nil
+}
+
+// varID returns the encoded ID saved in the given `$name` variable.
+func (sh *shellSession) varID(ref string) (string, error) {
+	name := strings.TrimPrefix(ref, "$")
+	val, ok := sh.vars[name]
+	if !ok {
+		return "", fmt.Errorf("undefined variable %q", name)
+	}
+	return val.ID.Encode()
+}
+
+// render prints an object result as the chain of calls that produced it.
+func (sh *shellSession) render(val *shellValue) error {
+	out := termenv.NewOutput(sh.out, termenv.WithProfile(termenv.EnvColorProfile()))
+	return idtui.DebugRenderID(out, nil, val.ID, 0)
+}
+
+// print prints a non-object result.
+func (sh *shellSession) print(val any) error {
+	switch x := val.(type) {
+	case nil:
+		return nil
+	case string:
+		fmt.Fprint(sh.out, x)
+		if !strings.HasSuffix(x, "\n") {
+			fmt.Fprintln(sh.out)
+		}
+		return nil
+	case []any:
+		for _, v := range x {
+			if err := sh.print(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		enc := json.NewEncoder(sh.out)
+		enc.SetIndent("", "    ")
+		return enc.Encode(x)
+	default:
+		fmt.Fprintln(sh.out, x)
+		return nil
+	}
+}
+
+// autoComplete completes the word under the cursor, printing the candidates
+// if there's more than one.
+func (sh *shellSession) autoComplete(out io.Writer, line string, pos int) (string, int, bool) {
+	start, candidates := sh.complete(line[:pos])
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	word := line[start:pos]
+	completion := candidates[0]
+	if len(candidates) == 1 {
+		completion += " "
+	} else {
+		for _, c := range candidates[1:] {
+			completion = commonPrefix(completion, c)
+		}
+		if completion == word {
+			fmt.Fprintln(out, strings.Join(candidates, "  "))
+			return "", 0, false
+		}
+	}
+
+	newLine := line[:start] + completion + line[pos:]
+	return newLine, start + len(completion), true
+}
+
+// complete returns the candidates for the last word of the given input,
+// along with the position where that word starts.
+func (sh *shellSession) complete(input string) (int, []string) {
+	start := strings.LastIndexAny(input, " \t") + 1
+	word := input[start:]
+	before := strings.Fields(input[:start])
+
+	var candidates []string
+	switch {
+	case len(before) == 0 && strings.HasPrefix(word, "."):
+		candidates = shellBuiltins
+	case strings.HasPrefix(word, "$"):
+		for name := range sh.vars {
+			candidates = append(candidates, "$"+name)
+		}
+	default:
+		candidates = sh.completeCall(before, word)
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return start, matches
+}
+
+// completeCall returns the function names or flags that may follow the
+// given words, which make up the line so far.
+func (sh *shellSession) completeCall(words []string, word string) []string {
+	if len(words) >= 2 && words[1] == "=" {
+		words = words[2:]
+	}
+
+	// the first call may be on the previous result or on the root
+	typeNames := []string{QueryType}
+	if sh.current != nil {
+		typeNames = append(typeNames, sh.current.TypeName)
+	}
+
+	var call []string
+	for i, w := range words {
+		if w != "|" {
+			call = append(call, w)
+			continue
+		}
+		if i == 1 && strings.HasPrefix(call[0], "$") {
+			val, ok := sh.vars[strings.TrimPrefix(call[0], "$")]
+			if !ok {
+				return nil
+			}
+			typeNames = []string{val.TypeName}
+			call = nil
+			continue
+		}
+		fn := sh.lookupFunction(typeNames[len(typeNames)-1], call)
+		if fn == nil && len(typeNames) > 1 {
+			fn = sh.lookupFunction(typeNames[0], call)
+		}
+		if fn == nil {
+			return nil
+		}
+		typeNames = []string{fn.ReturnType.Name()}
+		call = nil
+	}
+
+	if len(call) == 0 {
+		var names []string
+		for _, typeName := range typeNames {
+			provider := sh.mod.GetFunctionProvider(typeName)
+			if provider == nil {
+				continue
+			}
+			for _, fn := range provider.GetFunctions() {
+				names = append(names, cliName(fn.Name))
+			}
+		}
+		return names
+	}
+
+	if strings.HasPrefix(word, "--") {
+		fn := sh.lookupFunction(typeNames[len(typeNames)-1], call)
+		if fn == nil && len(typeNames) > 1 {
+			fn = sh.lookupFunction(typeNames[0], call)
+		}
+		if fn == nil {
+			return nil
+		}
+		var flags []string
+		for _, arg := range fn.Args {
+			flags = append(flags, "--"+arg.FlagName())
+		}
+		return flags
+	}
+
+	return nil
+}
+
+func (sh *shellSession) hasFunction(typeName string, name string) bool {
+	return sh.lookupFunction(typeName, []string{name}) != nil
+}
+
+func (sh *shellSession) lookupFunction(typeName string, call []string) *modFunction {
+	if len(call) == 0 {
+		return nil
+	}
+	provider := sh.mod.GetFunctionProvider(typeName)
+	if provider == nil {
+		return nil
+	}
+	fn, err := provider.GetFunction(call[0])
+	if err != nil {
+		return nil
+	}
+	sh.mod.LoadTypeDef(fn.ReturnType)
+	return fn
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
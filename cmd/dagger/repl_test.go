@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShellLine(t *testing.T) {
+	for _, tc := range []struct {
+		line     string
+		expected *shellLine
+		err      string
+	}{
+		{
+			line: "container",
+			expected: &shellLine{
+				Calls: [][]string{{"container"}},
+			},
+		},
+		{
+			line: `from alpine | with-exec sh -c "echo a | b" | stdout`,
+			expected: &shellLine{
+				Calls: [][]string{
+					{"from", "alpine"},
+					{"with-exec", "sh", "-c", "echo a | b"},
+					{"stdout"},
+				},
+			},
+		},
+		{
+			line: "src = host | directory .",
+			expected: &shellLine{
+				Assign: "src",
+				Calls: [][]string{
+					{"host"},
+					{"directory", "."},
+				},
+			},
+		},
+		{
+			line: "ctr = $base | with-workdir /src",
+			expected: &shellLine{
+				Assign: "ctr",
+				From:   "base",
+				Calls: [][]string{
+					{"with-workdir", "/src"},
+				},
+			},
+		},
+		{
+			line: "$base",
+			expected: &shellLine{
+				From:  "base",
+				Calls: [][]string{},
+			},
+		},
+		{
+			line: "container | | stdout",
+			err:  `unexpected "|"`,
+		},
+		{
+			line: "container |",
+			err:  `unexpected "|" at end of line`,
+		},
+		{
+			line: "1x = container",
+			err:  `invalid variable name "1x"`,
+		},
+		{
+			line: "x =",
+			err:  `missing value for variable "x"`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.line, func(t *testing.T) {
+			parsed, err := parseShellLine(tc.line)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, parsed)
+		})
+	}
+}
+
+func testShellSession() *shellSession {
+	objRef := func(name string) *modTypeDef {
+		return &modTypeDef{Kind: dagger.ObjectKind, AsObject: &modObject{Name: name}}
+	}
+	stringType := &modTypeDef{Kind: dagger.StringKind}
+
+	return &shellSession{
+		mod: &moduleDef{
+			Objects: []*modTypeDef{
+				{Kind: dagger.ObjectKind, AsObject: &modObject{
+					Name: "Query",
+					Functions: []*modFunction{
+						{Name: "container", ReturnType: objRef("Container")},
+						{Name: "host", ReturnType: objRef("Host")},
+					},
+				}},
+				{Kind: dagger.ObjectKind, AsObject: &modObject{
+					Name: "Container",
+					Functions: []*modFunction{
+						{Name: "from", ReturnType: objRef("Container"), Args: []*modFunctionArg{
+							{Name: "address", TypeDef: stringType},
+						}},
+						{Name: "withExec", ReturnType: objRef("Container"), Args: []*modFunctionArg{
+							{Name: "args", TypeDef: &modTypeDef{Kind: dagger.ListKind, AsList: &modList{ElementTypeDef: stringType}}},
+							{Name: "skipEntrypoint", TypeDef: &modTypeDef{Kind: dagger.BooleanKind, Optional: true}},
+						}},
+						{Name: "withDirectory", ReturnType: objRef("Container"), Args: []*modFunctionArg{
+							{Name: "path", TypeDef: stringType},
+							{Name: "directory", TypeDef: objRef("Directory")},
+							{Name: "owner", TypeDef: &modTypeDef{Kind: dagger.StringKind, Optional: true}},
+						}},
+						{Name: "withWorkdir", ReturnType: objRef("Container"), Args: []*modFunctionArg{
+							{Name: "path", TypeDef: stringType},
+						}},
+						{Name: "stdout", ReturnType: stringType},
+					},
+				}},
+				{Kind: dagger.ObjectKind, AsObject: &modObject{
+					Name: "Host",
+					Functions: []*modFunction{
+						{Name: "directory", ReturnType: objRef("Directory"), Args: []*modFunctionArg{
+							{Name: "path", TypeDef: stringType},
+						}},
+					},
+				}},
+				{Kind: dagger.ObjectKind, AsObject: &modObject{
+					Name: "Directory",
+				}},
+			},
+		},
+		vars: map[string]*shellValue{
+			"src": {TypeName: "Directory", ID: &idproto.ID{Field: "directory"}},
+			"sub": {TypeName: "Directory", ID: &idproto.ID{Field: "directory"}},
+		},
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	sh := testShellSession()
+
+	for _, tc := range []struct {
+		input    string
+		current  string
+		start    int
+		expected []string
+	}{
+		{
+			input:    "c",
+			expected: []string{"container"},
+		},
+		{
+			input:    "container | w",
+			start:    12,
+			expected: []string{"with-directory", "with-exec", "with-workdir"},
+		},
+		{
+			input:    "container | with-exec --",
+			start:    22,
+			expected: []string{"--args", "--skip-entrypoint"},
+		},
+		{
+			input:    "ctr = container | from alpine | s",
+			start:    32,
+			expected: []string{"stdout"},
+		},
+		{
+			input:    "with-directory /src $s",
+			start:    20,
+			expected: []string{"$src", "$sub"},
+		},
+		{
+			input:    "$src | e",
+			start:    7,
+			expected: nil,
+		},
+		{
+			// the previous result's functions and the root's
+			input:    "",
+			current:  "Container",
+			expected: []string{"container", "from", "host", "stdout", "with-directory", "with-exec", "with-workdir"},
+		},
+		{
+			input:    ".v",
+			expected: []string{".vars"},
+		},
+		{
+			input: "nope | ",
+			start: 7,
+		},
+	} {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			sh.current = nil
+			if tc.current != "" {
+				sh.current = &shellValue{TypeName: tc.current}
+			}
+			start, candidates := sh.complete(tc.input)
+			require.Equal(t, tc.start, start)
+			require.Equal(t, tc.expected, candidates)
+		})
+	}
+}
+
+func TestShellParseArgs(t *testing.T) {
+	sh := testShellSession()
+	srcID, err := sh.vars["src"].ID.Encode()
+	require.NoError(t, err)
+
+	ctr := sh.mod.GetObject("Container")
+
+	for _, tc := range []struct {
+		fn       string
+		words    []string
+		expected map[string]any
+		err      string
+	}{
+		{
+			fn:    "from",
+			words: []string{"alpine"},
+			expected: map[string]any{
+				"address": "alpine",
+			},
+		},
+		{
+			fn:    "from",
+			words: []string{"--address", "alpine"},
+			expected: map[string]any{
+				"address": "alpine",
+			},
+		},
+		{
+			fn:    "withExec",
+			words: []string{"sh", "-c", "echo a,b"},
+			expected: map[string]any{
+				"args": []string{"sh", "-c", "echo a,b"},
+			},
+		},
+		{
+			fn:    "withExec",
+			words: []string{"--skip-entrypoint", "echo", "hi"},
+			expected: map[string]any{
+				"args":           []string{"echo", "hi"},
+				"skipEntrypoint": "true",
+			},
+		},
+		{
+			fn:    "withDirectory",
+			words: []string{"/src", "$src"},
+			expected: map[string]any{
+				"path":      "/src",
+				"directory": srcID,
+			},
+		},
+		{
+			fn:    "withDirectory",
+			words: []string{"--directory=$src", "--owner", "me", "/src"},
+			expected: map[string]any{
+				"path":      "/src",
+				"directory": srcID,
+				"owner":     "me",
+			},
+		},
+		{
+			fn:    "withDirectory",
+			words: []string{"/src", "$nope"},
+			err:   `undefined variable "nope"`,
+		},
+		{
+			fn:    "withWorkdir",
+			words: []string{},
+			err:   `missing argument "path"`,
+		},
+		{
+			fn:    "withWorkdir",
+			words: []string{"/src", "/other"},
+			err:   `unexpected arguments: /other`,
+		},
+	} {
+		tc := tc
+		t.Run(fmt.Sprintf("%s %v", tc.fn, tc.words), func(t *testing.T) {
+			fn, err := ctr.GetFunction(tc.fn)
+			require.NoError(t, err)
+
+			args, err := sh.parseArgs(context.Background(), fn, tc.words)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			// flag values are passed as-is to the query builder, so compare
+			// their string representations
+			actual := map[string]any{}
+			for k, v := range args {
+				switch v.(type) {
+				case string, []string:
+					actual[k] = v
+				default:
+					actual[k] = fmt.Sprint(v)
+				}
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
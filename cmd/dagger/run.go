@@ -62,6 +62,8 @@ func init() {
 	)
 
 	runCmd.Flags().BoolVar(&runFocus, "focus", false, "Only show output for focused commands.")
+
+	addWatchFlag(runCmd.Flags())
 }
 
 func Run(cmd *cobra.Command, args []string) {
@@ -89,10 +91,16 @@ func run(ctx context.Context, args []string) error {
 
 	sessionToken := u.String()
 
-	focus = runFocus
-	return withEngineAndTUI(ctx, client.Params{
+	var watcher hostWatcher
+	params := client.Params{
 		SecretToken: sessionToken,
-	}, func(ctx context.Context, engineClient *client.Client) error {
+	}
+	if watchMode {
+		params.LocalImportCallback = watcher.Record
+	}
+
+	focus = runFocus
+	return withEngineAndTUI(ctx, params, func(ctx context.Context, engineClient *client.Client) error {
 		sessionL, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
 			return fmt.Errorf("session listen: %w", err)
@@ -103,45 +111,54 @@ func run(ctx context.Context, args []string) error {
 		os.Setenv("DAGGER_SESSION_PORT", sessionPort)
 		os.Setenv("DAGGER_SESSION_TOKEN", sessionToken)
 
-		subCmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec
-
-		// allow piping to the command
-		subCmd.Stdin = os.Stdin
+		go http.Serve(sessionL, engineClient) // nolint:gosec
 
-		// NB: go run lets its child process roam free when you interrupt it, so
-		// make sure they all get signalled. (you don't normally notice this in a
-		// shell because Ctrl+C sends to the process group.)
-		ensureChildProcessesAreKilled(subCmd)
+		if watchMode {
+			return watcher.Loop(ctx, func(ctx context.Context) error {
+				return runSubCmd(ctx, args)
+			})
+		}
+		return runSubCmd(ctx, args)
+	})
+}
 
-		go http.Serve(sessionL, engineClient) // nolint:gosec
+func runSubCmd(ctx context.Context, args []string) error {
+	subCmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec
 
-		var cmdErr error
-		if !silent {
-			rec := progrock.FromContext(ctx)
+	// allow piping to the command
+	subCmd.Stdin = os.Stdin
 
-			cmdline := strings.Join(subCmd.Args, " ")
-			cmdVtx := rec.Vertex(tui.RootVertex, cmdline)
+	// NB: go run lets its child process roam free when you interrupt it, so
+	// make sure they all get signalled. (you don't normally notice this in a
+	// shell because Ctrl+C sends to the process group.)
+	ensureChildProcessesAreKilled(subCmd)
 
-			if stdoutIsTTY {
-				subCmd.Stdout = cmdVtx.Stdout()
-			} else {
-				subCmd.Stdout = os.Stdout
-			}
+	var cmdErr error
+	if !silent {
+		rec := progrock.FromContext(ctx)
 
-			if stderrIsTTY {
-				subCmd.Stderr = cmdVtx.Stderr()
-			} else {
-				subCmd.Stderr = os.Stderr
-			}
+		cmdline := strings.Join(subCmd.Args, " ")
+		cmdVtx := rec.Vertex(watchVertexID(ctx, tui.RootVertex), cmdline)
 
-			cmdErr = subCmd.Run()
-			cmdVtx.Done(cmdErr)
+		if stdoutIsTTY {
+			subCmd.Stdout = cmdVtx.Stdout()
 		} else {
 			subCmd.Stdout = os.Stdout
+		}
+
+		if stderrIsTTY {
+			subCmd.Stderr = cmdVtx.Stderr()
+		} else {
 			subCmd.Stderr = os.Stderr
-			cmdErr = subCmd.Run()
 		}
 
-		return cmdErr
-	})
+		cmdErr = subCmd.Run()
+		cmdVtx.Done(cmdErr)
+	} else {
+		subCmd.Stdout = os.Stdout
+		subCmd.Stderr = os.Stderr
+		cmdErr = subCmd.Run()
+	}
+
+	return cmdErr
 }
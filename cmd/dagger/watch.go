@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/fsnotify/fsnotify"
+	"github.com/moby/patternmatcher"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/pflag"
+	"github.com/vito/progrock"
+)
+
+var watchMode bool
+
+// watchDebounce is how long to wait for more changes after the first one
+// before starting the next iteration, so that saving several files at once
+// only triggers a single run.
+const watchDebounce = 200 * time.Millisecond
+
+func addWatchFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(&watchMode, "watch", false, "Run again whenever the host directories loaded during the session change")
+}
+
+// hostWatcher records the host directories loaded by the engine during an
+// iteration so that they can be watched for changes afterwards.
+type hostWatcher struct {
+	mu      sync.Mutex
+	imports []engine.LocalImportOpts
+}
+
+// Record is meant to be used as the client.Params.LocalImportCallback.
+func (w *hostWatcher) Record(opts engine.LocalImportOpts) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.imports = append(w.imports, opts)
+}
+
+func (w *hostWatcher) reset() []engine.LocalImportOpts {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	imports := w.imports
+	w.imports = nil
+	return imports
+}
+
+type watchIterationKey struct{}
+
+// watchVertexID returns a vertex ID that is unique to the current watch
+// iteration, so that vertices from different iterations aren't merged.
+func watchVertexID(ctx context.Context, id string) digest.Digest {
+	iteration, _ := ctx.Value(watchIterationKey{}).(int)
+	if iteration <= 1 {
+		return digest.Digest(id)
+	}
+	return digest.Digest(fmt.Sprintf("%s#%d", id, iteration))
+}
+
+// Loop calls fn, then waits for changes to the host directories loaded
+// while it ran and calls it again, reusing the same engine session, until ctx
+// is canceled.
+//
+// Errors returned by fn are reported by fn itself and don't stop the loop.
+func (w *hostWatcher) Loop(ctx context.Context, fn func(ctx context.Context) error) error {
+	rec := progrock.FromContext(ctx)
+
+	for iteration := 1; ; iteration++ {
+		w.reset()
+
+		iterCtx, _ := progrock.WithGroup(ctx, fmt.Sprintf("run %d", iteration), progrock.Weak())
+		iterCtx = context.WithValue(iterCtx, watchIterationKey{}, iteration)
+		err := fn(iterCtx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		imports := w.reset()
+		if len(imports) == 0 {
+			// nothing to watch, so nothing will ever change
+			return err
+		}
+
+		vtx := rec.Vertex(
+			watchVertexID(iterCtx, "watch"),
+			fmt.Sprintf("watching %d host directories for changes", len(imports)),
+			progrock.Focused(),
+		)
+		changed, err := waitForChanges(ctx, imports)
+		if err != nil {
+			vtx.Done(err)
+			return err
+		}
+		for _, p := range changed {
+			fmt.Fprintf(vtx.Stderr(), "changed: %s\n", p)
+		}
+		vtx.Done(nil)
+	}
+}
+
+// waitForChanges blocks until a file that is part of one of the imports is
+// created, written, removed or renamed, and returns the changed paths.
+func waitForChanges(ctx context.Context, imports []engine.LocalImportOpts) ([]string, error) {
+	watcher, err := watchImports(imports)
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Close()
+	return watcher.wait(ctx)
+}
+
+// importWatcher watches the host directories of a set of imports.
+type importWatcher struct {
+	watcher *fsnotify.Watcher
+	roots   []watchRoot
+}
+
+// watchImports starts watching the imports, so that any change made once it
+// returns is seen by wait.
+func watchImports(imports []engine.LocalImportOpts) (*importWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+
+	roots := make([]watchRoot, 0, len(imports))
+	for _, opts := range imports {
+		root, err := newWatchRoot(opts)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		if err := root.addDir(watcher, root.path); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+
+	return &importWatcher{
+		watcher: watcher,
+		roots:   roots,
+	}, nil
+}
+
+func (w *importWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// wait blocks until a file that is part of one of the imports is created,
+// written, removed or renamed, and returns the changed paths.
+func (w *importWatcher) wait(ctx context.Context) ([]string, error) {
+	changed := map[string]struct{}{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-w.watcher.Errors:
+			return nil, fmt.Errorf("watch: %w", err)
+		case <-debounce:
+			paths := make([]string, 0, len(changed))
+			for p := range changed {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			return paths, nil
+		case ev := <-w.watcher.Events:
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			for _, root := range w.roots {
+				rel, ok := root.rel(ev.Name)
+				if !ok || root.excluded(rel) {
+					continue
+				}
+				if ev.Op.Has(fsnotify.Create) {
+					// watch new directories too
+					if err := root.addDir(w.watcher, ev.Name); err != nil {
+						return nil, err
+					}
+				}
+				if !root.included(rel) {
+					continue
+				}
+				changed[ev.Name] = struct{}{}
+				if debounce == nil {
+					debounce = time.After(watchDebounce)
+				}
+			}
+		}
+	}
+}
+
+// watchRoot is a host directory loaded with the given include and exclude
+// patterns.
+type watchRoot struct {
+	path     string
+	includes *patternmatcher.PatternMatcher
+	excludes *patternmatcher.PatternMatcher
+}
+
+func newWatchRoot(opts engine.LocalImportOpts) (watchRoot, error) {
+	path, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return watchRoot{}, err
+	}
+	includes, err := patternmatcher.New(opts.IncludePatterns)
+	if err != nil {
+		return watchRoot{}, fmt.Errorf("invalid include patterns: %w", err)
+	}
+	excludes, err := patternmatcher.New(opts.ExcludePatterns)
+	if err != nil {
+		return watchRoot{}, fmt.Errorf("invalid exclude patterns: %w", err)
+	}
+	return watchRoot{
+		path:     path,
+		includes: includes,
+		excludes: excludes,
+	}, nil
+}
+
+// rel returns the slash-separated path of p relative to the root, and whether
+// p is below the root at all.
+func (root watchRoot) rel(p string) (string, bool) {
+	rel, err := filepath.Rel(root.path, p)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+func (root watchRoot) excluded(rel string) bool {
+	if rel == "." {
+		return false
+	}
+	excluded, _ := root.excludes.MatchesOrParentMatches(rel)
+	return excluded
+}
+
+func (root watchRoot) included(rel string) bool {
+	if len(root.includes.Patterns()) == 0 {
+		return true
+	}
+	included, _ := root.includes.MatchesOrParentMatches(rel)
+	return included
+}
+
+// addDir watches dir and every directory below it that isn't excluded.
+func (root watchRoot) addDir(watcher *fsnotify.Watcher, dir string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// removed in the meantime
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		// directories with exclusions below them are still walked
+		if rel, _ := root.rel(p); root.excluded(rel) && !root.excludes.Exclusions() {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchRoot(t *testing.T) {
+	root, err := newWatchRoot(engine.LocalImportOpts{
+		Path:            "/src",
+		IncludePatterns: []string{"app/", "go.mod"},
+		ExcludePatterns: []string{"app/node_modules"},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		path     string
+		expected bool
+	}{
+		{"/src/go.mod", true},
+		{"/src/app/main.go", true},
+		{"/src/app/sub/main.go", true},
+		{"/src/app/node_modules/dep/index.js", false},
+		{"/src/README.md", false},
+		{"/other/go.mod", false},
+		{"/src-other/go.mod", false},
+	} {
+		rel, ok := root.rel(tc.path)
+		require.Equal(t, tc.expected, ok && !root.excluded(rel) && root.included(rel), tc.path)
+	}
+}
+
+func TestWaitForChanges(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "out"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("1"), 0o644))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	watcher, err := watchImports([]engine.LocalImportOpts{{
+		Path:            dir,
+		ExcludePatterns: []string{"out"},
+	}})
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	// changes to excluded paths are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out", "bin"), []byte("2"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("3"), 0o644))
+
+	changed, err := watcher.wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "main.go")}, changed)
+}
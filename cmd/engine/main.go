@@ -22,6 +22,8 @@ import (
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/containerd/sys"
 	sddaemon "github.com/coreos/go-systemd/v22/daemon"
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/cache"
 	"github.com/dagger/dagger/engine/server"
 	"github.com/dagger/dagger/network"
@@ -789,6 +791,19 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 		return nil, nil, err
 	}
 
+	dagqlCacheOpts, err := dagqlCacheOptsFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	dagqlLimits, err := engine.DagqlLimitsFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	dagqlCache, err := newDagqlCache(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	resolverFn := resolverFunc(cfg)
 	remoteCacheExporterFuncs := map[string]remotecache.ResolveCacheExporterFunc{
 		"registry": registryremotecache.ResolveCacheExporterFunc(sessionManager, resolverFn),
@@ -828,6 +843,9 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 		UpstreamCacheExporters: remoteCacheExporterFuncs,
 		UpstreamCacheImporters: remoteCacheImporterFuncs,
 		DNSConfig:              getDNSConfig(cfg.DNS),
+		DagqlCacheOpts:         dagqlCacheOpts,
+		DagqlCache:             dagqlCache,
+		DagqlLimits:            dagqlLimits,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -836,6 +854,72 @@ func newController(ctx context.Context, c *cli.Context, cfg *config.Config) (*se
 	return ctrler, cacheManager, nil
 }
 
+const (
+	dagqlCacheEnvName        = "_EXPERIMENTAL_DAGGER_DAGQL_CACHE"
+	dagqlCacheMaxSizeEnvName = "_EXPERIMENTAL_DAGGER_DAGQL_CACHE_MAX_SIZE"
+	dagqlCacheMaxAgeEnvName  = "_EXPERIMENTAL_DAGGER_DAGQL_CACHE_MAX_AGE"
+
+	defaultDagqlCacheMaxSize = 256 * 1024 * 1024
+	defaultDagqlCacheMaxAge  = 7 * 24 * time.Hour
+
+	dagqlCacheMaxEntriesEnvName = "_EXPERIMENTAL_DAGGER_DAGQL_CACHE_MAX_ENTRIES"
+	dagqlCacheTTLEnvName        = "_EXPERIMENTAL_DAGGER_DAGQL_CACHE_TTL"
+)
+
+// dagqlCacheOptsFromEnv configures the eviction of results from each
+// session's in-memory cache. By default, nothing is evicted.
+func dagqlCacheOptsFromEnv() (dagql.CacheOpts, error) {
+	var opts dagql.CacheOpts
+	var err error
+	if v := os.Getenv(dagqlCacheMaxEntriesEnvName); v != "" {
+		opts.MaxEntries, err = strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", dagqlCacheMaxEntriesEnvName, err)
+		}
+	}
+	if v := os.Getenv(dagqlCacheTTLEnvName); v != "" {
+		opts.TTL, err = time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", dagqlCacheTTLEnvName, err)
+		}
+	}
+	return opts, nil
+}
+
+// newDagqlCache opens the persistent cache of API results, if enabled. Its
+// limits default to 256MiB of results unused for at most a week.
+func newDagqlCache(cfg *config.Config) (*dagql.DiskCache, error) {
+	v, ok := os.LookupEnv(dagqlCacheEnvName)
+	if !ok {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", dagqlCacheEnvName, err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	opts := dagql.DiskCacheOpts{
+		MaxSize: defaultDagqlCacheMaxSize,
+		MaxAge:  defaultDagqlCacheMaxAge,
+		Version: engine.Version,
+	}
+	if v := os.Getenv(dagqlCacheMaxSizeEnvName); v != "" {
+		opts.MaxSize, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", dagqlCacheMaxSizeEnvName, err)
+		}
+	}
+	if v := os.Getenv(dagqlCacheMaxAgeEnvName); v != "" {
+		opts.MaxAge, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", dagqlCacheMaxAgeEnvName, err)
+		}
+	}
+	return dagql.OpenDiskCache(filepath.Join(cfg.Root, "dagql-cache.db"), opts)
+}
+
 func resolverFunc(cfg *config.Config) docker.RegistryHosts {
 	return resolver.NewRegistryConfig(cfg.Registries)
 }
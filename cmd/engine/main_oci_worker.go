@@ -31,6 +31,7 @@ import (
 	sgzlayer "github.com/containerd/stargz-snapshotter/fs/layer"
 	sgzsource "github.com/containerd/stargz-snapshotter/fs/source"
 	remotesn "github.com/containerd/stargz-snapshotter/snapshot"
+	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/sources/blob"
 	"github.com/dagger/dagger/engine/sources/gitdns"
 	"github.com/dagger/dagger/engine/sources/httpdns"
@@ -335,6 +336,10 @@ func ociWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([]worker
 	if err != nil {
 		return nil, err
 	}
+	opt.Executor, err = buildkit.ReportExecMetrics(opt.Executor, filepath.Join(common.config.Root, "exec-metrics"))
+	if err != nil {
+		return nil, err
+	}
 	opt.GCPolicy = getGCPolicy(cfg.GCConfig, common.config.Root)
 	opt.BuildkitVersion = getBuildkitVersion()
 	opt.RegistryHosts = hosts
@@ -350,6 +355,7 @@ func ociWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([]worker
 	if err != nil {
 		return nil, err
 	}
+	w.CacheMgr = buildkit.LimitCacheMounts(w.CacheMgr)
 	if err := registerDaggerCustomSources(w, dns); err != nil {
 		return nil, fmt.Errorf("register Dagger sources: %w", err)
 	}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	"github.com/klauspost/compress/zstd"
+)
+
+// These match the core.ArchiveFormat and core.ArchiveCompression enum values.
+const (
+	archiveFormatTar = "TAR"
+	archiveFormatZip = "ZIP"
+
+	archiveCompressionNone = "NONE"
+	archiveCompressionGz   = "GZ"
+	archiveCompressionZst  = "ZST"
+)
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// archive writes the contents of the src directory to an archive at dest.
+func archive(args []string) error {
+	if len(args) != 4 {
+		return fmt.Errorf("usage: archive <format> <compression> <src> <dest>")
+	}
+
+	format, compression, src, dest := args[0], args[1], args[2], args[3]
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case archiveFormatTar:
+		var w io.WriteCloser
+		switch compression {
+		case archiveCompressionNone:
+			w = nopWriteCloser{f}
+		case archiveCompressionGz:
+			w = gzip.NewWriter(f)
+		case archiveCompressionZst:
+			w, err = zstd.NewWriter(f)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown archive compression: %s", compression)
+		}
+		if err := writeTar(w, src); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+	case archiveFormatZip:
+		if compression != archiveCompressionNone {
+			return fmt.Errorf("zip archives don't support %s compression", compression)
+		}
+		if err := writeZip(f, src); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown archive format: %s", format)
+	}
+
+	return f.Close()
+}
+
+// extract extracts the archive at src into the dest directory. If format is
+// empty, it is detected from the contents of the archive. The compression of
+// tar archives is always detected.
+func extract(args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("usage: extract <format> <src> <dest>")
+	}
+
+	format, src, dest := args[0], args[1], args[2]
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	if format == "" {
+		format = archiveFormatTar
+		if magic, _ := r.Peek(len(zipMagic)); bytes.Equal(magic, zipMagic) {
+			format = archiveFormatZip
+		}
+	}
+
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+
+	switch format {
+	case archiveFormatTar:
+		tr, err := decompress(r)
+		if err != nil {
+			return err
+		}
+		defer tr.Close()
+		return readTar(tr, dest)
+	case archiveFormatZip:
+		stat, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(f, stat.Size())
+		if err != nil {
+			return err
+		}
+		return readZip(zr, dest)
+	default:
+		return fmt.Errorf("unknown archive format: %s", format)
+	}
+}
+
+func decompress(r *bufio.Reader) (io.ReadCloser, error) {
+	magic, _ := r.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(r)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+func writeTar(w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+
+	err := walkArchiveSrc(src, func(fullPath, rel string, fi fs.FileInfo, link string) error {
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		// names aren't resolvable in an empty rootfs, and ids are what matter
+		hdr.Uname = ""
+		hdr.Gname = ""
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileTo(tw, fullPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func writeZip(w io.Writer, src string) error {
+	zw := zip.NewWriter(w)
+
+	err := walkArchiveSrc(src, func(fullPath, rel string, fi fs.FileInfo, link string) error {
+		hdr, err := zip.FileInfoHeader(fi)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if fi.IsDir() {
+			hdr.Name += "/"
+		} else {
+			hdr.Method = zip.Deflate
+		}
+
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		switch {
+		case fi.Mode()&fs.ModeSymlink != 0:
+			// zip stores the target of a symlink as its contents
+			_, err := io.WriteString(fw, link)
+			return err
+		case fi.Mode().IsRegular():
+			return copyFileTo(fw, fullPath)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// walkArchiveSrc calls fn for every entry below src in lexical order, with its
+// path relative to src and its link target if it's a symlink. Entries other
+// than regular files, directories and symlinks are skipped.
+func walkArchiveSrc(src string, fn func(fullPath, rel string, fi fs.FileInfo, link string) error) error {
+	return filepath.WalkDir(src, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fullPath == src {
+			return nil
+		}
+		rel, err := filepath.Rel(src, fullPath)
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case fi.Mode()&fs.ModeSymlink != 0:
+			link, err = os.Readlink(fullPath)
+			if err != nil {
+				return err
+			}
+		case fi.Mode().IsRegular(), fi.IsDir():
+		default:
+			return nil
+		}
+
+		return fn(fullPath, filepath.ToSlash(rel), fi, link)
+	})
+}
+
+func readTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := archiveEntryPath(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		mode := hdr.FileInfo().Mode()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = extractDir(target, mode)
+		case tar.TypeReg, tar.TypeRegA: //nolint:staticcheck // TypeRegA is still found in the wild
+			err = extractFile(target, mode, tr)
+		case tar.TypeSymlink:
+			err = extractSymlink(target, hdr.Linkname)
+		case tar.TypeLink:
+			var oldname string
+			oldname, err = archiveEntryPath(dest, hdr.Linkname)
+			if err == nil {
+				err = extractHardlink(target, oldname)
+			}
+		default:
+			// devices, fifos etc. are skipped
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", hdr.Name, err)
+		}
+
+		if err := os.Lchown(target, hdr.Uid, hdr.Gid); err != nil {
+			return fmt.Errorf("extract %s: %w", hdr.Name, err)
+		}
+		if hdr.Typeflag != tar.TypeSymlink {
+			if err := os.Chtimes(target, hdr.ModTime, hdr.ModTime); err != nil {
+				return fmt.Errorf("extract %s: %w", hdr.Name, err)
+			}
+		}
+	}
+}
+
+func readZip(zr *zip.Reader, dest string) error {
+	for _, zf := range zr.File {
+		target, err := archiveEntryPath(dest, zf.Name)
+		if err != nil {
+			return err
+		}
+		mode := zf.Mode()
+
+		err = func() error {
+			if mode.IsDir() {
+				return extractDir(target, mode)
+			}
+
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+
+			if mode&fs.ModeSymlink != 0 {
+				link, err := io.ReadAll(rc)
+				if err != nil {
+					return err
+				}
+				return extractSymlink(target, string(link))
+			}
+			if err := extractFile(target, mode, rc); err != nil {
+				return err
+			}
+			return os.Chtimes(target, zf.Modified, zf.Modified)
+		}()
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", zf.Name, err)
+		}
+	}
+	return nil
+}
+
+// archiveEntryPath returns the path to extract an entry to, refusing entries
+// that would be written outside of dest. Symlinks in parent directories are
+// resolved within dest, so earlier entries can't redirect later ones either.
+func archiveEntryPath(dest, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q escapes destination", name)
+	}
+	parent, err := continuityfs.RootPath(dest, filepath.Dir(clean))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(parent, filepath.Base(clean)), nil
+}
+
+func extractDir(target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode.Perm()|mode&(fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky))
+}
+
+func extractFile(target string, mode fs.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode.Perm()|mode&(fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky))
+}
+
+func extractSymlink(target, link string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.Symlink(link, target)
+}
+
+func extractHardlink(target, oldname string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.Link(oldname, target)
+}
+
+func copyFileTo(w io.Writer, fullPath string) error {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
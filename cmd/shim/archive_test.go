@@ -0,0 +1,110 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "sub", "empty"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+	require.NoError(t, os.Symlink("../a.txt", filepath.Join(src, "sub", "link")))
+
+	for _, tc := range []struct {
+		format      string
+		compression string
+	}{
+		{archiveFormatTar, archiveCompressionNone},
+		{archiveFormatTar, archiveCompressionGz},
+		{archiveFormatTar, archiveCompressionZst},
+		{archiveFormatZip, archiveCompressionNone},
+	} {
+		tc := tc
+		t.Run(tc.format+"/"+tc.compression, func(t *testing.T) {
+			tmp := t.TempDir()
+			archivePath := filepath.Join(tmp, "archive")
+			require.NoError(t, archive([]string{tc.format, tc.compression, src, archivePath}))
+
+			// the format and compression are detected
+			dest := filepath.Join(tmp, "out")
+			require.NoError(t, extract([]string{"", archivePath, dest}))
+
+			content, err := os.ReadFile(filepath.Join(dest, "a.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "a", string(content))
+
+			stat, err := os.Stat(filepath.Join(dest, "sub", "run.sh"))
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o755), stat.Mode().Perm())
+
+			link, err := os.Readlink(filepath.Join(dest, "sub", "link"))
+			require.NoError(t, err)
+			require.Equal(t, "../a.txt", link)
+
+			stat, err = os.Stat(filepath.Join(dest, "sub", "empty"))
+			require.NoError(t, err)
+			require.True(t, stat.IsDir())
+		})
+	}
+
+	t.Run("zip with compression", func(t *testing.T) {
+		err := archive([]string{archiveFormatZip, archiveCompressionGz, src, filepath.Join(t.TempDir(), "archive")})
+		require.Error(t, err)
+	})
+}
+
+func TestExtractEscape(t *testing.T) {
+	writeTar := func(t *testing.T, hdrs ...*tar.Header) string {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range hdrs {
+			require.NoError(t, tw.WriteHeader(hdr))
+		}
+		require.NoError(t, tw.Close())
+
+		archivePath := filepath.Join(t.TempDir(), "archive.tar")
+		require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o644))
+		return archivePath
+	}
+
+	t.Run("parent path", func(t *testing.T) {
+		archivePath := writeTar(t, &tar.Header{
+			Name:     "../escaped",
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Uid:      os.Getuid(),
+			Gid:      os.Getgid(),
+		})
+		dest := filepath.Join(t.TempDir(), "out")
+		require.Error(t, extract([]string{archiveFormatTar, archivePath, dest}))
+	})
+
+	t.Run("through symlink", func(t *testing.T) {
+		outside := t.TempDir()
+		archivePath := writeTar(t, &tar.Header{
+			Name:     "link",
+			Typeflag: tar.TypeSymlink,
+			Linkname: outside,
+			Uid:      os.Getuid(),
+			Gid:      os.Getgid(),
+		}, &tar.Header{
+			Name:     "link/escaped",
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Uid:      os.Getuid(),
+			Gid:      os.Getgid(),
+		})
+		dest := filepath.Join(t.TempDir(), "out")
+		require.NoError(t, extract([]string{archiveFormatTar, archivePath, dest}))
+
+		_, err := os.Stat(filepath.Join(outside, "escaped"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/client"
 	"github.com/dagger/dagger/network"
@@ -33,11 +34,13 @@ import (
 )
 
 const (
-	metaMountPath = "/.dagger_meta_mount"
-	stdinPath     = metaMountPath + "/stdin"
-	exitCodePath  = metaMountPath + "/exitCode"
-	runcPath      = "/usr/local/bin/runc"
-	shimPath      = "/_shim"
+	metaMountPath  = "/.dagger_meta_mount"
+	stdinPath      = metaMountPath + "/stdin"
+	exitCodePath   = metaMountPath + "/exitCode"
+	exitReasonPath = metaMountPath + "/exitReason"
+	metricsPath    = engine.ExecMetricsMountPath + "/" + engine.ExecMetricsFile
+	runcPath       = "/usr/local/bin/runc"
+	shimPath       = "/_shim"
 
 	errorExitCode = 125
 )
@@ -101,6 +104,24 @@ func internalCommand() int {
 			return 1
 		}
 		return 0
+	case "symlink":
+		if err := symlink(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return errorExitCode
+		}
+		return 0
+	case "archive":
+		if err := archive(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return errorExitCode
+		}
+		return 0
+	case "extract":
+		if err := extract(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return errorExitCode
+		}
+		return 0
 	default:
 		fmt.Fprintf(os.Stderr, "unknown command: %s\n", cmd)
 		return errorExitCode
@@ -135,6 +156,20 @@ func check(args []string) error {
 	return nil
 }
 
+func symlink(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: symlink <target> <link>")
+	}
+
+	target, link := args[0], args[1]
+
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		return err
+	}
+
+	return os.Symlink(target, link)
+}
+
 func pollForPort(network, addr string) (string, error) {
 	retry := backoff.NewExponentialBackOff()
 	retry.InitialInterval = 100 * time.Millisecond
@@ -182,8 +217,33 @@ func shim() (returnExitCode int) {
 		args = os.Args[2:]
 	}
 
-	cmd := exec.Command(name, args...)
 	_, isTTY := internalEnv(core.ShimEnableTTYEnvVar)
+
+	var timeout time.Duration
+	if timeoutVal, found := internalEnv("_DAGGER_EXEC_TIMEOUT"); found {
+		seconds, err := strconv.Atoi(timeoutVal)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid timeout %q: %v\n", timeoutVal, err)
+			return errorExitCode
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	expect, _ := internalEnv("_DAGGER_EXEC_EXPECT")
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if timeout > 0 && !isTTY {
+		// kill the whole process group on timeout, so that any children
+		// holding onto stdout/stderr don't keep us waiting
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+	}
 	if isTTY {
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
@@ -281,10 +341,23 @@ func shim() (returnExitCode int) {
 		}
 	}
 
+	sampler := newMetricsSampler(metricsPath)
+	sampler.Start(metricsInterval)
+	defer sampler.Stop()
+
 	exitCode := 0
+	var exitReason buildkit.ExecErrorReason
 	if err := runWithNesting(ctx, cmd); err != nil {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			exitCode = exiterr.ExitCode()
+			switch {
+			case errors.Is(ctx.Err(), context.DeadlineExceeded):
+				exitReason = buildkit.ExecErrorReasonTimeout
+				fmt.Fprintf(os.Stderr, "command timed out after %s\n", timeout)
+			case wasKilled(exiterr) && oomKilled():
+				exitReason = buildkit.ExecErrorReasonOOMKilled
+				fmt.Fprintln(os.Stderr, "command was killed for exceeding its memory limit")
+			}
 		} else {
 			exitCode = errorExitCode
 			fmt.Fprintln(os.Stderr, err.Error())
@@ -294,10 +367,64 @@ func shim() (returnExitCode int) {
 	if err := os.WriteFile(exitCodePath, []byte(fmt.Sprintf("%d", exitCode)), 0o600); err != nil {
 		panic(err)
 	}
+	if exitReason != "" {
+		if err := os.WriteFile(exitReasonPath, []byte(exitReason), 0o600); err != nil {
+			panic(err)
+		}
+		// limits are enforced regardless of the expected exit code
+		return exitCode
+	}
+
+	return expectedExitCode(expect, exitCode)
+}
 
+// expectedExitCode returns the exit code the shim should exit with, which is
+// 0 if the command's exit code is one that was expected.
+func expectedExitCode(expect string, exitCode int) int {
+	switch core.ReturnType(expect) {
+	case core.ReturnFailure:
+		if exitCode == 0 {
+			fmt.Fprintln(os.Stderr, "expected command to fail, but it succeeded")
+			return errorExitCode
+		}
+		if exitCode > 0 && exitCode <= 127 {
+			return 0
+		}
+	case core.ReturnAny:
+		if exitCode >= 0 && exitCode <= 127 {
+			return 0
+		}
+	}
 	return exitCode
 }
 
+func wasKilled(exiterr *exec.ExitError) bool {
+	status, ok := exiterr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGKILL
+}
+
+// oomKilled returns whether the OOM killer has killed a process in the
+// container's cgroup.
+func oomKilled() bool {
+	for _, eventsPath := range []string{
+		"/sys/fs/cgroup/memory.events",             // cgroup v2
+		"/sys/fs/cgroup/memory/memory.oom_control", // cgroup v1
+	} {
+		events, err := os.ReadFile(eventsPath)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(events), "\n") {
+			key, val, ok := strings.Cut(line, " ")
+			if ok && key == "oom_kill" && strings.TrimSpace(val) != "0" {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
 func setupBundle() int {
 	// Figure out the path to the bundle dir, in which we can obtain the
 	// oci runtime config.json
@@ -436,9 +563,22 @@ func setupBundle() int {
 	}
 
 	var gpuParams string
+	var timeoutEnv string
 	keepEnv := []string{}
 	for _, env := range spec.Process.Env {
 		switch {
+		case strings.HasPrefix(env, "_DAGGER_EXEC_TIMEOUT="):
+			// keep the env var; the shim uses it at runtime. It may be set more
+			// than once, e.g. by withExec and then asService, last one wins.
+			timeoutEnv = env
+		case strings.HasPrefix(env, "_DAGGER_EXEC_MEMORY_LIMIT="),
+			strings.HasPrefix(env, "_DAGGER_EXEC_CPU_SHARES="),
+			strings.HasPrefix(env, "_DAGGER_EXEC_PIDS_LIMIT="):
+			// NB: don't keep these env vars, they're applied to the spec
+			if err := applyResourceLimit(&spec, env); err != nil {
+				fmt.Fprintln(os.Stderr, "resource limit:", err)
+				return errorExitCode
+			}
 		case strings.HasPrefix(env, "_DAGGER_ENABLE_NESTING="):
 			// keep the env var; we use it at runtime
 			keepEnv = append(keepEnv, env)
@@ -480,6 +620,9 @@ func setupBundle() int {
 			keepEnv = append(keepEnv, env)
 		}
 	}
+	if timeoutEnv != "" {
+		keepEnv = append(keepEnv, timeoutEnv)
+	}
 	spec.Process.Env = keepEnv
 
 	if gpuParams != "" {
@@ -545,6 +688,45 @@ func setupBundle() int {
 	return <-exitCodeCh
 }
 
+// applyResourceLimit sets the cgroup limit configured by the given env var
+// on the spec.
+func applyResourceLimit(spec *specs.Spec, env string) error {
+	name, val, _ := strings.Cut(env, "=")
+	limit, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	if spec.Linux == nil {
+		spec.Linux = &specs.Linux{}
+	}
+	if spec.Linux.Resources == nil {
+		spec.Linux.Resources = &specs.LinuxResources{}
+	}
+	resources := spec.Linux.Resources
+
+	switch name {
+	case "_DAGGER_EXEC_MEMORY_LIMIT":
+		if resources.Memory == nil {
+			resources.Memory = &specs.LinuxMemory{}
+		}
+		resources.Memory.Limit = &limit
+		// don't let the container swap its way past the limit
+		resources.Memory.Swap = &limit
+	case "_DAGGER_EXEC_CPU_SHARES":
+		if resources.CPU == nil {
+			resources.CPU = &specs.LinuxCPU{}
+		}
+		shares := uint64(limit)
+		resources.CPU.Shares = &shares
+	case "_DAGGER_EXEC_PIDS_LIMIT":
+		resources.Pids = &specs.LinuxPids{Limit: limit}
+	default:
+		return fmt.Errorf("unknown resource limit %s", name)
+	}
+	return nil
+}
+
 const aliasPrefix = "_DAGGER_HOSTNAME_ALIAS_"
 
 func appendHostAlias(hostsFilePath string, env string, searchDomains []string) error {
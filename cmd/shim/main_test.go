@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyResourceLimit(t *testing.T) {
+	spec := &specs.Spec{}
+
+	require.NoError(t, applyResourceLimit(spec, "_DAGGER_EXEC_MEMORY_LIMIT=1048576"))
+	require.NoError(t, applyResourceLimit(spec, "_DAGGER_EXEC_CPU_SHARES=512"))
+	require.NoError(t, applyResourceLimit(spec, "_DAGGER_EXEC_PIDS_LIMIT=42"))
+
+	resources := spec.Linux.Resources
+	require.Equal(t, int64(1048576), *resources.Memory.Limit)
+	require.Equal(t, int64(1048576), *resources.Memory.Swap)
+	require.Equal(t, uint64(512), *resources.CPU.Shares)
+	require.Equal(t, int64(42), resources.Pids.Limit)
+
+	// later values win
+	require.NoError(t, applyResourceLimit(spec, "_DAGGER_EXEC_PIDS_LIMIT=7"))
+	require.Equal(t, int64(7), resources.Pids.Limit)
+
+	require.Error(t, applyResourceLimit(spec, "_DAGGER_EXEC_PIDS_LIMIT=lots"))
+	require.Error(t, applyResourceLimit(spec, "_DAGGER_EXEC_BOGUS_LIMIT=1"))
+}
+
+func TestExpectedExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		expect   string
+		exitCode int
+		expected int
+	}{
+		{"", 0, 0},
+		{"", 1, 1},
+		{"SUCCESS", 0, 0},
+		{"SUCCESS", 2, 2},
+		{"FAILURE", 0, errorExitCode},
+		{"FAILURE", 1, 0},
+		{"FAILURE", 127, 0},
+		{"FAILURE", 137, 137},
+		{"FAILURE", -1, -1},
+		{"ANY", 0, 0},
+		{"ANY", 42, 0},
+		{"ANY", 137, 137},
+		{"ANY", -1, -1},
+	} {
+		require.Equal(t, tc.expected, expectedExitCode(tc.expect, tc.exitCode), "expect %q, exit code %d", tc.expect, tc.exitCode)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	dir := t.TempDir()
+
+	link := filepath.Join(dir, "some", "sub", "link")
+	require.NoError(t, symlink([]string{"../target", link}))
+
+	target, err := os.Readlink(link)
+	require.NoError(t, err)
+	require.Equal(t, "../target", target)
+
+	// an existing link is not replaced
+	require.Error(t, symlink([]string{"other", link}))
+
+	require.Error(t, symlink([]string{"target"}))
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+)
+
+const (
+	cgroupPath      = "/sys/fs/cgroup"
+	netDevPath      = "/proc/net/dev"
+	metricsInterval = 2 * time.Second
+)
+
+// metricsSampler periodically samples the resources used by the container's
+// cgroup and writes the latest sample to a file in the directory mounted by
+// the engine for it, which reports each new sample in the exec's progress.
+type metricsSampler struct {
+	cgroupPath string
+	netDevPath string
+	dst        string
+
+	peak uint64
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newMetricsSampler(dst string) *metricsSampler {
+	return &metricsSampler{
+		cgroupPath: cgroupPath,
+		netDevPath: netDevPath,
+		dst:        dst,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start samples every interval until Stop is called.
+func (s *metricsSampler) Start(interval time.Duration) {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.emit()
+			}
+		}
+	}()
+}
+
+// Stop stops sampling and writes a final sample.
+func (s *metricsSampler) Stop() {
+	close(s.stop)
+	<-s.done
+	s.emit()
+}
+
+func (s *metricsSampler) emit() {
+	payload, err := json.Marshal(s.Sample())
+	if err != nil {
+		return
+	}
+	// replace the file atomically, so that it's never read half-written
+	tmp := s.dst + ".tmp"
+	if err := os.WriteFile(tmp, payload, 0o600); err != nil {
+		return
+	}
+	os.Rename(tmp, s.dst)
+}
+
+// Sample reads the current metrics. Values that can't be read, e.g. because
+// the controller isn't enabled, are left as zero.
+func (s *metricsSampler) Sample() engine.ExecMetrics {
+	var m engine.ExecMetrics
+
+	if stat, err := readKeyedFile(filepath.Join(s.cgroupPath, "cpu.stat")); err == nil {
+		m.CPUTime = time.Duration(stat["usage_usec"]) * time.Microsecond
+	} else if usage, err := readUintFile(filepath.Join(s.cgroupPath, "cpuacct", "cpuacct.usage")); err == nil {
+		// cgroup v1, in nanoseconds
+		m.CPUTime = time.Duration(usage)
+	}
+
+	// memory.peak is only available on newer kernels; fall back to the highest
+	// usage sampled so far
+	for _, p := range []string{
+		"memory.peak",                      // cgroup v2
+		"memory/memory.max_usage_in_bytes", // cgroup v1
+		"memory.current",                   // cgroup v2
+		"memory/memory.usage_in_bytes",     // cgroup v1
+	} {
+		if usage, err := readUintFile(filepath.Join(s.cgroupPath, p)); err == nil {
+			s.peak = max(s.peak, usage)
+			break
+		}
+	}
+	m.MemoryPeak = s.peak
+
+	if rbytes, wbytes, err := readIOStat(filepath.Join(s.cgroupPath, "io.stat")); err == nil {
+		m.IOReadBytes, m.IOWriteBytes = rbytes, wbytes
+	} else if rbytes, wbytes, err := readBlkioStat(filepath.Join(s.cgroupPath, "blkio", "blkio.throttle.io_service_bytes")); err == nil {
+		m.IOReadBytes, m.IOWriteBytes = rbytes, wbytes
+	}
+
+	if rx, tx, err := readNetDev(s.netDevPath); err == nil {
+		m.NetRxBytes, m.NetTxBytes = rx, tx
+	}
+
+	return m
+}
+
+func readUintFile(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// readKeyedFile reads a file of "key value" lines, like cpu.stat.
+func readKeyedFile(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	vals := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+		if err != nil {
+			continue
+		}
+		vals[key] = n
+	}
+	return vals, scanner.Err()
+}
+
+// readIOStat sums the bytes read and written across devices from a cgroup v2
+// io.stat file, made of lines like "8:0 rbytes=1 wbytes=2 rios=3 wios=4".
+func readIOStat(path string) (rbytes, wbytes uint64, _ error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for _, field := range fields[min(1, len(fields)):] {
+			key, val, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				rbytes += n
+			case "wbytes":
+				wbytes += n
+			}
+		}
+	}
+	return rbytes, wbytes, scanner.Err()
+}
+
+// readBlkioStat sums the bytes read and written across devices from a cgroup
+// v1 blkio.throttle.io_service_bytes file, made of lines like "8:0 Read 1".
+func readBlkioStat(path string) (rbytes, wbytes uint64, _ error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		n, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			rbytes += n
+		case "Write":
+			wbytes += n
+		}
+	}
+	return rbytes, wbytes, scanner.Err()
+}
+
+// readNetDev sums the bytes received and sent by every interface but
+// loopback from /proc/net/dev. The container has its own network namespace,
+// so these are the container's.
+func readNetDev(path string) (rx, tx uint64, _ error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		iface, stats, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(iface) == "lo" {
+			// skips the two header lines too
+			continue
+		}
+		fields := strings.Fields(stats)
+		if len(fields) < 9 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			rx += n
+		}
+		if n, err := strconv.ParseUint(fields[8], 10, 64); err == nil {
+			tx += n
+		}
+	}
+	return rx, tx, scanner.Err()
+}
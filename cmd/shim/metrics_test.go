@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsSamplerCgroupV2(t *testing.T) {
+	dir := t.TempDir()
+	cgroupDir := filepath.Join(dir, "cgroup")
+	require.NoError(t, os.MkdirAll(cgroupDir, 0o755))
+	write := func(path, content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	write(filepath.Join(cgroupDir, "cpu.stat"), "usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\n")
+	write(filepath.Join(cgroupDir, "memory.current"), "4096\n")
+	write(filepath.Join(cgroupDir, "io.stat"), "8:0 rbytes=100 wbytes=200 rios=1 wios=2\n8:16 rbytes=10 wbytes=20 rios=1 wios=2\n")
+	netDev := filepath.Join(dir, "net_dev")
+	write(netDev, `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0:    5000      50    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+`)
+
+	dst := filepath.Join(dir, "metrics")
+	s := newMetricsSampler(dst)
+	s.cgroupPath = cgroupDir
+	s.netDevPath = netDev
+
+	require.Equal(t, engine.ExecMetrics{
+		CPUTime:      1500 * time.Millisecond,
+		MemoryPeak:   4096,
+		IOReadBytes:  110,
+		IOWriteBytes: 220,
+		NetRxBytes:   5000,
+		NetTxBytes:   3000,
+	}, s.Sample())
+
+	// the peak is kept when usage drops and memory.peak isn't available
+	write(filepath.Join(cgroupDir, "memory.current"), "1024\n")
+	require.Equal(t, uint64(4096), s.Sample().MemoryPeak)
+
+	s.Start(time.Hour)
+	s.Stop()
+	payload, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	var sample engine.ExecMetrics
+	require.NoError(t, json.Unmarshal(payload, &sample))
+	require.Equal(t, uint64(4096), sample.MemoryPeak)
+}
+
+func TestMetricsSamplerMissingCgroup(t *testing.T) {
+	s := newMetricsSampler(filepath.Join(t.TempDir(), "metrics"))
+	s.cgroupPath = filepath.Join(t.TempDir(), "nope")
+	s.netDevPath = filepath.Join(t.TempDir(), "nope")
+	require.Equal(t, engine.ExecMetrics{}, s.Sample())
+}
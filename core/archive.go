@@ -0,0 +1,83 @@
+package core
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+)
+
+// ArchiveFormat is the container format of an archive.
+type ArchiveFormat string
+
+var ArchiveFormats = dagql.NewEnum[ArchiveFormat]()
+
+var (
+	ArchiveFormatTar = ArchiveFormats.Register("TAR", "A tar archive, optionally compressed.")
+	ArchiveFormatZip = ArchiveFormats.Register("ZIP", "A zip archive.")
+)
+
+func (format ArchiveFormat) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ArchiveFormat",
+		NonNull:   true,
+	}
+}
+
+func (format ArchiveFormat) TypeDescription() string {
+	return "The format of an archive."
+}
+
+func (format ArchiveFormat) Decoder() dagql.InputDecoder {
+	return ArchiveFormats
+}
+
+func (format ArchiveFormat) ToLiteral() *idproto.Literal {
+	return ArchiveFormats.Literal(format)
+}
+
+// ArchiveCompression is the compression applied to a tar archive as a whole.
+type ArchiveCompression string
+
+var ArchiveCompressions = dagql.NewEnum[ArchiveCompression]()
+
+var (
+	ArchiveCompressionNone = ArchiveCompressions.Register("NONE", "No compression.")
+	ArchiveCompressionGz   = ArchiveCompressions.Register("GZ", "Gzip compression (.tar.gz).")
+	ArchiveCompressionZst  = ArchiveCompressions.Register("ZST", "Zstandard compression (.tar.zst).")
+)
+
+func (compression ArchiveCompression) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ArchiveCompression",
+		NonNull:   true,
+	}
+}
+
+func (compression ArchiveCompression) TypeDescription() string {
+	return "The compression of a tar archive."
+}
+
+func (compression ArchiveCompression) Decoder() dagql.InputDecoder {
+	return ArchiveCompressions
+}
+
+func (compression ArchiveCompression) ToLiteral() *idproto.Literal {
+	return ArchiveCompressions.Literal(compression)
+}
+
+// archiveName returns the conventional file name for an archive of the given
+// format and compression.
+func archiveName(format ArchiveFormat, compression ArchiveCompression) string {
+	if format == ArchiveFormatZip {
+		return "archive.zip"
+	}
+	switch compression {
+	case ArchiveCompressionGz:
+		return "archive.tar.gz"
+	case ArchiveCompressionZst:
+		return "archive.tar.zst"
+	default:
+		return "archive.tar"
+	}
+}
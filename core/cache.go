@@ -1,20 +1,31 @@
 package core
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
 // CacheVolume is a persistent volume with a globally scoped identifier.
 type CacheVolume struct {
+	Query *Query
+
 	Keys []string `json:"keys"`
+
+	// MaxSize and TTL limit the contents of the volume, which are wiped
+	// entirely when an exec mounts the volume if they exceed them. Zero means
+	// no limit.
+	MaxSize int64         `json:"max_size,omitempty"`
+	TTL     time.Duration `json:"ttl,omitempty"`
 }
 
 func (*CacheVolume) Type() *ast.Type {
@@ -50,6 +61,36 @@ func (cache *CacheVolume) Sum() string {
 	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
 }
 
+// Key returns the key the cache volume was created with.
+func (cache *CacheVolume) Key() string {
+	if len(cache.Keys) == 0 {
+		return ""
+	}
+	return cache.Keys[0]
+}
+
+// Usage returns the disk usage of the cache volume's contents, tagging them
+// with its key so they can be listed by key.
+func (cache *CacheVolume) Usage(ctx context.Context) (*buildkit.CacheMount, error) {
+	bk := cache.Query.Buildkit
+	if err := bk.RecordCacheMount(ctx, cache.Sum(), cache.Key()); err != nil {
+		return nil, err
+	}
+	mnt, err := bk.CacheMount(ctx, cache.Sum())
+	if err != nil {
+		return nil, err
+	}
+	mnt.Key = cache.Key()
+	return mnt, nil
+}
+
+// Prune removes the cache volume's contents, except for those currently
+// mounted by a running container.
+func (cache *CacheVolume) Prune(ctx context.Context) error {
+	_, err := cache.Query.Buildkit.PruneCacheMount(ctx, cache.Sum())
+	return err
+}
+
 type CacheSharingMode string
 
 var CacheSharingModes = dagql.NewEnum[CacheSharingMode]()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/images"
@@ -229,6 +231,10 @@ type ContainerMount struct {
 	// How to share the cache across concurrent runs.
 	CacheSharingMode CacheSharingMode `json:"cache_sharing,omitempty"`
 
+	// Limits of the cache volume, enforced when it's mounted by an exec.
+	CacheMaxSize int64         `json:"cache_max_size,omitempty"`
+	CacheTTL     time.Duration `json:"cache_ttl,omitempty"`
+
 	// Configure the mount as a tmpfs.
 	Tmpfs bool `json:"tmpfs,omitempty"`
 
@@ -534,6 +540,8 @@ func (container *Container) WithMountedCache(ctx context.Context, target string,
 		Target:           target,
 		CacheVolumeID:    cache.Sum(),
 		CacheSharingMode: sharingMode,
+		CacheMaxSize:     cache.MaxSize,
+		CacheTTL:         cache.TTL,
 	}
 
 	if source != nil {
@@ -1006,6 +1014,19 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 	runOpts = append(runOpts,
 		llb.AddMount(buildkit.MetaMountDestPath, metaSt, llb.SourcePath(metaSourcePath)))
 
+	limitsEnv, err := opts.ContainerExecLimits.env()
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range limitsEnv {
+		name, val, _ := strings.Cut(env, "=")
+		runOpts = append(runOpts, llb.AddEnv(name, val))
+	}
+
+	if opts.Expect != "" && opts.Expect != ReturnSuccess {
+		runOpts = append(runOpts, llb.AddEnv("_DAGGER_EXEC_EXPECT", string(opts.Expect)))
+	}
+
 	if opts.RedirectStdout != "" {
 		runOpts = append(runOpts, llb.AddEnv("_DAGGER_REDIRECT_STDOUT", opts.RedirectStdout))
 	}
@@ -1047,6 +1068,9 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 		if name == "_DAGGER_ENABLE_NESTING_IN_SAME_SESSION" && !opts.NestedInSameSession {
 			continue
 		}
+		if strings.HasPrefix(name, "_DAGGER_EXEC_") {
+			continue
+		}
 
 		runOpts = append(runOpts, llb.AddEnv(name, val))
 	}
@@ -1146,6 +1170,14 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 				return nil, errors.Errorf("invalid cache mount sharing mode %q", mnt.CacheSharingMode)
 			}
 
+			if mnt.CacheMaxSize > 0 || mnt.CacheTTL > 0 {
+				// enforced by the worker when the exec acquires the mount
+				buildkit.SetCacheMountLimits(mnt.CacheVolumeID, buildkit.CacheMountLimits{
+					MaxSize: mnt.CacheMaxSize,
+					TTL:     mnt.CacheTTL,
+				})
+			}
+
 			mountOpts = append(mountOpts, llb.AsPersistentCacheDir(mnt.CacheVolumeID, sharingMode))
 		}
 
@@ -1263,6 +1295,103 @@ func (container *Container) MetaFileContents(ctx context.Context, filePath strin
 	return string(content), nil
 }
 
+// StdoutStream follows the stdout of the last exec as it's written. If the
+// exec is cached, or its progress is clipped, the rest of its stdout is sent
+// once it completes.
+func (container *Container) StdoutStream(ctx context.Context) (dagql.Stream[dagql.String], error) {
+	if container.Meta == nil {
+		ctr, err := container.WithExec(ctx, ContainerExecOpts{})
+		if err != nil {
+			return dagql.Stream[dagql.String]{}, err
+		}
+		return ctr.StdoutStream(ctx)
+	}
+
+	chunks := make(chan dagql.String)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(chunks)
+		errs <- container.followStdout(ctx, chunks)
+	}()
+
+	return dagql.NewStream(func(ctx context.Context) (dagql.String, error) {
+		select {
+		case chunk, ok := <-chunks:
+			if ok {
+				return chunk, nil
+			}
+			if err := <-errs; err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}), nil
+}
+
+func (container *Container) followStdout(ctx context.Context, out chan<- dagql.String) error {
+	send := func(ctx context.Context, chunk string) error {
+		select {
+		case out <- dagql.NewString(chunk):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	followCtx, stopFollowing := context.WithCancel(ctx)
+	defer stopFollowing()
+
+	type followResult struct {
+		end buildkit.ExecOutputEnd
+		err error
+	}
+	// only touched by the follower until it's done
+	var sent int
+	followed := make(chan followResult, 1)
+	go func() {
+		end, err := container.Query.Buildkit.FollowExecOutput(followCtx, container.Meta, 1, func(data []byte) error {
+			if err := send(followCtx, string(data)); err != nil {
+				return err
+			}
+			sent += len(data)
+			return nil
+		})
+		followed <- followResult{end, err}
+	}()
+
+	// solving the exec's metadata reports its completion to the follower
+	stdout, err := container.MetaFileContents(ctx, "stdout")
+	if err != nil {
+		stopFollowing()
+		<-followed
+		return err
+	}
+	res := <-followed
+	if res.err != nil {
+		return res.err
+	}
+	if res.end.Cached || res.end.Clipped {
+		// the follower sent what the progress had up to the clip, if anything
+		return send(ctx, stdout[sent:])
+	}
+	return nil
+}
+
+// ExitCode returns the exit code of the last exec.
+func (container *Container) ExitCode(ctx context.Context) (int, error) {
+	contents, err := container.MetaFileContents(ctx, "exitCode")
+	if err != nil {
+		return 0, err
+	}
+	exitCode, err := strconv.Atoi(strings.TrimSpace(contents))
+	if err != nil {
+		return 0, fmt.Errorf("invalid exit code %q: %w", contents, err)
+	}
+	return exitCode, nil
+}
+
 func (container *Container) Publish(
 	ctx context.Context,
 	ref string,
@@ -1671,7 +1800,7 @@ func (container *Container) ImageRefOrErr(ctx context.Context) (string, error) {
 	return "", errors.Errorf("Image reference can only be retrieved immediately after the 'Container.From' call. Error in fetching imageRef as the container image is changed")
 }
 
-func (container *Container) Service(ctx context.Context) (*Service, error) {
+func (container *Container) Service(ctx context.Context, limits ContainerExecLimits) (*Service, error) {
 	if container.Meta == nil {
 		var err error
 		container, err = container.WithExec(ctx, ContainerExecOpts{})
@@ -1679,7 +1808,13 @@ func (container *Container) Service(ctx context.Context) (*Service, error) {
 			return nil, err
 		}
 	}
-	return container.Query.NewContainerService(container), nil
+	// validate the limits now rather than when the service starts
+	if _, err := limits.env(); err != nil {
+		return nil, err
+	}
+	svc := container.Query.NewContainerService(container)
+	svc.Limits = limits
+	return svc, nil
 }
 
 func (container *Container) ownership(ctx context.Context, owner string) (*Ownership, error) {
@@ -1758,6 +1893,12 @@ type ContainerExecOpts struct {
 	// Grant the process all root capabilities
 	InsecureRootCapabilities bool `default:"false"`
 
+	// Exit codes to accept from the command
+	Expect ReturnType `default:"SUCCESS"`
+
+	// Bound the time and resources available to the command
+	ContainerExecLimits
+
 	// (Internal-only) If this exec is for a module function, this digest will be set in the
 	// grpc context metadata for any api requests back to the engine. It's used by the API
 	// server to determine which schema to serve and other module context metadata.
@@ -1768,6 +1909,46 @@ type ContainerExecOpts struct {
 	NestedInSameSession bool `name:"-"`
 }
 
+// ContainerExecLimits bounds a command run in a container. Zero values mean
+// no limit.
+type ContainerExecLimits struct {
+	// Maximum time the command may run for, in seconds
+	Timeout int `default:"0"`
+
+	// Maximum amount of memory the container may use, in bytes
+	MemoryLimit int `default:"0"`
+
+	// Relative CPU weight of the container
+	CPUShares int `default:"0" name:"cpuShares"`
+
+	// Maximum number of processes the container may run
+	PidsLimit int `default:"0"`
+}
+
+// env returns the internal env vars that the shim reads to apply the limits.
+func (limits ContainerExecLimits) env() ([]string, error) {
+	var env []string
+	for _, limit := range []struct {
+		name  string
+		env   string
+		value int
+	}{
+		{"timeout", "_DAGGER_EXEC_TIMEOUT", limits.Timeout},
+		{"memoryLimit", "_DAGGER_EXEC_MEMORY_LIMIT", limits.MemoryLimit},
+		{"cpuShares", "_DAGGER_EXEC_CPU_SHARES", limits.CPUShares},
+		{"pidsLimit", "_DAGGER_EXEC_PIDS_LIMIT", limits.PidsLimit},
+	} {
+		if limit.value < 0 {
+			return nil, fmt.Errorf("%s must not be negative", limit.name)
+		}
+		if limit.value == 0 {
+			continue
+		}
+		env = append(env, limit.env+"="+strconv.Itoa(limit.value))
+	}
+	return env, nil
+}
+
 type BuildArg struct {
 	Name  string `field:"true" doc:"The build argument name."`
 	Value string `field:"true" doc:"The build argument value."`
@@ -1895,3 +2076,36 @@ func (proto ImageMediaTypes) Decoder() dagql.InputDecoder {
 func (proto ImageMediaTypes) ToLiteral() *idproto.Literal {
 	return ImageMediaTypesEnum.Literal(proto)
 }
+
+// ReturnType is the expected outcome of an exec.
+type ReturnType string
+
+var ReturnTypes = dagql.NewEnum[ReturnType]()
+
+var (
+	ReturnSuccess = ReturnTypes.Register("SUCCESS",
+		"A successful execution (exit code 0)")
+	ReturnFailure = ReturnTypes.Register("FAILURE",
+		"A failed execution (exit codes 1-127)")
+	ReturnAny = ReturnTypes.Register("ANY",
+		"Any execution (exit codes 0-127)")
+)
+
+func (expect ReturnType) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ReturnType",
+		NonNull:   true,
+	}
+}
+
+func (expect ReturnType) TypeDescription() string {
+	return "Expected return type of an execution"
+}
+
+func (expect ReturnType) Decoder() dagql.InputDecoder {
+	return ReturnTypes
+}
+
+func (expect ReturnType) ToLiteral() *idproto.Literal {
+	return ReturnTypes.Literal(expect)
+}
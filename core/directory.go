@@ -219,6 +219,36 @@ func (dir *Directory) Stat(ctx context.Context, bk *buildkit.Client, svcs *Servi
 }
 
 func (dir *Directory) Entries(ctx context.Context, src string) ([]string, error) {
+	entries, err := dir.readDir(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		paths = append(paths, entry.GetPath())
+	}
+
+	return paths, nil
+}
+
+// EntriesWithInfo returns the entries of the directory at src along with
+// their type, permissions, ownership and link target.
+func (dir *Directory) EntriesWithInfo(ctx context.Context, src string) ([]FileInfo, error) {
+	entries, err := dir.readDir(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []FileInfo{}
+	for _, entry := range entries {
+		infos = append(infos, NewFileInfo(entry))
+	}
+
+	return infos, nil
+}
+
+func (dir *Directory) readDir(ctx context.Context, src string) ([]*fstypes.Stat, error) {
 	src = path.Join(dir.Dir, src)
 
 	svcs := dir.Query.Services
@@ -244,24 +274,14 @@ func (dir *Directory) Entries(ctx context.Context, src string) ([]string, error)
 	// empty directory, i.e. llb.Scratch()
 	if ref == nil {
 		if clean := path.Clean(src); clean == "." || clean == "/" {
-			return []string{}, nil
+			return []*fstypes.Stat{}, nil
 		}
 		return nil, fmt.Errorf("%s: no such file or directory", src)
 	}
 
-	entries, err := ref.ReadDir(ctx, bkgw.ReadDirRequest{
+	return ref.ReadDir(ctx, bkgw.ReadDirRequest{
 		Path: src,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	paths := []string{}
-	for _, entry := range entries {
-		paths = append(paths, entry.GetPath())
-	}
-
-	return paths, nil
 }
 
 // Glob returns a list of files that matches the given pattern.
@@ -627,6 +647,47 @@ func (dir *Directory) WithNewDirectory(ctx context.Context, dest string, permiss
 	return dir, nil
 }
 
+// WithSymlink creates a symlink at linkName pointing to target. The target
+// is written as-is, and doesn't need to exist.
+func (dir *Directory) WithSymlink(ctx context.Context, target, linkName string) (*Directory, error) {
+	dir = dir.Clone()
+
+	if target == "" {
+		return nil, fmt.Errorf("symlink target must not be empty")
+	}
+
+	linkName = path.Clean(linkName)
+	if linkName == "." || linkName == ".." || strings.HasPrefix(linkName, "../") {
+		return nil, fmt.Errorf("cannot create symlink outside parent: %s", linkName)
+	}
+	if err := validateFileName(linkName); err != nil {
+		return nil, err
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	// Buildkit's file ops can't create symlinks, so have the shim create it in
+	// an otherwise empty container with the directory mounted.
+	const mnt = "/mnt"
+	linkPath := path.Join(mnt, dir.Dir, linkName)
+	execSt := llb.Scratch().Run(
+		llb.Args([]string{"symlink", target, linkPath}),
+		llb.AddEnv("_DAGGER_INTERNAL_COMMAND", ""),
+		llb.WithCustomNamef("%ssymlink %s -> %s", buildkit.InternalPrefix, linkName, target),
+	)
+	st = execSt.AddMount(mnt, st)
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
 func (dir *Directory) Diff(ctx context.Context, other *Directory) (*Directory, error) {
 	dir = dir.Clone()
 
@@ -675,27 +736,13 @@ func (dir *Directory) Without(ctx context.Context, path string) (*Directory, err
 	return dir, nil
 }
 
-func (dir *Directory) Export(ctx context.Context, destPath string) (rerr error) {
+func (dir *Directory) Export(ctx context.Context, destPath string, wipe bool) (rerr error) {
 	svcs := dir.Query.Services
 	bk := dir.Query.Buildkit
 
-	var defPB *pb.Definition
-	if dir.Dir != "" {
-		src, err := dir.State()
-		if err != nil {
-			return err
-		}
-		src = llb.Scratch().File(llb.Copy(src, dir.Dir, ".", &llb.CopyInfo{
-			CopyDirContentsOnly: true,
-		}))
-
-		def, err := src.Marshal(ctx, llb.Platform(dir.Platform.Spec()))
-		if err != nil {
-			return err
-		}
-		defPB = def.ToPB()
-	} else {
-		defPB = dir.LLB
+	defPB, err := dir.exportDefinition(ctx)
+	if err != nil {
+		return err
 	}
 
 	rec := progrock.FromContext(ctx)
@@ -712,7 +759,100 @@ func (dir *Directory) Export(ctx context.Context, destPath string) (rerr error)
 	}
 	defer detach()
 
-	return bk.LocalDirExport(ctx, defPB, destPath)
+	return bk.LocalDirExport(ctx, defPB, destPath, wipe)
+}
+
+// ExportDryRun returns the changes that Export would make to the host,
+// without making them.
+func (dir *Directory) ExportDryRun(ctx context.Context, destPath string, wipe bool) ([]string, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	defPB, err := dir.exportDefinition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	return bk.LocalDirExportDryRun(ctx, defPB, destPath, wipe)
+}
+
+// exportDefinition returns the definition of the directory with its contents
+// at the root.
+func (dir *Directory) exportDefinition(ctx context.Context) (*pb.Definition, error) {
+	if dir.Dir == "" {
+		return dir.LLB, nil
+	}
+
+	src, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+	src = llb.Scratch().File(llb.Copy(src, dir.Dir, ".", &llb.CopyInfo{
+		CopyDirContentsOnly: true,
+	}))
+
+	def, err := src.Marshal(ctx, llb.Platform(dir.Platform.Spec()))
+	if err != nil {
+		return nil, err
+	}
+	return def.ToPB(), nil
+}
+
+// AsArchive packs the entries of the directory matching filter into an
+// archive of the given format and compression.
+func (dir *Directory) AsArchive(ctx context.Context, format ArchiveFormat, compression ArchiveCompression, filter CopyFilter) (*File, error) {
+	if format == ArchiveFormatZip && compression != ArchiveCompressionNone {
+		return nil, fmt.Errorf("zip archives don't support %s compression", compression)
+	}
+
+	srcSt, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+	srcDir := dir.Dir
+	if len(filter.Include) > 0 || len(filter.Exclude) > 0 {
+		srcSt = llb.Scratch().File(llb.Copy(srcSt, dir.Dir, "/", &llb.CopyInfo{
+			CopyDirContentsOnly: true,
+			IncludePatterns:     filter.Include,
+			ExcludePatterns:     filter.Exclude,
+		}))
+		srcDir = "/"
+	}
+
+	// Buildkit's file ops can't create archives, so have the shim do it in an
+	// otherwise empty container.
+	const src, dest = "/src", "/out"
+	name := archiveName(format, compression)
+	execSt := llb.Scratch().Run(
+		llb.Args([]string{"archive", string(format), string(compression), src, path.Join(dest, name)}),
+		llb.AddEnv("_DAGGER_INTERNAL_COMMAND", ""),
+		llb.AddMount(src, srcSt, llb.SourcePath(srcDir), llb.Readonly),
+		llb.WithCustomNamef("%sarchive %s", buildkit.InternalPrefix, name),
+	)
+	st := execSt.AddMount(dest, llb.Scratch())
+
+	return NewFileSt(ctx, dir.Query, st, name, dir.Platform, dir.Services)
+}
+
+// Digest returns a content digest of the directory. See
+// buildkit.Client.ContentDigest for what it covers.
+func (dir *Directory) Digest(ctx context.Context, excludeMetadata bool) (digest.Digest, error) {
+	svcs := dir.Query.Services
+	bk := dir.Query.Buildkit
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return "", err
+	}
+	defer detach()
+
+	return bk.ContentDigest(ctx, dir.LLB, dir.Dir, excludeMetadata)
 }
 
 // Root removes any relative path from the directory.
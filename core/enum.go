@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ModuleEnumType is the ModType for an enum defined by a user module.
+type ModuleEnumType struct {
+	typeDef *EnumTypeDef
+	mod     *Module
+}
+
+var _ ModType = (*ModuleEnumType)(nil)
+
+func (t *ModuleEnumType) SourceMod() Mod {
+	return t.mod
+}
+
+func (t *ModuleEnumType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		return nil, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result value type %T for enum %q", value, t.typeDef.Name)
+	}
+	return (&ModuleEnum{TypeDef: t.typeDef}).Lookup(str)
+}
+
+func (t *ModuleEnumType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	enum, ok := value.(*ModuleEnum)
+	if !ok {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput cannot handle %T", t, value)
+	}
+	return enum.Value, nil
+}
+
+func (t *ModuleEnumType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind:   TypeDefKindEnum,
+		AsEnum: dagql.NonNull(t.typeDef),
+	}
+}
+
+// ModuleEnum is a value of an enum defined by a user module. The zero value
+// (with only TypeDef set) acts as the enum's scalar type in the schema.
+type ModuleEnum struct {
+	TypeDef *EnumTypeDef
+	Value   string
+}
+
+func (e *ModuleEnum) Install(dag *dagql.Server) {
+	dag.InstallScalar(e)
+}
+
+var _ dagql.ScalarType = (*ModuleEnum)(nil)
+
+func (e *ModuleEnum) TypeName() string {
+	return e.TypeDef.Name
+}
+
+func (e *ModuleEnum) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: e.TypeName(),
+		NonNull:   true,
+	}
+}
+
+var _ dagql.Definitive = (*ModuleEnum)(nil)
+
+func (e *ModuleEnum) TypeDefinition() *ast.Definition {
+	return &ast.Definition{
+		Kind:        ast.Enum,
+		Name:        e.TypeName(),
+		Description: formatGqlDescription(e.TypeDef.Description),
+		EnumValues:  e.PossibleValues(),
+	}
+}
+
+func (e *ModuleEnum) PossibleValues() ast.EnumValueList {
+	var values ast.EnumValueList
+	for _, val := range e.TypeDef.Values {
+		values = append(values, &ast.EnumValueDefinition{
+			Name:        val.Name,
+			Description: formatGqlDescription(val.Description),
+		})
+	}
+	return values
+}
+
+// Lookup returns the enum value with the given name.
+//
+// References to an enum (e.g. in a function's argument types) don't carry the
+// enum's values, so they're resolved to the full definition before decoding
+// any value against them.
+func (e *ModuleEnum) Lookup(val string) (*ModuleEnum, error) {
+	if _, ok := e.TypeDef.ValueByName(val); !ok {
+		return nil, fmt.Errorf("invalid value %q for enum %q", val, e.TypeDef.Name)
+	}
+	return &ModuleEnum{
+		TypeDef: e.TypeDef,
+		Value:   val,
+	}, nil
+}
+
+var _ dagql.InputDecoder = (*ModuleEnum)(nil)
+
+func (e *ModuleEnum) DecodeInput(val any) (dagql.Input, error) {
+	switch x := val.(type) {
+	case string:
+		return e.Lookup(x)
+	case *ModuleEnum:
+		return e.Lookup(x.Value)
+	default:
+		return nil, fmt.Errorf("cannot create enum %q from %T", e.TypeDef.Name, x)
+	}
+}
+
+var _ dagql.Input = (*ModuleEnum)(nil)
+
+func (e *ModuleEnum) Decoder() dagql.InputDecoder {
+	return &ModuleEnum{TypeDef: e.TypeDef}
+}
+
+func (e *ModuleEnum) ToLiteral() *idproto.Literal {
+	return &idproto.Literal{
+		Value: &idproto.Literal_Enum{
+			Enum: e.Value,
+		},
+	}
+}
+
+func (e *ModuleEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Value)
+}
@@ -280,6 +280,57 @@ func (file *File) Export(ctx context.Context, dest string, allowParentDirPath bo
 	return bk.LocalFileExport(ctx, def.ToPB(), dest, file.File, allowParentDirPath)
 }
 
+// Extract extracts the file as an archive into a new directory, keeping only
+// the entries matching filter. If format is nil, it is detected from the
+// archive's contents; the compression of tar archives is always detected.
+func (file *File) Extract(ctx context.Context, format *ArchiveFormat, filter CopyFilter) (*Directory, error) {
+	fileSt, err := file.State()
+	if err != nil {
+		return nil, err
+	}
+
+	var formatArg string
+	if format != nil {
+		formatArg = string(*format)
+	}
+
+	// Buildkit's file ops can only unpack some tar archives, so have the shim
+	// do it in an otherwise empty container.
+	const src, dest = "/src", "/out"
+	execSt := llb.Scratch().Run(
+		llb.Args([]string{"extract", formatArg, path.Join(src, file.File), dest}),
+		llb.AddEnv("_DAGGER_INTERNAL_COMMAND", ""),
+		llb.AddMount(src, fileSt, llb.Readonly),
+		llb.WithCustomNamef("%sextract %s", buildkit.InternalPrefix, file.File),
+	)
+	st := execSt.AddMount(dest, llb.Scratch())
+
+	if len(filter.Include) > 0 || len(filter.Exclude) > 0 {
+		st = llb.Scratch().File(llb.Copy(st, "/", "/", &llb.CopyInfo{
+			CopyDirContentsOnly: true,
+			IncludePatterns:     filter.Include,
+			ExcludePatterns:     filter.Exclude,
+		}))
+	}
+
+	return NewDirectorySt(ctx, file.Query, st, "/", file.Platform, file.Services)
+}
+
+// Digest returns a content digest of the file. See
+// buildkit.Client.ContentDigest for what it covers.
+func (file *File) Digest(ctx context.Context, excludeMetadata bool) (digest.Digest, error) {
+	svcs := file.Query.Services
+	bk := file.Query.Buildkit
+
+	detach, _, err := svcs.StartBindings(ctx, file.Services)
+	if err != nil {
+		return "", err
+	}
+	defer detach()
+
+	return bk.ContentDigest(ctx, file.LLB, file.File, excludeMetadata)
+}
+
 // bkRef returns the buildkit reference from the solved def.
 func bkRef(ctx context.Context, bk *buildkit.Client, def *pb.Definition) (bkgw.Reference, error) {
 	res, err := bk.Solve(ctx, bkgw.SolveRequest{
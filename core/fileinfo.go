@@ -0,0 +1,89 @@
+package core
+
+import (
+	"io/fs"
+	"path"
+
+	fstypes "github.com/tonistiigi/fsutil/types"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+)
+
+// FileInfo describes an entry in a directory, without following symlinks.
+type FileInfo struct {
+	Name        string   `field:"true" doc:"The base name of the entry."`
+	FileType    FileType `field:"true" doc:"The type of the entry."`
+	Permissions int      `field:"true" doc:"The permission bits of the entry (e.g., 0644)."`
+	Size        int      `field:"true" doc:"The size of the entry in bytes."`
+	UID         int      `field:"true" name:"uid" doc:"The user ID owning the entry."`
+	GID         int      `field:"true" name:"gid" doc:"The group ID owning the entry."`
+	LinkTarget  string   `field:"true" doc:"The path the entry points to if it's a symlink, or empty otherwise."`
+}
+
+func NewFileInfo(stat *fstypes.Stat) FileInfo {
+	mode := fs.FileMode(stat.Mode)
+
+	fileType := FileTypeUnknown
+	switch mode.Type() {
+	case 0:
+		fileType = FileTypeRegular
+	case fs.ModeDir:
+		fileType = FileTypeDirectory
+	case fs.ModeSymlink:
+		fileType = FileTypeSymlink
+	}
+
+	return FileInfo{
+		Name:        path.Base(stat.Path),
+		FileType:    fileType,
+		Permissions: int(mode.Perm()),
+		Size:        int(stat.Size_),
+		UID:         int(stat.Uid),
+		GID:         int(stat.Gid),
+		LinkTarget:  stat.Linkname,
+	}
+}
+
+func (FileInfo) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FileInfo",
+		NonNull:   true,
+	}
+}
+
+func (FileInfo) TypeDescription() string {
+	return "Information about a file, directory or symlink."
+}
+
+// FileType is the type of an entry in a directory.
+type FileType string
+
+var FileTypes = dagql.NewEnum[FileType]()
+
+var (
+	FileTypeRegular   = FileTypes.Register("REGULAR_TYPE", "A regular file.")
+	FileTypeDirectory = FileTypes.Register("DIRECTORY_TYPE", "A directory.")
+	FileTypeSymlink   = FileTypes.Register("SYMLINK_TYPE", "A symbolic link.")
+	FileTypeUnknown   = FileTypes.Register("UNKNOWN_TYPE", "Any other kind of entry, e.g. a device or a named pipe.")
+)
+
+func (ft FileType) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FileType",
+		NonNull:   true,
+	}
+}
+
+func (ft FileType) TypeDescription() string {
+	return "The type of an entry in a directory."
+}
+
+func (ft FileType) Decoder() dagql.InputDecoder {
+	return FileTypes
+}
+
+func (ft FileType) ToLiteral() *idproto.Literal {
+	return FileTypes.Literal(ft)
+}
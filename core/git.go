@@ -2,9 +2,11 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strings"
 
 	"github.com/dagger/dagger/engine"
-	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/sources/gitdns"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/pkg/errors"
@@ -21,6 +23,8 @@ type GitRepository struct {
 	SSHKnownHosts string  `json:"sshKnownHosts"`
 	SSHAuthSocket *Socket `json:"sshAuthSocket"`
 
+	HTTPAuthToken *Secret `json:"httpAuthToken"`
+
 	Services ServiceBindings `json:"services"`
 	Platform Platform        `json:"platform,omitempty"`
 }
@@ -54,16 +58,71 @@ func (*GitRef) TypeDescription() string {
 	return "A git ref (tag, branch, or commit)."
 }
 
-func (ref *GitRef) Tree(ctx context.Context) (*Directory, error) {
-	bk := ref.Query.Buildkit
-	st := ref.getState(ctx, bk)
-	return NewDirectorySt(ctx, ref.Query, *st, "", ref.Repo.Platform, ref.Repo.Services)
+// Branches returns the names of the repository's branches.
+func (repo *GitRepository) Branches(ctx context.Context) ([]string, error) {
+	refs, err := repo.listRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return refNames(refs, "refs/heads/", ""), nil
+}
+
+// Tags returns the names of the repository's tags, optionally filtered by a
+// glob pattern.
+func (repo *GitRepository) Tags(ctx context.Context, pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	refs, err := repo.listRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return refNames(refs, "refs/tags/", pattern), nil
+}
+
+func (repo *GitRepository) listRefs(ctx context.Context) ([]byte, error) {
+	st := repo.getState(ctx, "", gitdns.DaggerGitURLHack{ListRefs: true})
+	file, err := NewFileSt(ctx, repo.Query, st, gitdns.RefsFilename, repo.Platform, repo.Services)
+	if err != nil {
+		return nil, err
+	}
+	return file.Contents(ctx)
+}
+
+// refNames returns the names of the refs with the given prefix in the output
+// of git ls-remote, with the prefix trimmed, skipping those that don't match
+// pattern if it's set.
+func refNames(lsRemote []byte, prefix, pattern string) []string {
+	names := []string{}
+	for _, line := range strings.Split(string(lsRemote), "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok || !strings.HasPrefix(ref, prefix) {
+			continue
+		}
+		// skip the commits annotated tags point to
+		if strings.HasSuffix(ref, "^{}") {
+			continue
+		}
+		name := strings.TrimPrefix(ref, prefix)
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, name); !ok {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (ref *GitRef) Tree(ctx context.Context, opts gitdns.CheckoutOpts) (*Directory, error) {
+	st := ref.Repo.getState(ctx, ref.Ref, gitdns.DaggerGitURLHack{CheckoutOpts: opts})
+	return NewDirectorySt(ctx, ref.Query, st, "", ref.Repo.Platform, ref.Repo.Services)
 }
 
 func (ref *GitRef) Commit(ctx context.Context) (string, error) {
 	bk := ref.Query.Buildkit
-	st := ref.getState(ctx, bk)
-	p, err := resolveProvenance(ctx, bk, *st)
+	st := ref.Repo.getState(ctx, ref.Ref, gitdns.DaggerGitURLHack{})
+	p, err := resolveProvenance(ctx, bk, st)
 	if err != nil {
 		return "", err
 	}
@@ -73,28 +132,35 @@ func (ref *GitRef) Commit(ctx context.Context) (string, error) {
 	return p.Sources.Git[0].Commit, nil
 }
 
-func (ref *GitRef) getState(ctx context.Context, bk *buildkit.Client) *llb.State {
+// getState returns the state for ref, fetched with the repository's options.
+// The remote and client IDs of hack are filled in; the rest of its options
+// are passed to the git source as-is.
+func (repo *GitRepository) getState(ctx context.Context, ref string, hack gitdns.DaggerGitURLHack) llb.State {
 	opts := []llb.GitOption{}
 
-	if ref.Repo.KeepGitDir {
+	if repo.KeepGitDir {
 		opts = append(opts, llb.KeepGitDir())
 	}
-	if ref.Repo.SSHKnownHosts != "" {
-		opts = append(opts, llb.KnownSSHHosts(ref.Repo.SSHKnownHosts))
+	if repo.SSHKnownHosts != "" {
+		opts = append(opts, llb.KnownSSHHosts(repo.SSHKnownHosts))
+	}
+	if repo.SSHAuthSocket != nil {
+		opts = append(opts, llb.MountSSHSock(repo.SSHAuthSocket.SSHID()))
 	}
-	if ref.Repo.SSHAuthSocket != nil {
-		opts = append(opts, llb.MountSSHSock(ref.Repo.SSHAuthSocket.SSHID()))
+	if repo.HTTPAuthToken != nil {
+		opts = append(opts, llb.AuthTokenSecret(repo.HTTPAuthToken.Name))
 	}
 
-	useDNS := len(ref.Repo.Services) > 0
+	useDNS := len(repo.Services) > 0
 
 	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
 	if err == nil && !useDNS {
 		useDNS = len(clientMetadata.ParentClientIDs) > 0
 	}
 
-	var st llb.State
-	if useDNS {
+	hack.Remote = repo.URL
+	checkout := hack.CheckoutOpts
+	if useDNS || hack.ListRefs || checkout.Depth > 0 || len(checkout.SparsePaths) > 0 || checkout.SkipSubmodules || checkout.LFS {
 		// NB: only configure search domains if we're directly using a service, or
 		// if we're nested beneath another search domain.
 		//
@@ -103,9 +169,10 @@ func (ref *GitRef) getState(ctx context.Context, bk *buildkit.Client) *llb.State
 		// networks API cap.
 		//
 		// TODO: add API cap
-		st = gitdns.State(ref.Repo.URL, ref.Ref, clientMetadata.ClientIDs(), opts...)
-	} else {
-		st = llb.Git(ref.Repo.URL, ref.Ref, opts...)
+		if useDNS {
+			hack.ClientIDs = clientMetadata.ClientIDs()
+		}
+		return gitdns.StateWithHack(hack, ref, opts...)
 	}
-	return &st
+	return llb.Git(repo.URL, ref, opts...)
 }
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefNames(t *testing.T) {
+	lsRemote := []byte("" +
+		"1111111111111111111111111111111111111111\trefs/heads/main\n" +
+		"2222222222222222222222222222222222222222\trefs/heads/feature/x\n" +
+		"3333333333333333333333333333333333333333\trefs/tags/v1.0.0\n" +
+		"4444444444444444444444444444444444444444\trefs/tags/v1.0.0^{}\n" +
+		"5555555555555555555555555555555555555555\trefs/tags/v2.0.0\n")
+
+	require.Equal(t, []string{"main", "feature/x"}, refNames(lsRemote, "refs/heads/", ""))
+	require.Equal(t, []string{"v1.0.0", "v2.0.0"}, refNames(lsRemote, "refs/tags/", ""))
+	require.Equal(t, []string{"v1.0.0"}, refNames(lsRemote, "refs/tags/", "v1.*"))
+	require.Equal(t, []string{}, refNames(lsRemote, "refs/tags/", "v3.*"))
+	require.Equal(t, []string{}, refNames(nil, "refs/heads/", ""))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/containerd/containerd/labels"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/engine/buildkit"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vito/progrock"
@@ -66,6 +67,8 @@ func (host *Host) Directory(
 	dirPath string,
 	pipelineNamePrefix string,
 	filter CopyFilter,
+	daggerignore bool,
+	gitignore bool,
 ) (dagql.Instance[*Directory], error) {
 	var i dagql.Instance[*Directory]
 	// TODO: enforcement that requester session is granted access to source session at this path
@@ -74,12 +77,26 @@ func (host *Host) Directory(
 	pipelineName := fmt.Sprintf("%s %s", pipelineNamePrefix, dirPath)
 	ctx, subRecorder := progrock.WithGroup(ctx, pipelineName, progrock.Weak())
 
+	var ignoreFiles []string
+	if daggerignore {
+		ignoreFiles = append(ignoreFiles, buildkit.DaggerIgnoreFilename)
+	}
+	if gitignore {
+		ignoreFiles = append(ignoreFiles, buildkit.GitIgnoreFilename)
+	}
+	excludes, err := host.Query.Buildkit.ReadIgnorePatterns(ctx, dirPath, ignoreFiles...)
+	if err != nil {
+		return i, fmt.Errorf("host directory %s: %w", dirPath, err)
+	}
+	// explicit excludes come last so they can't be negated by an ignore file
+	excludes = append(excludes, filter.Exclude...)
+
 	_, desc, err := host.Query.Buildkit.LocalImport(
 		ctx,
 		subRecorder,
 		host.Query.Platform.Spec(),
 		dirPath,
-		filter.Exclude,
+		excludes,
 		filter.Include,
 	)
 	if err != nil {
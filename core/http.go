@@ -0,0 +1,15 @@
+package core
+
+// HTTPHeader is a header sent with an HTTP request.
+type HTTPHeader struct {
+	Name  string `field:"true" doc:"The header name."`
+	Value string `field:"true" doc:"The header value."`
+}
+
+func (HTTPHeader) TypeName() string {
+	return "HTTPHeader"
+}
+
+func (HTTPHeader) TypeDescription() string {
+	return "Key value object that represents an HTTP request header."
+}
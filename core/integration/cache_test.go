@@ -145,3 +145,87 @@ func TestLocalImportCacheReuse(t *testing.T) {
 
 	require.Equal(t, out1, out2)
 }
+
+func TestCacheVolumeUsage(t *testing.T) {
+	t.Parallel()
+
+	key := identity.NewID()
+	fill := func(ctx context.Context, t *testing.T, c *dagger.Client, cache *dagger.CacheVolume) {
+		_, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", cache).
+			WithEnvVariable("BUST", identity.NewID()).
+			WithExec([]string{"sh", "-c", "head -c 1048576 /dev/urandom > /cache/data"}).
+			Sync(ctx)
+		require.NoError(t, err)
+	}
+	exists := func(ctx context.Context, t *testing.T, c *dagger.Client, cache *dagger.CacheVolume) bool {
+		out, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", cache).
+			WithEnvVariable("BUST", identity.NewID()).
+			WithExec([]string{"sh", "-c", "test -f /cache/data && echo yes || echo no"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		return strings.TrimSpace(out) == "yes"
+	}
+
+	t.Run("size and listing", func(t *testing.T) {
+		c, ctx := connect(t)
+		cache := c.CacheVolume(key)
+
+		size, err := cache.Size(ctx)
+		require.NoError(t, err)
+		require.Zero(t, size)
+
+		fill(ctx, t, c, cache)
+
+		size, err = cache.Size(ctx)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, size, 1048576)
+
+		caches, err := c.CacheVolumes(ctx)
+		require.NoError(t, err)
+		var keys []string
+		for _, cache := range caches {
+			k, err := cache.Key(ctx)
+			require.NoError(t, err)
+			keys = append(keys, k)
+		}
+		require.Contains(t, keys, key)
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		c, ctx := connect(t)
+
+		// building an exec without running it doesn't enforce the limits
+		_, err := c.Container().From(alpineImage).
+			WithMountedCache("/cache", c.CacheVolume(key, dagger.CacheVolumeOpts{
+				MaxSize: 1024,
+			})).
+			WithExec([]string{"true"}).
+			ID(ctx)
+		require.NoError(t, err)
+
+		require.True(t, exists(ctx, t, c, c.CacheVolume(key, dagger.CacheVolumeOpts{
+			MaxSize: 10 * 1048576,
+		})))
+
+		c, ctx = connect(t)
+		require.False(t, exists(ctx, t, c, c.CacheVolume(key, dagger.CacheVolumeOpts{
+			MaxSize: 1024,
+		})))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		c, ctx := connect(t)
+		cache := c.CacheVolume(key)
+		fill(ctx, t, c, cache)
+
+		_, err := cache.Prune(ctx)
+		require.NoError(t, err)
+
+		size, err := cache.Size(ctx)
+		require.NoError(t, err)
+		require.Zero(t, size)
+		require.False(t, exists(ctx, t, c, cache))
+	})
+}
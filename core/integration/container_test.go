@@ -5,7 +5,9 @@ import (
 	_ "embed"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
@@ -371,6 +373,36 @@ func TestContainerExecStdoutStderr(t *testing.T) {
 	require.Equal(t, res.Container.From.WithExec.Stderr, "goodbye\n")
 }
 
+func TestContainerStdoutSubscription(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	ctr := c.Container().From(alpineImage).
+		WithEnvVariable("CACHEBUSTER", identity.NewID()).
+		WithExec([]string{"sh", "-c", "for i in 1 2 3; do echo $i; sleep 0.1; done"})
+
+	follow := func() string {
+		sub, err := c.ContainerStdout(ctx, ctr)
+		require.NoError(t, err)
+		defer sub.Close()
+
+		var out string
+		for {
+			chunk, err := sub.Next()
+			if errors.Is(err, io.EOF) {
+				return out
+			}
+			require.NoError(t, err)
+			out += chunk
+		}
+	}
+
+	require.Equal(t, "1\n2\n3\n", follow())
+
+	// the cached output is sent all at once
+	require.Equal(t, "1\n2\n3\n", follow())
+}
+
 func TestContainerExecStdin(t *testing.T) {
 	t.Parallel()
 
@@ -3118,6 +3150,192 @@ func TestContainerExecError(t *testing.T) {
 	})
 }
 
+func TestContainerExecLimits(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"sh", "-c", "echo hey; sleep 300"}, dagger.ContainerWithExecOpts{
+				Timeout: 2,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.True(t, exErr.TimedOut())
+		require.False(t, exErr.OOMKilled())
+		require.Equal(t, "hey", exErr.Stdout)
+	})
+
+	t.Run("finishes within timeout", func(t *testing.T) {
+		out, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"echo", "hey"}, dagger.ContainerWithExecOpts{
+				Timeout: 60,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hey\n", out)
+	})
+
+	t.Run("memory limit", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			// allocate well past the limit
+			WithExec([]string{"sh", "-c", "head -c 256m /dev/zero | tail"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: 32 * 1024 * 1024,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.True(t, exErr.OOMKilled())
+		require.False(t, exErr.TimedOut())
+	})
+
+	t.Run("non-zero exit has no reason", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"sh", "-c", "exit 3"}, dagger.ContainerWithExecOpts{
+				Timeout:     60,
+				MemoryLimit: 32 * 1024 * 1024,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.Equal(t, 3, exErr.ExitCode)
+		require.Empty(t, exErr.Reason)
+	})
+
+	t.Run("pids limit", func(t *testing.T) {
+		out, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 42,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "42", strings.TrimSpace(out))
+	})
+
+	t.Run("cpu shares", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				CPUShares: 512,
+			}).
+			Sync(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("negative limits are rejected", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				PidsLimit: -1,
+			}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "pidsLimit must not be negative")
+	})
+}
+
+func TestContainerExecExpect(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	t.Run("exit code of successful exec", func(t *testing.T) {
+		code, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"true"}).
+			ExitCode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+	})
+
+	t.Run("expect failure", func(t *testing.T) {
+		ctr := c.Container().
+			From(alpineImage).
+			WithExec([]string{"sh", "-c", "echo out; echo err >&2; echo data > /result; exit 3"}, dagger.ContainerWithExecOpts{
+				Expect: dagger.Failure,
+			})
+
+		code, err := ctr.ExitCode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+
+		stdout, err := ctr.Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "out\n", stdout)
+
+		stderr, err := ctr.Stderr(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "err\n", stderr)
+
+		// the filesystem is usable by the rest of the pipeline
+		out, err := ctr.WithExec([]string{"cat", "/result"}).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "data\n", out)
+	})
+
+	t.Run("expect failure but succeeds", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				Expect: dagger.Failure,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.Equal(t, 0, exErr.ExitCode)
+	})
+
+	t.Run("expect any", func(t *testing.T) {
+		for _, exit := range []int{0, 1, 42} {
+			code, err := c.Container().
+				From(alpineImage).
+				WithExec([]string{"sh", "-c", fmt.Sprintf("exit %d", exit)}, dagger.ContainerWithExecOpts{
+					Expect: dagger.Any,
+				}).
+				ExitCode(ctx)
+			require.NoError(t, err)
+			require.Equal(t, exit, code)
+		}
+	})
+
+	t.Run("expect success", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"sh", "-c", "exit 1"}, dagger.ContainerWithExecOpts{
+				Expect: dagger.Success,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.Equal(t, 1, exErr.ExitCode)
+	})
+
+	t.Run("limits are enforced regardless of expect", func(t *testing.T) {
+		_, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"sleep", "300"}, dagger.ContainerWithExecOpts{
+				Expect:  dagger.Any,
+				Timeout: 2,
+			}).
+			Sync(ctx)
+
+		var exErr *dagger.ExecError
+		require.ErrorAs(t, err, &exErr)
+		require.True(t, exErr.TimedOut())
+	})
+}
+
 func TestContainerWithRegistryAuth(t *testing.T) {
 	t.Parallel()
 
@@ -2,12 +2,14 @@ package core
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 
 	"dagger.io/dagger"
@@ -340,6 +342,107 @@ func TestDirectoryWithNewDirectory(t *testing.T) {
 	})
 }
 
+func TestDirectoryWithSymlink(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("lib/libfoo.so.1", "foo").
+		WithSymlink("libfoo.so.1", "lib/libfoo.so").
+		WithSymlink("does-not-exist", "dangling")
+
+	out, err := c.Container().From(alpineImage).
+		WithMountedDirectory("/dir", dir).
+		WithWorkdir("/dir").
+		WithExec([]string{"sh", "-c", "readlink lib/libfoo.so && cat lib/libfoo.so && readlink dangling"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "libfoo.so.1\nfoo\ndoes-not-exist\n", out)
+
+	t.Run("does not permit creating symlink outside of root", func(t *testing.T) {
+		_, err := dir.Directory("lib").WithSymlink("libfoo.so.1", "../libfoo.so").Sync(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("requires a target", func(t *testing.T) {
+		_, err := dir.WithSymlink("", "empty").Sync(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestDirectoryStat(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("some-file", "some-content", dagger.DirectoryWithNewFileOpts{
+			Permissions: 0o600,
+		}).
+		WithNewDirectory("some-dir").
+		WithSymlink("some-file", "some-link")
+
+	info := dir.Stat("some-file")
+	fileType, err := info.FileType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.RegularType, fileType)
+	perms, err := info.Permissions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0o600, perms)
+	size, err := info.Size(ctx)
+	require.NoError(t, err)
+	require.Equal(t, len("some-content"), size)
+
+	fileType, err = dir.Stat("some-dir").FileType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.DirectoryType, fileType)
+
+	info = dir.Stat("some-link")
+	fileType, err = info.FileType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.SymlinkType, fileType)
+	target, err := info.LinkTarget(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "some-file", target)
+
+	fileType, err = dir.File("some-file").Stat().FileType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dagger.RegularType, fileType)
+
+	t.Run("entries with info", func(t *testing.T) {
+		infos, err := dir.EntriesWithInfo(ctx)
+		require.NoError(t, err)
+		require.Len(t, infos, 3)
+
+		types := map[string]dagger.FileType{}
+		for _, info := range infos {
+			name, err := info.Name(ctx)
+			require.NoError(t, err)
+			fileType, err := info.FileType(ctx)
+			require.NoError(t, err)
+			types[name] = fileType
+		}
+		require.Equal(t, map[string]dagger.FileType{
+			"some-file": dagger.RegularType,
+			"some-dir":  dagger.DirectoryType,
+			"some-link": dagger.SymlinkType,
+		}, types)
+	})
+
+	t.Run("ownership", func(t *testing.T) {
+		ctrDir := c.Container().From(alpineImage).
+			WithExec([]string{"sh", "-c", "mkdir /out && echo hi > /out/owned && chown 1234:5678 /out/owned"}).
+			Directory("/out")
+
+		info := ctrDir.Stat("owned")
+		uid, err := info.UID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1234, uid)
+		gid, err := info.Gid(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 5678, gid)
+	})
+}
+
 func TestDirectoryWithFile(t *testing.T) {
 	t.Parallel()
 	c, ctx := connect(t)
@@ -592,6 +695,114 @@ func TestDirectoryDiff(t *testing.T) {
 	*/
 }
 
+func TestDirectoryDigest(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	scratch := c.Directory().
+		WithNewFile("a.txt", "a").
+		WithNewFile("sub/b.txt", "b")
+
+	// same contents, but built differently and at different times
+	built := c.Container().From(alpineImage).
+		WithExec([]string{"sh", "-c", "mkdir -p /out/sub && echo -n a > /out/a.txt && echo -n b > /out/sub/b.txt"}).
+		Directory("/out")
+
+	scratchDigest, err := scratch.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+	require.NoError(t, err)
+	builtDigest, err := built.Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+	require.NoError(t, err)
+	require.Equal(t, scratchDigest, builtDigest)
+
+	t.Run("subdirectory digest ignores its location", func(t *testing.T) {
+		nested, err := c.Directory().
+			WithDirectory("nested/deeper", scratch).
+			Directory("nested/deeper").
+			Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.Equal(t, scratchDigest, nested)
+	})
+
+	t.Run("different contents", func(t *testing.T) {
+		other, err := scratch.WithNewFile("sub/b.txt", "c").
+			Digest(ctx, dagger.DirectoryDigestOpts{ExcludeMetadata: true})
+		require.NoError(t, err)
+		require.NotEqual(t, scratchDigest, other)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		withMeta, err := scratch.Digest(ctx)
+		require.NoError(t, err)
+		chmodded, err := scratch.WithNewFile("a.txt", "a", dagger.DirectoryWithNewFileOpts{
+			Permissions: 0o600,
+		}).Digest(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, withMeta, chmodded)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		dgst, err := c.Directory().Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, digest.FromBytes(nil).String(), dgst)
+	})
+}
+
+func TestDirectoryAsArchive(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("a.txt", "a").
+		WithNewFile("sub/b.txt", "b").
+		WithNewFile("node_modules/c.txt", "c")
+
+	for _, tc := range []struct {
+		opts dagger.DirectoryAsArchiveOpts
+		name string
+		cmd  []string
+	}{
+		{dagger.DirectoryAsArchiveOpts{}, "archive.tar", []string{"tar", "tf"}},
+		{dagger.DirectoryAsArchiveOpts{Compression: dagger.Gz}, "archive.tar.gz", []string{"tar", "tzf"}},
+		{dagger.DirectoryAsArchiveOpts{Format: dagger.Zip}, "archive.zip", []string{"unzip", "-Z1"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.Exclude = []string{"node_modules"}
+			archive := dir.AsArchive(tc.opts)
+
+			name, err := archive.Name(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tc.name, name)
+
+			out, err := c.Container().From(alpineImage).
+				WithExec([]string{"apk", "add", "unzip"}).
+				WithMountedFile("/"+tc.name, archive).
+				WithExec(append(tc.cmd, "/"+tc.name)).
+				Stdout(ctx)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"a.txt", "sub/", "sub/b.txt"}, strings.Fields(out))
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, compression := range []dagger.ArchiveCompression{dagger.None, dagger.Gz, dagger.Zst} {
+			extracted := dir.AsArchive(dagger.DirectoryAsArchiveOpts{Compression: compression}).Extract()
+
+			contents, err := extracted.File("sub/b.txt").Contents(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "b", contents)
+		}
+	})
+
+	t.Run("zip with compression", func(t *testing.T) {
+		_, err := dir.AsArchive(dagger.DirectoryAsArchiveOpts{
+			Format:      dagger.Zip,
+			Compression: dagger.Gz,
+		}).Sync(ctx)
+		require.Error(t, err)
+	})
+}
+
 func TestDirectoryExport(t *testing.T) {
 	t.Parallel()
 
@@ -629,6 +840,82 @@ func TestDirectoryExport(t *testing.T) {
 	})
 }
 
+func TestDirectoryExportWipe(t *testing.T) {
+	t.Parallel()
+
+	dest := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "stale.txt"), []byte("stale"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "b.txt"), []byte("old"), 0o600))
+
+	c, ctx := connect(t)
+
+	dir := c.Directory().
+		WithNewFile("a.txt", "a").
+		WithNewFile("b.txt", "b").
+		WithNewFile("sub/c.txt", "c")
+
+	t.Run("dry run", func(t *testing.T) {
+		changes, err := dir.ExportDryRun(ctx, dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"A a.txt", "M b.txt", "A sub", "A sub/c.txt"}, changes)
+
+		changes, err = dir.ExportDryRun(ctx, dest, dagger.DirectoryExportDryRunOpts{Wipe: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"A a.txt", "M b.txt", "D stale.txt", "A sub", "A sub/c.txt"}, changes)
+
+		// nothing was written
+		entries, err := ls(dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"b.txt", "stale.txt"}, entries)
+	})
+
+	t.Run("dry run to new dir", func(t *testing.T) {
+		changes, err := dir.ExportDryRun(ctx, filepath.Join(dest, "new"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"A a.txt", "A b.txt", "A sub", "A sub/c.txt"}, changes)
+	})
+
+	t.Run("wipe", func(t *testing.T) {
+		ok, err := dir.Export(ctx, dest, dagger.DirectoryExportOpts{Wipe: true})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		entries, err := ls(dest)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a.txt", "b.txt", "sub"}, entries)
+
+		content, err := os.ReadFile(filepath.Join(dest, "b.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(content))
+
+		changes, err := dir.ExportDryRun(ctx, dest, dagger.DirectoryExportDryRunOpts{Wipe: true})
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("wipe of the working directory is refused", func(t *testing.T) {
+		for _, path := range []string{".", ".."} {
+			_, err := dir.Export(ctx, path, dagger.DirectoryExportOpts{Wipe: true})
+			require.ErrorContains(t, err, "contains the current working directory")
+
+			_, err = dir.ExportDryRun(ctx, path, dagger.DirectoryExportDryRunOpts{Wipe: true})
+			require.ErrorContains(t, err, "contains the current working directory")
+		}
+	})
+
+	t.Run("wipe of a git repository is refused", func(t *testing.T) {
+		repo := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0o755))
+
+		_, err := dir.Export(ctx, repo, dagger.DirectoryExportOpts{Wipe: true})
+		require.ErrorContains(t, err, "root of a git repository")
+
+		entries, err := ls(repo)
+		require.NoError(t, err)
+		require.Equal(t, []string{".git"}, entries)
+	})
+}
+
 func TestDirectoryDockerBuild(t *testing.T) {
 	t.Parallel()
 	c, ctx := connect(t)
@@ -15,6 +15,7 @@ import (
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/internal/testutil"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -112,6 +113,78 @@ func TestFileSize(t *testing.T) {
 	require.Equal(t, len("some-content"), res.Directory.WithNewFile.File.Size)
 }
 
+func TestFileDigest(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	file := c.Directory().WithNewFile("some-file", "some-content").File("some-file")
+
+	dgst, err := file.Digest(ctx, dagger.FileDigestOpts{ExcludeMetadata: true})
+	require.NoError(t, err)
+	require.Equal(t, digest.FromString("some-content").String(), dgst)
+
+	out, err := c.Container().From(alpineImage).
+		WithMountedFile("/some-file", file).
+		WithExec([]string{"sha256sum", "/some-file"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimPrefix(dgst, "sha256:"), strings.Fields(out)[0])
+
+	withMeta, err := file.Digest(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, dgst, withMeta)
+}
+
+func TestFileExtract(t *testing.T) {
+	t.Parallel()
+	c, ctx := connect(t)
+
+	ctr := c.Container().From(alpineImage).
+		WithExec([]string{"apk", "add", "zip"}).
+		WithExec([]string{"sh", "-c", strings.Join([]string{
+			"mkdir -p /src/sub /src/node_modules",
+			"echo -n a > /src/a.txt",
+			"echo -n b > /src/sub/b.txt",
+			"echo -n c > /src/node_modules/c.txt",
+			"ln -s ../a.txt /src/sub/link",
+			"chmod 0700 /src/sub/b.txt",
+			"tar -C /src -czf /src.tar.gz .",
+			"cd /src && zip -qry /src.zip .",
+		}, " && ")})
+
+	for _, name := range []string{"src.tar.gz", "src.zip"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			dir := ctr.File("/" + name).Extract()
+
+			contents, err := dir.File("sub/b.txt").Contents(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "b", contents)
+
+			perms, err := dir.Stat("sub/b.txt").Permissions(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 0o700, perms)
+
+			target, err := dir.Stat("sub/link").LinkTarget(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "../a.txt", target)
+
+			entries, err := ctr.File("/" + name).Extract(dagger.FileExtractOpts{
+				Exclude: []string{"node_modules"},
+			}).Entries(ctx)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"a.txt", "sub"}, entries)
+		})
+	}
+
+	t.Run("explicit format", func(t *testing.T) {
+		_, err := ctr.File("/src.tar.gz").Extract(dagger.FileExtractOpts{
+			Format: dagger.Zip,
+		}).Sync(ctx)
+		require.Error(t, err)
+	})
+}
+
 func TestFileName(t *testing.T) {
 	t.Parallel()
 
@@ -234,3 +234,211 @@ func TestGitServiceStableDigest(t *testing.T) {
 	c2, ctx2 := connect(t)
 	require.Equal(t, hostname(ctx1, c1), hostname(ctx2, c2))
 }
+
+func TestGitRefs(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	repo := c.Git("https://github.com/dagger/dagger")
+
+	branches, err := repo.Branches(ctx)
+	require.NoError(t, err)
+	require.Contains(t, branches, "main")
+
+	tags, err := repo.Tags(ctx, dagger.GitRepositoryTagsOpts{Pattern: "v0.9.*"})
+	require.NoError(t, err)
+	require.Contains(t, tags, "v0.9.0")
+	for _, tag := range tags {
+		require.True(t, strings.HasPrefix(tag, "v0.9."), tag)
+		require.False(t, strings.HasSuffix(tag, "^{}"), tag)
+	}
+
+	_, err = repo.Tags(ctx, dagger.GitRepositoryTagsOpts{Pattern: "["})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid pattern")
+}
+
+func TestGitTreeSparse(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	ref := c.Git("https://github.com/dagger/dagger").Commit("c80ac2c13df7d573a069938e01ca13f7a81f0345")
+
+	ents, err := ref.Tree(dagger.GitRefTreeOpts{
+		SparsePaths: []string{"README.md", "docs"},
+	}).Entries(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"README.md", "docs"}, ents)
+
+	ents, err = c.Git("https://github.com/dagger/dagger", dagger.GitOpts{KeepGitDir: true}).
+		Commit("c80ac2c13df7d573a069938e01ca13f7a81f0345").
+		Tree(dagger.GitRefTreeOpts{SparsePaths: []string{"README.md"}}).
+		Entries(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{".git", "README.md"}, ents)
+}
+
+func TestGitTreeDepth(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	tree := c.Git("https://github.com/dagger/dagger", dagger.GitOpts{KeepGitDir: true}).
+		Commit("c80ac2c13df7d573a069938e01ca13f7a81f0345").
+		Tree(dagger.GitRefTreeOpts{Depth: 3})
+
+	out, err := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git"}).
+		WithMountedDirectory("/repo", tree).
+		WithWorkdir("/repo").
+		WithExec([]string{"git", "rev-list", "--count", "HEAD"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "3\n", out)
+
+	_, err = c.Git("https://github.com/dagger/dagger").
+		Branch("main").
+		Tree(dagger.GitRefTreeOpts{Depth: -1}).
+		Sync(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "depth must be at least 1")
+}
+
+func TestGitTreeLFS(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	const content = "the real content, stored in LFS\n"
+	svc, url := gitLFSService(ctx, t, c, content)
+	repo := c.Git(url, dagger.GitOpts{ExperimentalServiceHost: svc}).Branch("main")
+
+	t.Run("pointer files are replaced with their contents", func(t *testing.T) {
+		contents, err := repo.Tree(dagger.GitRefTreeOpts{LFS: true}).
+			File("data.bin").
+			Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, content, contents)
+	})
+
+	t.Run("pointer files are kept without lfs", func(t *testing.T) {
+		contents, err := repo.Tree().
+			File("data.bin").
+			Contents(ctx)
+		require.NoError(t, err)
+		require.Contains(t, contents, "version https://git-lfs.github.com/spec/v1")
+	})
+
+	t.Run("repos without LFS objects are checked out as usual", func(t *testing.T) {
+		contents, err := repo.Tree(dagger.GitRefTreeOpts{LFS: true}).
+			File("README.md").
+			Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "not in LFS\n", contents)
+	})
+}
+
+// gitLFSService serves a repo over HTTP whose data.bin file is tracked by Git
+// LFS with the given content, along with a minimal LFS server for it.
+func gitLFSService(ctx context.Context, t testing.TB, c *dagger.Client, content string) (*dagger.Service, string) {
+	t.Helper()
+
+	const port = 8080
+	srv := c.Container().
+		From(golangImage).
+		WithExec([]string{"apk", "add", "git", "git-lfs"}).
+		WithNewFile("/root/src/data.bin", dagger.ContainerWithNewFileOpts{
+			Contents: content,
+		}).
+		WithNewFile("/root/src/README.md", dagger.ContainerWithNewFileOpts{
+			Contents: "not in LFS\n",
+		}).
+		WithNewFile("/root/start.sh", dagger.ContainerWithNewFileOpts{
+			Contents: `#!/bin/sh
+
+set -e -u -x
+
+git config --global user.email "root@localhost"
+git config --global user.name "Test User"
+git config --global init.defaultBranch main
+
+cd /root/src
+	git init
+	git lfs install --local
+	git lfs track "*.bin"
+	git add .
+	git commit -m "init"
+cd ..
+
+git clone --bare /root/src /srv/repo.git
+
+exec go run /root/server.go
+`,
+		}).
+		WithNewFile("/root/server.go", dagger.ContainerWithNewFileOpts{
+			Contents: `package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cgi"
+	"path/filepath"
+)
+
+// lfsObjects is where git-lfs stored the objects of the source repo.
+const lfsObjects = "/root/src/.git/lfs/objects"
+
+type object struct {
+	OID     string         ` + "`json:\"oid\"`" + `
+	Size    int64          ` + "`json:\"size\"`" + `
+	Actions map[string]any ` + "`json:\"actions,omitempty\"`" + `
+}
+
+func main() {
+	http.HandleFunc("/repo.git/info/lfs/objects/batch", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Objects []object ` + "`json:\"objects\"`" + `
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for i, obj := range req.Objects {
+			req.Objects[i].Actions = map[string]any{
+				"download": map[string]any{"href": "http://" + r.Host + "/lfs/" + obj.OID},
+			}
+		}
+		w.Header().Set("Content-Type", "application/vnd.git-lfs+json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"transfer": "basic",
+			"objects":  req.Objects,
+		})
+	})
+	http.HandleFunc("/lfs/", func(w http.ResponseWriter, r *http.Request) {
+		oid := filepath.Base(r.URL.Path)
+		if len(oid) < 4 {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(lfsObjects, oid[0:2], oid[2:4], oid))
+	})
+	http.Handle("/", &cgi.Handler{
+		Path: "/usr/libexec/git-core/git-http-backend",
+		Env:  []string{"GIT_PROJECT_ROOT=/srv", "GIT_HTTP_EXPORT_ALL=1"},
+	})
+	http.ListenAndServe(":8080", nil)
+}
+`,
+		}).
+		WithExposedPort(port).
+		WithExec([]string{"sh", "/root/start.sh"}).
+		AsService()
+
+	host, err := srv.Hostname(ctx)
+	require.NoError(t, err)
+
+	return srv, fmt.Sprintf("http://%s:%d/repo.git", host, port)
+}
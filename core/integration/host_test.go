@@ -267,6 +267,75 @@ func TestHostDirectoryExcludeInclude(t *testing.T) {
 	})
 }
 
+func TestHostDirectoryIgnoreFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# build outputs\nnode_modules/\n*.log\n!keep.log\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".daggerignore"), []byte("/secret.txt\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("1"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("2"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("3"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.log"), []byte("4"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "dep", "index.js"), []byte("5"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "secret.txt"), []byte("6"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "trace.log"), []byte("7"), 0600))
+
+	c, ctx := connect(t)
+
+	t.Run("default", func(t *testing.T) {
+		entries, err := c.Host().Directory(dir).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".daggerignore", ".gitignore", "debug.log", "keep.log", "main.go", "node_modules", "secret.txt", "sub"}, entries)
+	})
+
+	t.Run("daggerignore", func(t *testing.T) {
+		entries, err := c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Daggerignore: true,
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".daggerignore", ".gitignore", "debug.log", "keep.log", "main.go", "node_modules", "sub"}, entries)
+
+		entries, err = c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Daggerignore: true,
+		}).Directory("sub").Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"secret.txt", "trace.log"}, entries)
+	})
+
+	t.Run("gitignore", func(t *testing.T) {
+		entries, err := c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Gitignore: true,
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".daggerignore", ".gitignore", "keep.log", "main.go", "secret.txt", "sub"}, entries)
+
+		entries, err = c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Gitignore: true,
+		}).Directory("sub").Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"secret.txt"}, entries)
+	})
+
+	t.Run("explicit exclude wins", func(t *testing.T) {
+		entries, err := c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Daggerignore: true,
+			Gitignore:    true,
+			Exclude:      []string{"keep.log"},
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".daggerignore", ".gitignore", "main.go", "sub"}, entries)
+	})
+
+	t.Run("no ignore files", func(t *testing.T) {
+		entries, err := c.Host().Directory(filepath.Join(dir, "sub")).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"secret.txt", "trace.log"}, entries)
+	})
+}
+
 func TestHostFile(t *testing.T) {
 	t.Parallel()
 
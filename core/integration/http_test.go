@@ -6,6 +6,7 @@ import (
 
 	"dagger.io/dagger"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,3 +63,105 @@ func TestHTTPServiceStableDigest(t *testing.T) {
 	c2, ctx2 := connect(t)
 	require.Equal(t, hostname(ctx1, c1), hostname(ctx2, c2))
 }
+
+func TestHTTPChecksum(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	content := identity.NewID()
+	svc, url := httpService(ctx, t, c, content)
+
+	t.Run("matching checksum", func(t *testing.T) {
+		file := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                digest.FromString(content).String(),
+			Name:                    "index.html",
+			Permissions:             0o644,
+		})
+
+		contents, err := file.Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, content, contents)
+
+		name, err := file.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "index.html", name)
+
+		out, err := c.Container().
+			From(alpineImage).
+			WithMountedFile("/mnt/index.html", file).
+			WithExec([]string{"stat", "-c", "%a", "/mnt/index.html"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "644\n", out)
+	})
+
+	t.Run("mismatched checksum", func(t *testing.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                digest.FromString("nope").String(),
+		}).Contents(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch")
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                "nope",
+		}).Contents(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid checksum")
+	})
+}
+
+func TestHTTPHeaders(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := connect(t)
+
+	// echoes the request headers we care about back as the response body
+	srv := c.Container().
+		From("python").
+		WithNewFile("/srv/echo.py", dagger.ContainerWithNewFileOpts{
+			Contents: `from http.server import BaseHTTPRequestHandler, HTTPServer
+
+class Handler(BaseHTTPRequestHandler):
+    def do_GET(self):
+        if self.headers.get("Authorization") != "Bearer hunter2":
+            self.send_response(401)
+            self.end_headers()
+            return
+        body = self.headers.get("X-Test", "").encode()
+        self.send_response(200)
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+HTTPServer(("", 8000), Handler).serve_forever()
+`,
+		}).
+		WithExposedPort(8000).
+		WithExec([]string{"python", "/srv/echo.py"}).
+		AsService()
+
+	url, err := srv.Endpoint(ctx, dagger.ServiceEndpointOpts{
+		Scheme: "http",
+	})
+	require.NoError(t, err)
+
+	contents, err := c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: srv,
+		Headers:                 []dagger.HTTPHeader{{Name: "X-Test", Value: "hello"}},
+		AuthHeader:              c.SetSecret("http-auth", "Bearer hunter2"),
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello", contents)
+
+	_, err = c.HTTP(url+"/?unauthenticated", dagger.HTTPOpts{
+		ExperimentalServiceHost: srv,
+	}).Contents(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid response status 401")
+}
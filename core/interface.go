@@ -190,10 +190,15 @@ func (iface *InterfaceType) Install(ctx context.Context, dag *dagql.Server) erro
 				}
 			}
 
+			argType, err := iface.mod.resolveEnums(ctx, argMetadata.TypeDef)
+			if err != nil {
+				return fmt.Errorf("failed to resolve type of arg %q: %w", argMetadata.Name, err)
+			}
+
 			inputSpec := dagql.InputSpec{
 				Name:        gqlArgName(argMetadata.Name),
 				Description: formatGqlDescription(argMetadata.Description),
-				Type:        argMetadata.TypeDef.ToInput(),
+				Type:        argType.ToInput(),
 			}
 			fieldDef.Args = append(fieldDef.Args, inputSpec)
 		}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/dagger/dagger/dagql"
+)
+
+// maxLogHistory is how much of a service's logs is kept for followers that
+// start late.
+const maxLogHistory = 1024 * 1024
+
+// LogBroadcaster is a writer whose output can be followed by any number of
+// readers, each starting from the retained history.
+type LogBroadcaster struct {
+	mu sync.Mutex
+
+	// buf holds the most recent output, starting at offset.
+	buf    []byte
+	offset int
+
+	// changed is closed and replaced whenever output is written or the
+	// broadcaster is closed.
+	changed chan struct{}
+	closed  bool
+}
+
+func NewLogBroadcaster() *LogBroadcaster {
+	return &LogBroadcaster{
+		changed: make(chan struct{}),
+	}
+}
+
+func (b *LogBroadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if trim := len(b.buf) - maxLogHistory; trim > 0 {
+		b.buf = append([]byte(nil), b.buf[trim:]...)
+		b.offset += trim
+	}
+	b.notify()
+	return len(p), nil
+}
+
+// Close ends the output, letting followers finish once they've caught up.
+func (b *LogBroadcaster) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		b.notify()
+	}
+	return nil
+}
+
+func (b *LogBroadcaster) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Follow returns a stream of the output, starting from the oldest output
+// still retained, which ends once the broadcaster is closed.
+func (b *LogBroadcaster) Follow() dagql.Stream[dagql.String] {
+	var pos int
+	return dagql.NewStream(func(ctx context.Context) (dagql.String, error) {
+		for {
+			b.mu.Lock()
+			if pos < b.offset {
+				// fell behind; skip what's been dropped
+				pos = b.offset
+			}
+			if pos < b.offset+len(b.buf) {
+				chunk := string(b.buf[pos-b.offset:])
+				pos = b.offset + len(b.buf)
+				b.mu.Unlock()
+				return dagql.NewString(chunk), nil
+			}
+			if b.closed {
+				b.mu.Unlock()
+				return "", io.EOF
+			}
+			changed := b.changed
+			b.mu.Unlock()
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogBroadcaster(t *testing.T) {
+	ctx := context.Background()
+	logs := NewLogBroadcaster()
+
+	_, err := logs.Write([]byte("hello, "))
+	require.NoError(t, err)
+
+	early := logs.Follow()
+	chunk, err := early.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello, ", chunk.String())
+
+	_, err = logs.Write([]byte("world!"))
+	require.NoError(t, err)
+	chunk, err = early.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "world!", chunk.String())
+
+	// late followers start with the history
+	late := logs.Follow()
+	chunk, err = late.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello, world!", chunk.String())
+
+	require.NoError(t, logs.Close())
+	_, err = early.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+	_, err = late.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestLogBroadcasterHistory(t *testing.T) {
+	ctx := context.Background()
+	logs := NewLogBroadcaster()
+
+	_, err := logs.Write([]byte(strings.Repeat("a", maxLogHistory)))
+	require.NoError(t, err)
+	_, err = logs.Write([]byte("b"))
+	require.NoError(t, err)
+	require.NoError(t, logs.Close())
+
+	chunk, err := logs.Follow().Next(ctx)
+	require.NoError(t, err)
+	require.Len(t, chunk.String(), maxLogHistory)
+	require.True(t, strings.HasSuffix(chunk.String(), "ab"))
+}
+
+func TestLogBroadcasterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewLogBroadcaster().Follow().Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
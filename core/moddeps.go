@@ -115,8 +115,10 @@ func (d *ModDeps) lazilyLoadSchema(ctx context.Context) (loadedSchema *dagql.Ser
 
 	dag.Around(tracing.AroundFunc)
 
-	// share the same cache session-wide
+	// share the same cache, limits and IDs session-wide
 	dag.Cache = d.root.Cache
+	dag.Limits = d.root.Limits
+	dag.IDs = d.root.IDs
 
 	dagintro.Install[*Query](dag)
 
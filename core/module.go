@@ -55,6 +55,12 @@ type Module struct {
 	// The module's interfaces
 	InterfaceDefs []*TypeDef `field:"true" name:"interfaces" doc:"Interfaces served by this module."`
 
+	// The module's enumerations
+	EnumDefs []*TypeDef `field:"true" name:"enums" doc:"Enumerations served by this module."`
+
+	// The module's scalars
+	ScalarDefs []*TypeDef `field:"true" name:"scalars" doc:"Scalars served by this module."`
+
 	// Runtime is the container that runs the module's entrypoint. It is
 	// unavailable if the module doesn't compile.
 	Runtime *Container
@@ -124,6 +130,29 @@ func (mod *Module) Install(ctx context.Context, dag *dagql.Server) error {
 	start := time.Now()
 	defer func() { slog.Debug("done installing module", "name", mod.Name(), "took", time.Since(start)) }()
 
+	// scalars and enums are installed first since objects and interfaces may refer to them
+	for _, def := range mod.ScalarDefs {
+		scalarDef := def.AsScalar.Value
+
+		slog.Debug("installing scalar", "name", mod.Name(), "scalar", scalarDef.Name)
+
+		scalar := &ModuleScalar{
+			TypeDef: scalarDef,
+		}
+		scalar.Install(dag)
+	}
+
+	for _, def := range mod.EnumDefs {
+		enumDef := def.AsEnum.Value
+
+		slog.Debug("installing enum", "name", mod.Name(), "enum", enumDef.Name)
+
+		enum := &ModuleEnum{
+			TypeDef: enumDef,
+		}
+		enum.Install(dag)
+	}
+
 	for _, def := range mod.ObjectDefs {
 		objDef := def.AsObject.Value
 
@@ -172,7 +201,7 @@ func (mod *Module) Install(ctx context.Context, dag *dagql.Server) error {
 }
 
 func (mod *Module) TypeDefs(ctx context.Context) ([]*TypeDef, error) {
-	typeDefs := make([]*TypeDef, 0, len(mod.ObjectDefs)+len(mod.InterfaceDefs))
+	typeDefs := make([]*TypeDef, 0, len(mod.ObjectDefs)+len(mod.InterfaceDefs)+len(mod.EnumDefs)+len(mod.ScalarDefs))
 	for _, def := range mod.ObjectDefs {
 		typeDef := def.Clone()
 		if typeDef.AsObject.Valid {
@@ -187,6 +216,20 @@ func (mod *Module) TypeDefs(ctx context.Context) ([]*TypeDef, error) {
 		}
 		typeDefs = append(typeDefs, typeDef)
 	}
+	for _, def := range mod.EnumDefs {
+		typeDef := def.Clone()
+		if typeDef.AsEnum.Valid {
+			typeDef.AsEnum.Value.SourceModuleName = mod.Name()
+		}
+		typeDefs = append(typeDefs, typeDef)
+	}
+	for _, def := range mod.ScalarDefs {
+		typeDef := def.Clone()
+		if typeDef.AsScalar.Valid {
+			typeDef.AsScalar.Value.SourceModuleName = mod.Name()
+		}
+		typeDefs = append(typeDefs, typeDef)
+	}
 	return typeDefs, nil
 }
 
@@ -197,7 +240,7 @@ func (mod *Module) DependencySchemaIntrospectionJSON(ctx context.Context) (strin
 func (mod *Module) ModTypeFor(ctx context.Context, typeDef *TypeDef, checkDirectDeps bool) (ModType, bool, error) {
 	var modType ModType
 	switch typeDef.Kind {
-	case TypeDefKindString, TypeDefKindInteger, TypeDefKindBoolean, TypeDefKindVoid:
+	case TypeDefKindString, TypeDefKindInteger, TypeDefKindBoolean, TypeDefKindFloat, TypeDefKindVoid:
 		modType = &PrimitiveType{typeDef}
 
 	case TypeDefKindList:
@@ -271,6 +314,64 @@ func (mod *Module) ModTypeFor(ctx context.Context, typeDef *TypeDef, checkDirect
 			return nil, false, nil
 		}
 
+	case TypeDefKindEnum:
+		if checkDirectDeps {
+			// check to see if this is from a *direct* dependency
+			depType, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get enum type from dependency: %w", err)
+			}
+			if ok {
+				return depType, true, nil
+			}
+		}
+
+		var found bool
+		// otherwise it must be from this module
+		for _, enum := range mod.EnumDefs {
+			if enum.AsEnum.Value.Name == typeDef.AsEnum.Value.Name {
+				modType = &ModuleEnumType{
+					typeDef: enum.AsEnum.Value,
+					mod:     mod,
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			slog.Debug("module did not find enum", "mod", mod.Name(), "enum", typeDef.AsEnum.Value.Name)
+			return nil, false, nil
+		}
+
+	case TypeDefKindScalar:
+		if checkDirectDeps {
+			// check to see if this is from a *direct* dependency
+			depType, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get scalar type from dependency: %w", err)
+			}
+			if ok {
+				return depType, true, nil
+			}
+		}
+
+		var found bool
+		// otherwise it must be from this module
+		for _, scalar := range mod.ScalarDefs {
+			if scalar.AsScalar.Value.Name == typeDef.AsScalar.Value.Name {
+				modType = &ModuleScalarType{
+					typeDef: scalar.AsScalar.Value,
+					mod:     mod,
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			slog.Debug("module did not find scalar", "mod", mod.Name(), "scalar", typeDef.AsScalar.Value.Name)
+			return nil, false, nil
+		}
+
 	default:
 		return nil, false, fmt.Errorf("unexpected type def kind %s", typeDef.Kind)
 	}
@@ -294,6 +395,8 @@ func (mod *Module) validateTypeDef(ctx context.Context, typeDef *TypeDef) error
 		return mod.validateObjectTypeDef(ctx, typeDef)
 	case TypeDefKindInterface:
 		return mod.validateInterfaceTypeDef(ctx, typeDef)
+	case TypeDefKindEnum:
+		return mod.validateEnumTypeDef(ctx, typeDef)
 	}
 	return nil
 }
@@ -422,6 +525,33 @@ func (mod *Module) validateInterfaceTypeDef(ctx context.Context, typeDef *TypeDe
 	return nil
 }
 
+func (mod *Module) validateEnumTypeDef(ctx context.Context, typeDef *TypeDef) error {
+	enum := typeDef.AsEnum.Value
+
+	// check whether this is a pre-existing enum from core or another module
+	modType, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+	if err != nil {
+		return fmt.Errorf("failed to get mod type for type def: %w", err)
+	}
+	if ok {
+		if sourceMod := modType.SourceMod(); sourceMod != nil && sourceMod != mod {
+			// already validated, skip
+			return nil
+		}
+	}
+	seen := make(map[string]struct{}, len(enum.Values))
+	for _, val := range enum.Values {
+		if err := validateEnumValueName(val.Name); err != nil {
+			return fmt.Errorf("enum %q: %w", enum.OriginalName, err)
+		}
+		if _, dup := seen[val.Name]; dup {
+			return fmt.Errorf("enum %q has duplicate value %q", enum.OriginalName, val.Name)
+		}
+		seen[val.Name] = struct{}{}
+	}
+	return nil
+}
+
 // prefix the given typedef (and any recursively referenced typedefs) with this module's name for any objects
 func (mod *Module) namespaceTypeDef(ctx context.Context, typeDef *TypeDef) error {
 	switch typeDef.Kind {
@@ -481,6 +611,28 @@ func (mod *Module) namespaceTypeDef(ctx context.Context, typeDef *TypeDef) error
 				}
 			}
 		}
+	case TypeDefKindEnum:
+		enum := typeDef.AsEnum.Value
+
+		// only namespace enums defined in this module
+		_, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+		if err != nil {
+			return fmt.Errorf("failed to get mod type for type def: %w", err)
+		}
+		if !ok {
+			enum.Name = namespaceObject(enum.Name, mod.Name())
+		}
+	case TypeDefKindScalar:
+		scalar := typeDef.AsScalar.Value
+
+		// only namespace scalars defined in this module
+		_, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+		if err != nil {
+			return fmt.Errorf("failed to get mod type for type def: %w", err)
+		}
+		if !ok {
+			scalar.Name = namespaceObject(scalar.Name, mod.Name())
+		}
 	}
 	return nil
 }
@@ -571,6 +723,14 @@ func (mod Module) Clone() *Module {
 	for i, def := range mod.InterfaceDefs {
 		cp.InterfaceDefs[i] = def.Clone()
 	}
+	cp.EnumDefs = make([]*TypeDef, len(mod.EnumDefs))
+	for i, def := range mod.EnumDefs {
+		cp.EnumDefs[i] = def.Clone()
+	}
+	cp.ScalarDefs = make([]*TypeDef, len(mod.ScalarDefs))
+	for i, def := range mod.ScalarDefs {
+		cp.ScalarDefs[i] = def.Clone()
+	}
 	return &cp
 }
 
@@ -612,6 +772,76 @@ func (mod *Module) WithInterface(ctx context.Context, def *TypeDef) (*Module, er
 	return mod, nil
 }
 
+// resolveEnums returns the type def with any references to enums replaced by
+// their full definitions, which references don't carry, so that the values
+// passed for them can be validated.
+func (mod *Module) resolveEnums(ctx context.Context, typeDef *TypeDef) (*TypeDef, error) {
+	switch typeDef.Kind {
+	case TypeDefKindList:
+		elem := typeDef.AsList.Value.ElementTypeDef
+		resolvedElem, err := mod.resolveEnums(ctx, elem)
+		if err != nil {
+			return nil, err
+		}
+		if resolvedElem == elem {
+			return typeDef, nil
+		}
+		resolved := typeDef.Clone()
+		resolved.AsList.Value.ElementTypeDef = resolvedElem
+		return resolved, nil
+	case TypeDefKindEnum:
+		if len(typeDef.AsEnum.Value.Values) > 0 {
+			return typeDef, nil
+		}
+		modType, ok, err := mod.ModTypeFor(ctx, typeDef, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mod type for enum %q: %w", typeDef.AsEnum.Value.Name, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("enum %q is not defined", typeDef.AsEnum.Value.Name)
+		}
+		resolved := typeDef.Clone()
+		resolved.AsEnum = modType.TypeDef().AsEnum
+		return resolved, nil
+	}
+	return typeDef, nil
+}
+
+func (mod *Module) WithEnum(ctx context.Context, def *TypeDef) (*Module, error) {
+	mod = mod.Clone()
+	if !def.AsEnum.Valid {
+		return nil, fmt.Errorf("expected enum type def, got %s: %+v", def.Kind, def)
+	}
+	if len(def.AsEnum.Value.Values) == 0 {
+		return nil, fmt.Errorf("enum %q must have at least one value", def.AsEnum.Value.OriginalName)
+	}
+	if err := mod.validateTypeDef(ctx, def); err != nil {
+		return nil, fmt.Errorf("failed to validate type def: %w", err)
+	}
+	def = def.Clone()
+	if err := mod.namespaceTypeDef(ctx, def); err != nil {
+		return nil, fmt.Errorf("failed to namespace type def: %w", err)
+	}
+	mod.EnumDefs = append(mod.EnumDefs, def)
+	return mod, nil
+}
+
+func (mod *Module) WithScalar(ctx context.Context, def *TypeDef) (*Module, error) {
+	mod = mod.Clone()
+	if !def.AsScalar.Valid {
+		return nil, fmt.Errorf("expected scalar type def, got %s: %+v", def.Kind, def)
+	}
+	if err := mod.validateTypeDef(ctx, def); err != nil {
+		return nil, fmt.Errorf("failed to validate type def: %w", err)
+	}
+	def = def.Clone()
+	if err := mod.namespaceTypeDef(ctx, def); err != nil {
+		return nil, fmt.Errorf("failed to namespace type def: %w", err)
+	}
+	mod.ScalarDefs = append(mod.ScalarDefs, def)
+	return mod, nil
+}
+
 // Load the module config as parsed from the given File
 func LoadModuleConfigFromFile(
 	ctx context.Context,
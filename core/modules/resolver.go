@@ -169,8 +169,9 @@ func (ref *Ref) source(ctx context.Context, c *dagger.Client) (*dagger.Directory
 
 		return c.Host().
 				Directory(modRootDir, dagger.HostDirectoryOpts{
-					Include: cfg.Include,
-					Exclude: cfg.Exclude,
+					Include:      cfg.Include,
+					Exclude:      cfg.Exclude,
+					Daggerignore: true,
 				}),
 			subdirRelPath, nil
 
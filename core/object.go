@@ -235,7 +235,7 @@ func (obj *ModuleObject) installConstructor(ctx context.Context, dag *dagql.Serv
 		return fmt.Errorf("failed to create function: %w", err)
 	}
 
-	spec, err := fn.metadata.FieldSpec()
+	spec, err := fn.metadata.FieldSpec(ctx, mod)
 	if err != nil {
 		return fmt.Errorf("failed to get field spec: %w", err)
 	}
@@ -324,7 +324,7 @@ func objFun(ctx context.Context, mod *Module, objDef *ObjectTypeDef, fun *Functi
 	if err != nil {
 		return f, fmt.Errorf("failed to create function %q: %w", fun.Name, err)
 	}
-	spec, err := fun.FieldSpec()
+	spec, err := fun.FieldSpec(ctx, mod)
 	if err != nil {
 		return f, fmt.Errorf("failed to get field spec: %w", err)
 	}
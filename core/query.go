@@ -46,6 +46,13 @@ type Query struct {
 	// The DagQL query cache.
 	Cache dagql.Cache
 
+	// The limits of queries made in this session.
+	Limits dagql.Limits
+
+	// The IDs returned in this session, which clients may refer to by
+	// reference.
+	IDs *idproto.Store
+
 	// The metadata of client calls.
 	// For the special case of the main client caller, the key is just empty string.
 	// This is never explicitly deleted from; instead it will just be garbage collected
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ModuleScalarType is the ModType for a scalar defined by a user module.
+type ModuleScalarType struct {
+	typeDef *ScalarTypeDef
+	mod     *Module
+}
+
+var _ ModType = (*ModuleScalarType)(nil)
+
+func (t *ModuleScalarType) SourceMod() Mod {
+	return t.mod
+}
+
+func (t *ModuleScalarType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return (&ModuleScalar{TypeDef: t.typeDef}).DecodeInput(value)
+}
+
+func (t *ModuleScalarType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	scalar, ok := value.(*ModuleScalar)
+	if !ok {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput cannot handle %T", t, value)
+	}
+	return scalar.Value, nil
+}
+
+func (t *ModuleScalarType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind:     TypeDefKindScalar,
+		AsScalar: dagql.NonNull(t.typeDef),
+	}
+}
+
+// ModuleScalar is a value of a scalar defined by a user module. The zero value
+// (with only TypeDef set) acts as the scalar's type in the schema.
+//
+// Scalars are opaque to the engine: their values are passed between the
+// caller and the SDK as-is.
+type ModuleScalar struct {
+	TypeDef *ScalarTypeDef
+	Value   any
+}
+
+func (s *ModuleScalar) Install(dag *dagql.Server) {
+	dag.InstallScalar(s)
+}
+
+var _ dagql.ScalarType = (*ModuleScalar)(nil)
+
+func (s *ModuleScalar) TypeName() string {
+	return s.TypeDef.Name
+}
+
+func (s *ModuleScalar) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: s.TypeName(),
+		NonNull:   true,
+	}
+}
+
+var _ dagql.Definitive = (*ModuleScalar)(nil)
+
+func (s *ModuleScalar) TypeDefinition() *ast.Definition {
+	return &ast.Definition{
+		Kind:        ast.Scalar,
+		Name:        s.TypeName(),
+		Description: formatGqlDescription(s.TypeDef.Description),
+	}
+}
+
+var _ dagql.InputDecoder = (*ModuleScalar)(nil)
+
+func (s *ModuleScalar) DecodeInput(val any) (dagql.Input, error) {
+	switch x := val.(type) {
+	case *ModuleScalar:
+		return &ModuleScalar{TypeDef: s.TypeDef, Value: x.Value}, nil
+	case string, bool, int, int32, int64, float32, float64, json.Number, []any, map[string]any:
+		return &ModuleScalar{TypeDef: s.TypeDef, Value: x}, nil
+	default:
+		return nil, fmt.Errorf("cannot create scalar %q from %T", s.TypeDef.Name, x)
+	}
+}
+
+var _ dagql.Input = (*ModuleScalar)(nil)
+
+func (s *ModuleScalar) Decoder() dagql.InputDecoder {
+	return &ModuleScalar{TypeDef: s.TypeDef}
+}
+
+func (s *ModuleScalar) ToLiteral() *idproto.Literal {
+	return scalarLiteral(s.Value)
+}
+
+func (s *ModuleScalar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value)
+}
+
+// scalarLiteral converts an arbitrary scalar value into an ID literal.
+func scalarLiteral(val any) *idproto.Literal {
+	switch x := val.(type) {
+	case nil:
+		return &idproto.Literal{Value: &idproto.Literal_Null{Null: true}}
+	case string:
+		return &idproto.Literal{Value: &idproto.Literal_String_{String_: x}}
+	case bool:
+		return &idproto.Literal{Value: &idproto.Literal_Bool{Bool: x}}
+	case int:
+		return &idproto.Literal{Value: &idproto.Literal_Int{Int: int64(x)}}
+	case int32:
+		return &idproto.Literal{Value: &idproto.Literal_Int{Int: int64(x)}}
+	case int64:
+		return &idproto.Literal{Value: &idproto.Literal_Int{Int: x}}
+	case float32:
+		return &idproto.Literal{Value: &idproto.Literal_Float{Float: float64(x)}}
+	case float64:
+		return &idproto.Literal{Value: &idproto.Literal_Float{Float: x}}
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return &idproto.Literal{Value: &idproto.Literal_Int{Int: i}}
+		}
+		if f, err := x.Float64(); err == nil {
+			return &idproto.Literal{Value: &idproto.Literal_Float{Float: f}}
+		}
+		return &idproto.Literal{Value: &idproto.Literal_String_{String_: x.String()}}
+	case []any:
+		list := &idproto.List{}
+		for _, v := range x {
+			list.Values = append(list.Values, scalarLiteral(v))
+		}
+		return &idproto.Literal{Value: &idproto.Literal_List{List: list}}
+	case map[string]any:
+		// sort keys so that the literal (and thus the ID digest) is stable
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		obj := &idproto.Object{}
+		for _, k := range keys {
+			obj.Values = append(obj.Values, &idproto.Argument{
+				Name:  k,
+				Value: scalarLiteral(x[k]),
+			})
+		}
+		return &idproto.Literal{Value: &idproto.Literal_Object{Object: obj}}
+	default:
+		// shouldn't be reachable given DecodeInput, but fall back to JSON
+		payload, _ := json.Marshal(x)
+		return &idproto.Literal{Value: &idproto.Literal_String_{String_: string(payload)}}
+	}
+}
@@ -2,6 +2,8 @@ package schema
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -21,10 +23,35 @@ func (s *cacheSchema) Install() {
 	dagql.Fields[*core.Query]{
 		dagql.Func("cacheVolume", s.cacheVolume).
 			Doc("Constructs a cache volume for a given cache key.").
-			ArgDoc("key", `A string identifier to target this cache volume (e.g., "modules-cache").`),
+			ArgDoc("key", `A string identifier to target this cache volume (e.g., "modules-cache").`).
+			ArgDoc("maxSize",
+				`Maximum size of the cache volume's contents, in bytes. If the contents
+				exceed it when an exec mounts the volume, they are wiped entirely
+				rather than trimmed.`,
+				`The size is only checked when the volume is mounted, so an exec may
+				grow the contents past it.`,
+				`Set to 0 for no limit.`).
+			ArgDoc("ttl",
+				`Number of seconds the cache volume's contents are kept once they
+				stop being used. Older contents are wiped entirely when an exec
+				next mounts the volume.`,
+				`Set to 0 for no limit.`),
+		dagql.Func("cacheVolumes", s.cacheVolumes).
+			Impure("Cache volumes are created and removed outside of the pipeline.").
+			Doc(`Lists the cache volumes with contents in the engine's local cache.`),
 	}.Install(s.srv)
 
-	dagql.Fields[*core.CacheVolume]{}.Install(s.srv)
+	dagql.Fields[*core.CacheVolume]{
+		dagql.Func("key", s.key).
+			Doc(`The key the cache volume was constructed with.`),
+		dagql.Func("size", s.size).
+			Impure("The contents of the cache volume change as it is used.").
+			Doc(`The size of the cache volume's contents in the engine's local cache, in bytes.`),
+		dagql.Func("prune", s.prune).
+			Impure("Clears the contents of the cache volume.").
+			Doc(`Clears the contents of the cache volume from the engine's local cache.`,
+				`Contents currently mounted by a running container are kept.`),
+	}.Install(s.srv)
 }
 
 func (s *cacheSchema) Dependencies() []SchemaResolvers {
@@ -32,7 +59,9 @@ func (s *cacheSchema) Dependencies() []SchemaResolvers {
 }
 
 type cacheArgs struct {
-	Key string
+	Key     string
+	MaxSize int `default:"0"`
+	TTL     int `name:"ttl" default:"0"`
 }
 
 func (s *cacheSchema) cacheVolume(ctx context.Context, parent *core.Query, args cacheArgs) (*core.CacheVolume, error) {
@@ -40,5 +69,60 @@ func (s *cacheSchema) cacheVolume(ctx context.Context, parent *core.Query, args
 	// here instead of a static value
 	//
 	// we have to inject something so we can tell it's a valid ID
-	return core.NewCache(args.Key), nil
+	cache := core.NewCache(args.Key)
+	cache.Query = parent
+	cache.MaxSize = int64(args.MaxSize)
+	cache.TTL = time.Duration(args.TTL) * time.Second
+	return cache, nil
+}
+
+func (s *cacheSchema) cacheVolumes(ctx context.Context, parent *core.Query, _ struct{}) ([]*core.CacheVolume, error) {
+	// record the keys of the volumes mounted so far, so that their contents
+	// can be found by key, even after a restart
+	var seen []string
+	core.SeenCacheKeys.Range(func(key, _ any) bool {
+		seen = append(seen, key.(string))
+		return true
+	})
+	for _, key := range seen {
+		cache := core.NewCache(key)
+		if err := parent.Buildkit.RecordCacheMount(ctx, cache.Sum(), key); err != nil {
+			return nil, err
+		}
+	}
+
+	mnts, err := parent.Buildkit.CacheMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caches := []*core.CacheVolume{}
+	for _, mnt := range mnts {
+		if mnt.Key == "" {
+			// not mounted by a cache volume, or never seen by this engine
+			continue
+		}
+		cache := core.NewCache(mnt.Key)
+		cache.Query = parent
+		caches = append(caches, cache)
+	}
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].Key() < caches[j].Key()
+	})
+	return caches, nil
+}
+
+func (s *cacheSchema) key(ctx context.Context, parent *core.CacheVolume, _ struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Key()), nil
+}
+
+func (s *cacheSchema) size(ctx context.Context, parent *core.CacheVolume, _ struct{}) (dagql.Int, error) {
+	mnt, err := parent.Usage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dagql.NewInt(mnt.Size), nil
+}
+
+func (s *cacheSchema) prune(ctx context.Context, parent *core.CacheVolume, _ struct{}) (dagql.Nullable[core.Void], error) {
+	return dagql.Null[core.Void](), parent.Prune(ctx)
 }
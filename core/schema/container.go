@@ -14,6 +14,7 @@ import (
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/moby/buildkit/frontend/dockerfile/shell"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -48,12 +49,19 @@ func (s *containerSchema) Install() {
 			ArgDoc("labels", "Labels to apply to the sub-pipeline."),
 
 		dagql.Func("from", s.from).
+			Complexity(costs(transferCost)).
+			Volatile(func(call *idproto.ID) bool {
+				// tags can move, digests can't
+				return !strings.Contains(callArg(call, "address").GetString_(), "@")
+			}).
 			Doc(`Initializes this container from a pulled base image.`).
 			ArgDoc("address",
 				`Image's address from its registry.`,
 				`Formatted as [host]/[user]/[repo]:[tag] (e.g., "docker.io/dagger/dagger:main").`),
 
 		dagql.Func("build", s.build).
+			Complexity(costs(buildCost)).
+			Volatile().
 			Doc(`Initializes this container from a Dockerfile build.`).
 			ArgDoc("context", "Directory context used by the Dockerfile.").
 			ArgDoc("dockerfile", "Path to the Dockerfile to use.").
@@ -267,6 +275,7 @@ func (s *containerSchema) Install() {
 				`If the group is omitted, it defaults to the same as the user.`),
 
 		dagql.Func("withExec", s.withExec).
+			Complexity(costs(execCost)).
 			Doc(`Retrieves this container after executing the specified command inside it.`).
 			ArgDoc("args",
 				`Command to run instead of the container's default command (e.g., ["run", "main.go"]).`,
@@ -292,7 +301,26 @@ func (s *containerSchema) Install() {
 				running a command with "sudo" or executing "docker run" with the
 				"--privileged" flag. Containerization does not provide any security
 				guarantees when using this option. It should only be used when
-				absolutely necessary and only with trusted commands.`),
+				absolutely necessary and only with trusted commands.`).
+			ArgDoc("timeout",
+				`Maximum time in seconds the command may run for before being killed.`,
+				`If 0, the command may run indefinitely.`).
+			ArgDoc("memoryLimit",
+				`Maximum amount of memory in bytes the container may use before the
+				command is killed.`,
+				`If 0, memory is not limited.`).
+			ArgDoc("cpuShares",
+				`Relative CPU weight of the container, compared to other containers
+				(e.g., 1024).`,
+				`If 0, the default weight is used.`).
+			ArgDoc("pidsLimit",
+				`Maximum number of processes the container may run.`,
+				`If 0, the number of processes is not limited.`).
+			ArgDoc("expect",
+				`Exit codes this command is allowed to exit with without error.`,
+				`With FAILURE or ANY, a failing command's filesystem and output stay
+				available to the rest of the pipeline, and its status can be read
+				with exitCode.`),
 
 		dagql.Func("stdout", s.stdout).
 			Doc(`The output stream of the last executed command.`,
@@ -302,7 +330,12 @@ func (s *containerSchema) Install() {
 			Doc(`The error stream of the last executed command.`,
 				`Will execute default command if none is set, or error if there's no default.`),
 
+		dagql.Func("exitCode", s.exitCode).
+			Doc(`The exit code of the last executed command.`,
+				`Will execute default command if none is set, or error if there's no default.`),
+
 		dagql.Func("publish", s.publish).
+			Complexity(costs(transferCost)).
 			Impure("Writes to the specified Docker registry.").
 			Doc(`Publishes this container as a new image to the specified address.`,
 				`Publish returns a fully qualified ref.`,
@@ -331,6 +364,7 @@ func (s *containerSchema) Install() {
 			Doc(`The platform this container executes and publishes as.`),
 
 		dagql.Func("export", s.export).
+			Complexity(costs(transferCost)).
 			Impure("Writes to the local host.").
 			Doc(`Writes the container as an OCI tarball to the destination file path on the host.`,
 				`Return true on success.`,
@@ -555,6 +589,14 @@ func (s *containerSchema) stderr(ctx context.Context, parent *core.Container, _
 	return parent.MetaFileContents(ctx, "stderr")
 }
 
+func (s *containerSchema) exitCode(ctx context.Context, parent *core.Container, _ struct{}) (dagql.Int, error) {
+	exitCode, err := parent.ExitCode(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dagql.NewInt(exitCode), nil
+}
+
 type containerGpuArgs struct {
 	core.ContainerGPUOpts
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 // CoreMod is a special implementation of Mod for our core API, which is not *technically* a true module yet
@@ -45,6 +47,7 @@ func (m *CoreMod) Install(ctx context.Context, dag *dagql.Server) error {
 		&platformSchema{dag},
 		&socketSchema{dag},
 		&moduleSchema{dag},
+		&subscriptionSchema{dag},
 	} {
 		schema.Install()
 	}
@@ -55,7 +58,7 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 	var modType core.ModType
 
 	switch typeDef.Kind {
-	case core.TypeDefKindString, core.TypeDefKindInteger, core.TypeDefKindBoolean, core.TypeDefKindVoid:
+	case core.TypeDefKindString, core.TypeDefKindInteger, core.TypeDefKindBoolean, core.TypeDefKindFloat, core.TypeDefKindVoid:
 		modType = &core.PrimitiveType{Def: typeDef}
 
 	case core.TypeDefKindList:
@@ -82,6 +85,32 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 		// core does not yet defined any interfaces
 		return nil, false, nil
 
+	case core.TypeDefKindEnum:
+		scalar, ok := m.dag.ScalarType(typeDef.AsEnum.Value.Name)
+		if !ok {
+			return nil, false, nil
+		}
+		// references to the enum don't carry its values, so take them from
+		// its definition in the schema
+		enumDef := typeDef.AsEnum.Value.Clone()
+		if def, ok := scalar.(dagql.Definitive); ok {
+			enumDef.Values = nil
+			for _, val := range def.TypeDefinition().EnumValues {
+				enumDef.Values = append(enumDef.Values, &core.EnumValueTypeDef{
+					Name:        val.Name,
+					Description: val.Description,
+				})
+			}
+		}
+		modType = &CoreModEnum{coreMod: m, typeDef: enumDef}
+
+	case core.TypeDefKindScalar:
+		_, ok := m.dag.ScalarType(typeDef.AsScalar.Value.Name)
+		if !ok {
+			return nil, false, nil
+		}
+		modType = &CoreModScalar{coreMod: m, typeDef: typeDef.AsScalar.Value}
+
 	default:
 		return nil, false, fmt.Errorf("unexpected type def kind %s", typeDef.Kind)
 	}
@@ -144,7 +173,11 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 					}
 
 					if introspectionArg.DefaultValue != nil {
-						fnArg.DefaultValue = core.JSON(*introspectionArg.DefaultValue)
+						defaultValue, err := gqlLiteralToJSON(*introspectionArg.DefaultValue)
+						if err != nil {
+							return nil, fmt.Errorf("failed to convert default value of arg %q: %w", introspectionArg.Name, err)
+						}
+						fnArg.DefaultValue = defaultValue
 					}
 
 					argType, ok, err := introspectionRefToTypeDef(introspectionArg.TypeRef, false, true)
@@ -162,7 +195,9 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 				typeDef.Functions = append(typeDef.Functions, fn)
 			}
 
-			if !isIdable {
+			// skip objects that can't be passed around, except for the root
+			// Query type which clients can call functions on directly
+			if !isIdable && introspectionType.Name != schema.QueryType.Name {
 				continue
 			}
 
@@ -197,6 +232,29 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 				AsInput: dagql.NonNull(typeDef),
 			})
 
+		case introspection.TypeKindEnum:
+			if strings.HasPrefix(introspectionType.Name, "__") {
+				// skip introspection enums like __TypeKind
+				continue
+			}
+
+			typeDef := &core.EnumTypeDef{
+				Name:        introspectionType.Name,
+				Description: introspectionType.Description,
+			}
+
+			for _, introspectionValue := range introspectionType.EnumValues {
+				typeDef.Values = append(typeDef.Values, &core.EnumValueTypeDef{
+					Name:        introspectionValue.Name,
+					Description: introspectionValue.Description,
+				})
+			}
+
+			typeDefs = append(typeDefs, &core.TypeDef{
+				Kind:   core.TypeDefKindEnum,
+				AsEnum: dagql.NonNull(typeDef),
+			})
+
 		default:
 			continue
 		}
@@ -262,6 +320,75 @@ func (obj *CoreModObject) TypeDef() *core.TypeDef {
 	}
 }
 
+// CoreModEnum represents enums from core (ImageLayerCompression, etc.)
+type CoreModEnum struct {
+	coreMod *CoreMod
+	typeDef *core.EnumTypeDef
+}
+
+var _ core.ModType = (*CoreModEnum)(nil)
+
+func (enum *CoreModEnum) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		return nil, nil
+	}
+	scalar, ok := enum.coreMod.dag.ScalarType(enum.typeDef.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown enum %q", enum.typeDef.Name)
+	}
+	return scalar.DecodeInput(value)
+}
+
+func (enum *CoreModEnum) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	// core enums are all string-based, so they're passed through as-is
+	return value, nil
+}
+
+func (enum *CoreModEnum) SourceMod() core.Mod {
+	return enum.coreMod
+}
+
+func (enum *CoreModEnum) TypeDef() *core.TypeDef {
+	return &core.TypeDef{
+		Kind:   core.TypeDefKindEnum,
+		AsEnum: dagql.NonNull(enum.typeDef),
+	}
+}
+
+// CoreModScalar represents scalars from core (Platform, JSON, etc.)
+type CoreModScalar struct {
+	coreMod *CoreMod
+	typeDef *core.ScalarTypeDef
+}
+
+var _ core.ModType = (*CoreModScalar)(nil)
+
+func (scalar *CoreModScalar) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		return nil, nil
+	}
+	s, ok := scalar.coreMod.dag.ScalarType(scalar.typeDef.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown scalar %q", scalar.typeDef.Name)
+	}
+	return s.DecodeInput(value)
+}
+
+func (scalar *CoreModScalar) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	return value, nil
+}
+
+func (scalar *CoreModScalar) SourceMod() core.Mod {
+	return scalar.coreMod
+}
+
+func (scalar *CoreModScalar) TypeDef() *core.TypeDef {
+	return &core.TypeDef{
+		Kind:     core.TypeDefKindScalar,
+		AsScalar: dagql.NonNull(scalar.typeDef),
+	}
+}
+
 func introspectionRefToTypeDef(introspectionType *introspection.TypeRef, nonNull, isInput bool) (*core.TypeDef, bool, error) {
 	switch introspectionType.Kind {
 	case introspection.TypeKindNonNull:
@@ -290,6 +417,8 @@ func introspectionRefToTypeDef(introspectionType *introspection.TypeRef, nonNull
 			typeDef.Kind = core.TypeDefKindInteger
 		case string(introspection.ScalarBoolean):
 			typeDef.Kind = core.TypeDefKindBoolean
+		case string(introspection.ScalarFloat):
+			typeDef.Kind = core.TypeDefKindFloat
 		default:
 			// default to saying it's a string for now
 			typeDef.Kind = core.TypeDefKindString
@@ -299,9 +428,11 @@ func introspectionRefToTypeDef(introspectionType *introspection.TypeRef, nonNull
 
 	case introspection.TypeKindEnum:
 		return &core.TypeDef{
-			// just call it a string for now
-			Kind:     core.TypeDefKindString,
+			Kind:     core.TypeDefKindEnum,
 			Optional: !nonNull,
+			AsEnum: dagql.NonNull(&core.EnumTypeDef{
+				Name: introspectionType.Name,
+			}),
 		}, true, nil
 
 	case introspection.TypeKindList:
@@ -342,3 +473,19 @@ func introspectionRefToTypeDef(introspectionType *introspection.TypeRef, nonNull
 		return nil, false, fmt.Errorf("unexpected type kind %s", introspectionType.Kind)
 	}
 }
+
+// gqlLiteralToJSON converts a default value from introspection, which is
+// a GraphQL literal (e.g. `FOO` for an enum or `{a: 1}` for an input), to the
+// JSON encoding used by FunctionArg.DefaultValue.
+func gqlLiteralToJSON(literal string) (core.JSON, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: fmt.Sprintf("{f(v: %s)}", literal)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse literal %q: %w", literal, err)
+	}
+	field := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	val, err := field.Arguments[0].Value.Value(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate literal %q: %w", literal, err)
+	}
+	return json.Marshal(val)
+}
@@ -34,6 +34,14 @@ func (s *directorySchema) Install() {
 		dagql.Func("entries", s.entries).
 			Doc(`Returns a list of files and directories at the given path.`).
 			ArgDoc("path", `Location of the directory to look at (e.g., "/src").`),
+		dagql.Func("entriesWithInfo", s.entriesWithInfo).
+			Doc(`Returns a list of files and directories at the given path, along with their type, permissions, ownership and link target.`,
+				`Symlinks are not followed.`).
+			ArgDoc("path", `Location of the directory to look at (e.g., "/src").`),
+		dagql.Func("stat", s.stat).
+			Doc(`Retrieves information about the file, directory or symlink at the given path.`,
+				`Symlinks are not followed.`).
+			ArgDoc("path", `Location of the entry to inspect (e.g., "bin/app").`),
 		dagql.Func("glob", s.glob).
 			Doc(`Returns a list of files and directories that matche the given pattern.`).
 			ArgDoc("pattern", `Pattern to match (e.g., "*.md").`),
@@ -66,17 +74,46 @@ func (s *directorySchema) Install() {
 			Doc(`Retrieves this directory plus a new directory created at the given path.`).
 			ArgDoc("path", `Location of the directory created (e.g., "/logs").`).
 			ArgDoc("permissions", `Permission granted to the created directory (e.g., 0777).`),
+		dagql.Func("withSymlink", s.withSymlink).
+			Doc(`Retrieves this directory plus a symlink created at the given path.`).
+			ArgDoc("target", `Location the symlink points to (e.g., "../lib/libfoo.so.1").`,
+				`The target is written as-is and doesn't need to exist.`).
+			ArgDoc("linkName", `Location of the created symlink (e.g., "lib/libfoo.so").`),
 		dagql.Func("withoutDirectory", s.withoutDirectory).
 			Doc(`Retrieves this directory with the directory at the given path removed.`).
 			ArgDoc("path", `Location of the directory to remove (e.g., ".github/").`),
+		dagql.Func("asArchive", s.asArchive).
+			Doc(`Packs the contents of this directory into an archive.`).
+			ArgDoc("format", `The format of the archive.`).
+			ArgDoc("compression", `The compression of the archive.`,
+				`Only supported for tar archives.`).
+			ArgDoc("exclude", `Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
+			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`),
 		dagql.Func("diff", s.diff).
 			Doc(`Gets the difference between this directory and an another directory.`).
 			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("digest", s.digest).
+			Doc(`Returns a sha256 digest of the contents of this directory.`,
+				`The digest covers the path, type and contents of every entry, so
+				directories with identical contents have the same digest no
+				matter how they were built.`).
+			ArgDoc("excludeMetadata", `If true, permissions, ownership and modification times are not included in the digest.`),
 		dagql.Func("export", s.export).
+			Complexity(costs(transferCost)).
 			Impure("Writes to the local host.").
 			Doc(`Writes the contents of the directory to a path on the host.`).
-			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`),
+			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`).
+			ArgDoc("wipe", `Remove files under the path that aren't in the directory, so that the path mirrors it.`,
+				`Wiping the current working directory or any of its parents, or the root of a git repository, is refused. Check the changes with exportDryRun first.`),
+		dagql.Func("exportDryRun", s.exportDryRun).
+			Impure("Reads from the local host.").
+			Doc(`Returns the changes that exporting the directory to a path on the host would make, without making them.`,
+				`Each change is a path relative to the exported path, prefixed with "A " if it would be added, "M " if it would be modified or "D " if it would be removed.`).
+			ArgDoc("path", `Location of the copied directory (e.g., "logs/").`).
+			ArgDoc("wipe", `Remove files under the path that aren't in the directory, so that the path mirrors it.`),
 		dagql.Func("dockerBuild", s.dockerBuild).
+			Complexity(costs(buildCost)).
+			Volatile().
 			Doc(`Builds a new Docker container from this directory.`).
 			ArgDoc("dockerfile", `Path to the Dockerfile to use (e.g., "frontend.Dockerfile").`).
 			ArgDoc("platform", `The platform to build.`).
@@ -169,6 +206,54 @@ func (s *directorySchema) entries(ctx context.Context, parent *core.Directory, a
 	return dagql.NewStringArray(ents...), nil
 }
 
+func (s *directorySchema) entriesWithInfo(ctx context.Context, parent *core.Directory, args entriesArgs) ([]core.FileInfo, error) {
+	return parent.EntriesWithInfo(ctx, args.Path.Value.String())
+}
+
+type statArgs struct {
+	Path string
+}
+
+func (s *directorySchema) stat(ctx context.Context, parent *core.Directory, args statArgs) (core.FileInfo, error) {
+	info, err := parent.Stat(ctx, parent.Query.Buildkit, parent.Query.Services, args.Path)
+	if err != nil {
+		return core.FileInfo{}, err
+	}
+	return core.NewFileInfo(info), nil
+}
+
+type withSymlinkArgs struct {
+	Target   string
+	LinkName string
+}
+
+func (s *directorySchema) withSymlink(ctx context.Context, parent *core.Directory, args withSymlinkArgs) (*core.Directory, error) {
+	return parent.WithSymlink(ctx, args.Target, args.LinkName)
+}
+
+type asArchiveArgs struct {
+	Format      core.ArchiveFormat      `default:"TAR"`
+	Compression core.ArchiveCompression `default:"NONE"`
+
+	core.CopyFilter
+}
+
+func (s *directorySchema) asArchive(ctx context.Context, parent *core.Directory, args asArchiveArgs) (*core.File, error) {
+	return parent.AsArchive(ctx, args.Format, args.Compression, args.CopyFilter)
+}
+
+type digestArgs struct {
+	ExcludeMetadata bool `default:"false"`
+}
+
+func (s *directorySchema) digest(ctx context.Context, parent *core.Directory, args digestArgs) (dagql.String, error) {
+	dgst, err := parent.Digest(ctx, args.ExcludeMetadata)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(dgst.String()), nil
+}
+
 type globArgs struct {
 	Pattern string
 }
@@ -242,10 +327,11 @@ func (s *directorySchema) diff(ctx context.Context, parent *core.Directory, args
 
 type dirExportArgs struct {
 	Path string
+	Wipe bool `default:"false"`
 }
 
 func (s *directorySchema) export(ctx context.Context, parent *core.Directory, args dirExportArgs) (dagql.Boolean, error) {
-	err := parent.Export(ctx, args.Path)
+	err := parent.Export(ctx, args.Path, args.Wipe)
 	if err != nil {
 		return false, err
 	}
@@ -253,6 +339,14 @@ func (s *directorySchema) export(ctx context.Context, parent *core.Directory, ar
 	return true, nil
 }
 
+func (s *directorySchema) exportDryRun(ctx context.Context, parent *core.Directory, args dirExportArgs) (dagql.Array[dagql.String], error) {
+	changes, err := parent.ExportDryRun(ctx, args.Path, args.Wipe)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(changes...), nil
+}
+
 type dirDockerBuildArgs struct {
 	Platform   dagql.Optional[core.Platform]
 	Dockerfile string                             `default:"Dockerfile"`
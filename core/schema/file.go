@@ -27,9 +27,26 @@ func (s *fileSchema) Install() {
 			Doc(`Retrieves the contents of the file.`),
 		dagql.Func("size", s.size).
 			Doc(`Retrieves the size of the file, in bytes.`),
+		dagql.Func("stat", s.stat).
+			Doc(`Retrieves information about the file, such as its type, permissions and ownership.`),
 		dagql.Func("name", s.name).
 			Doc(`Retrieves the name of the file.`),
+		dagql.Func("digest", s.digest).
+			Doc(`Returns a sha256 digest of the file.`,
+				`With excludeMetadata set, this is the digest of the file's
+				contents, i.e. the same as sha256sum.`).
+			ArgDoc("excludeMetadata", `If true, permissions, ownership and modification times are not included in the digest.`),
+		dagql.Func("extract", s.extract).
+			Doc(`Extracts this file as an archive into a new directory.`,
+				`Supports tar archives, optionally compressed with gzip or zstd, and
+				zip archives.`).
+			ArgDoc("format", `The format of the archive.`,
+				`If not set, it is detected from the archive's contents. The
+				compression of tar archives is always detected.`).
+			ArgDoc("exclude", `Exclude entries that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
+			ArgDoc("include", `Include only entries that match the given pattern (e.g., ["app/", "package.*"]).`),
 		dagql.Func("export", s.export).
+			Complexity(costs(transferCost)).
 			Impure("Writes to the local host.").
 			Doc(`Writes the file to a file path on the host.`).
 			ArgDoc("path", `Location of the written directory (e.g., "output.txt").`).
@@ -64,6 +81,37 @@ func (s *fileSchema) contents(ctx context.Context, file *core.File, args struct{
 	return dagql.NewString(string(content)), nil
 }
 
+func (s *fileSchema) digest(ctx context.Context, file *core.File, args digestArgs) (dagql.String, error) {
+	dgst, err := file.Digest(ctx, args.ExcludeMetadata)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(dgst.String()), nil
+}
+
+type fileExtractArgs struct {
+	Format dagql.Optional[core.ArchiveFormat]
+
+	core.CopyFilter
+}
+
+func (s *fileSchema) extract(ctx context.Context, file *core.File, args fileExtractArgs) (*core.Directory, error) {
+	var format *core.ArchiveFormat
+	if args.Format.Valid {
+		format = &args.Format.Value
+	}
+	return file.Extract(ctx, format, args.CopyFilter)
+}
+
+func (s *fileSchema) stat(ctx context.Context, file *core.File, args struct{}) (core.FileInfo, error) {
+	info, err := file.Stat(ctx)
+	if err != nil {
+		return core.FileInfo{}, err
+	}
+
+	return core.NewFileInfo(info), nil
+}
+
 func (s *fileSchema) size(ctx context.Context, file *core.File, args struct{}) (dagql.Int, error) {
 	info, err := file.Stat(ctx)
 	if err != nil {
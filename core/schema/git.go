@@ -2,9 +2,11 @@ package schema
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/engine/sources/gitdns"
 )
 
 var _ SchemaResolvers = &gitSchema{}
@@ -16,6 +18,7 @@ type gitSchema struct {
 func (s *gitSchema) Install() {
 	dagql.Fields[*core.Query]{
 		dagql.Func("git", s.git).
+			Complexity(costs(transferCost)).
 			Doc(`Queries a Git repository.`).
 			ArgDoc("url",
 				`URL of the git repository.`,
@@ -24,28 +27,45 @@ func (s *gitSchema) Install() {
 			ArgDoc("keepGitDir", `Set to true to keep .git directory.`).
 			ArgDoc("sshKnownHosts", `Set SSH known hosts`).
 			ArgDoc("sshAuthSocket", `Set SSH auth socket`).
+			ArgDoc("httpAuthToken", `A secret containing a token used to authenticate to an HTTPS remote.`).
 			ArgDoc("experimentalServiceHost", `A service which must be started before the repo is fetched.`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.GitRepository]{
 		dagql.Func("branch", s.branch).
+			Volatile().
 			Doc(`Returns details of a branch.`).
 			ArgDoc("name", `Branch's name (e.g., "main").`),
 		dagql.Func("tag", s.tag).
+			Volatile().
 			Doc(`Returns details of a tag.`).
 			ArgDoc("name", `Tag's name (e.g., "v0.3.9").`),
 		dagql.Func("commit", s.commit).
 			Doc(`Returns details of a commit.`).
 			// TODO: id is normally a reserved word; we should probably rename this
 			ArgDoc("id", `Identifier of the commit (e.g., "b6315d8f2810962c601af73f86831f6866ea798b").`),
+		dagql.Func("branches", s.branches).
+			Impure("Branches can change on the remote.").
+			Doc(`Returns the names of the repository's branches.`),
+		dagql.Func("tags", s.tags).
+			Impure("Tags can change on the remote.").
+			Doc(`Returns the names of the repository's tags.`).
+			ArgDoc("pattern", `Glob pattern to filter tags by (e.g., "v1.*").`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.GitRef]{
 		dagql.Func("tree", s.tree).
 			Doc(`The filesystem tree at this ref.`).
 			ArgDeprecated("sshKnownHosts", "This option should be passed to `git` instead.").
-			ArgDeprecated("sshAuthSocket", "This option should be passed to `git` instead."),
+			ArgDeprecated("sshAuthSocket", "This option should be passed to `git` instead.").
+			ArgDoc("depth",
+				`Number of commits of history to fetch.`,
+				`Only the tip of the ref is fetched by default, unless it's a commit.`).
+			ArgDoc("sparsePaths", `Only check out these paths (e.g., ["docs", "go.mod"]).`).
+			ArgDoc("skipSubmodules", `Skip checking out submodules.`).
+			ArgDoc("lfs", `Replace Git LFS pointer files with their contents.`),
 		dagql.Func("commit", s.fetchCommit).
+			Volatile().
 			Doc(`The resolved commit id at this ref.`),
 	}.Install(s.srv)
 }
@@ -57,6 +77,8 @@ type gitArgs struct {
 
 	SSHKnownHosts string                        `name:"sshKnownHosts" default:""`
 	SSHAuthSocket dagql.Optional[core.SocketID] `name:"sshAuthSocket"`
+
+	HTTPAuthToken dagql.Optional[core.SecretID] `name:"httpAuthToken"`
 }
 
 func (s *gitSchema) git(ctx context.Context, parent *core.Query, args gitArgs) (*core.GitRepository, error) {
@@ -84,17 +106,46 @@ func (s *gitSchema) git(ctx context.Context, parent *core.Query, args gitArgs) (
 		}
 		authSock = sock.Self
 	}
+	var authToken *core.Secret
+	if args.HTTPAuthToken.Valid {
+		secret, err := args.HTTPAuthToken.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		authToken = secret.Self
+	}
 	return &core.GitRepository{
 		Query:         parent,
 		URL:           args.URL,
 		KeepGitDir:    args.KeepGitDir,
 		SSHKnownHosts: args.SSHKnownHosts,
 		SSHAuthSocket: authSock,
+		HTTPAuthToken: authToken,
 		Services:      svcs,
 		Platform:      parent.Platform,
 	}, nil
 }
 
+func (s *gitSchema) branches(ctx context.Context, parent *core.GitRepository, _ struct{}) (dagql.Array[dagql.String], error) {
+	branches, err := parent.Branches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(branches...), nil
+}
+
+type tagsArgs struct {
+	Pattern dagql.Optional[dagql.String]
+}
+
+func (s *gitSchema) tags(ctx context.Context, parent *core.GitRepository, args tagsArgs) (dagql.Array[dagql.String], error) {
+	tags, err := parent.Tags(ctx, args.Pattern.GetOr("").String())
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(tags...), nil
+}
+
 type commitArgs struct {
 	ID string
 }
@@ -132,8 +183,12 @@ func (s *gitSchema) tag(ctx context.Context, parent *core.GitRepository, args ta
 }
 
 type treeArgs struct {
-	SSHKnownHosts dagql.Optional[dagql.String]  `name:"sshKnownHosts"`
-	SSHAuthSocket dagql.Optional[core.SocketID] `name:"sshAuthSocket"`
+	SSHKnownHosts  dagql.Optional[dagql.String]  `name:"sshKnownHosts"`
+	SSHAuthSocket  dagql.Optional[core.SocketID] `name:"sshAuthSocket"`
+	Depth          dagql.Optional[dagql.Int]
+	SparsePaths    dagql.Optional[dagql.ArrayInput[dagql.String]]
+	SkipSubmodules bool `default:"false"`
+	LFS            bool `name:"lfs" default:"false"`
 }
 
 func (s *gitSchema) tree(ctx context.Context, parent *core.GitRef, args treeArgs) (*core.Directory, error) {
@@ -153,7 +208,18 @@ func (s *gitSchema) tree(ctx context.Context, parent *core.GitRef, args treeArgs
 		cp.SSHAuthSocket = authSock
 		res.Repo = &cp
 	}
-	return res.Tree(ctx)
+	opts := gitdns.CheckoutOpts{
+		Depth:          args.Depth.GetOr(0).Int(),
+		SkipSubmodules: args.SkipSubmodules,
+		LFS:            args.LFS,
+	}
+	if args.Depth.Valid && opts.Depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1, got %d", opts.Depth)
+	}
+	if args.SparsePaths.Valid {
+		opts.SparsePaths = collectArrayInput(args.SparsePaths.Value, dagql.String.String)
+	}
+	return res.Tree(ctx, opts)
 }
 
 func (s *gitSchema) fetchCommit(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.String, error) {
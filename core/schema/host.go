@@ -57,6 +57,7 @@ func (s *hostSchema) Install() {
 
 	dagql.Fields[*core.Host]{
 		dagql.Func("directory", s.directory).
+			Complexity(costs(transferCost)).
 			Impure("The `directory` field loads data from the local machine.",
 				`Despite being impure, this field returns a pure Directory object. It
 				does this by uploading the requested path to the internal content store
@@ -64,9 +65,14 @@ func (s *hostSchema) Install() {
 			Doc(`Accesses a directory on the host.`).
 			ArgDoc("path", `Location of the directory to access (e.g., ".").`).
 			ArgDoc("exclude", `Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
-			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`),
+			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`).
+			ArgDoc("daggerignore", `Also exclude artifacts that match the patterns in the .daggerignore file at the root of the directory, which uses the same syntax as .gitignore.`,
+				`Only the file at the root is read; .daggerignore files in subdirectories are not honored.`).
+			ArgDoc("gitignore", `Also exclude artifacts that match the patterns in the .gitignore file at the root of the directory.`,
+				`Only the file at the root is read; .gitignore files in subdirectories are not honored.`),
 
 		dagql.Func("file", s.file).
+			Complexity(costs(transferCost)).
 			Impure("The `field` field loads data from the local machine.",
 				`Despite being impure, this field returns a pure File object. It does
 				this by uploading the requested path to the internal content store and
@@ -126,10 +132,13 @@ type hostDirectoryArgs struct {
 	Path string
 
 	core.CopyFilter
+
+	Daggerignore bool `default:"false"`
+	Gitignore    bool `default:"false"`
 }
 
 func (s *hostSchema) directory(ctx context.Context, host *core.Host, args hostDirectoryArgs) (dagql.Instance[*core.Directory], error) {
-	return host.Directory(ctx, s.srv, args.Path, "host.directory", args.CopyFilter)
+	return host.Directory(ctx, s.srv, args.Path, "host.directory", args.CopyFilter, args.Daggerignore, args.Gitignore)
 }
 
 type hostSocketArgs struct {
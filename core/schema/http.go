@@ -2,9 +2,12 @@ package schema
 
 import (
 	"context"
+	"fmt"
+	"io/fs"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/sources/httpdns"
 	"github.com/moby/buildkit/client/llb"
@@ -20,15 +23,32 @@ type httpSchema struct {
 func (s *httpSchema) Install() {
 	dagql.Fields[*core.Query]{
 		dagql.Func("http", s.http).
+			Complexity(costs(transferCost)).
+			Volatile(func(call *idproto.ID) bool {
+				return callArg(call, "checksum") == nil
+			}).
 			Doc(`Returns a file containing an http remote url content.`).
 			ArgDoc("url", `HTTP url to get the content from (e.g., "https://docs.dagger.io").`).
-			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`),
+			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`).
+			ArgDoc("checksum",
+				`Expected digest of the content (e.g., "sha256:...").`,
+				`The download fails if the content doesn't match, and a matching
+				download is never fetched again.`).
+			ArgDoc("headers", `Headers to send with the request.`).
+			ArgDoc("authHeader", `A secret whose value is sent as the Authorization header.`).
+			ArgDoc("name", `Name of the downloaded file.`).
+			ArgDoc("permissions", `Permission given to the downloaded file (e.g., 0600).`),
 	}.Install(s.srv)
 }
 
 type httpArgs struct {
 	URL                     string
 	ExperimentalServiceHost dagql.Optional[core.ServiceID]
+	Checksum                dagql.Optional[dagql.String]
+	Headers                 []dagql.InputObject[core.HTTPHeader] `default:"[]"`
+	AuthHeader              dagql.Optional[core.SecretID]
+	Name                    dagql.Optional[dagql.String]
+	Permissions             dagql.Optional[dagql.Int]
 }
 
 func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs) (*core.File, error) {
@@ -37,6 +57,9 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	// of following more optimized cache codepaths.
 	// Do a hash encode to prevent conflicts with use of `/` in the URL while also not hitting max filename limits
 	filename := digest.FromString(args.URL).Encoded()
+	if args.Name.Valid {
+		filename = args.Name.Value.String()
+	}
 
 	svcs := core.ServiceBindings{}
 	if args.ExperimentalServiceHost.Valid {
@@ -58,6 +81,28 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	opts := []llb.HTTPOption{
 		llb.Filename(filename),
 	}
+	if args.Checksum.Valid {
+		dgst, err := digest.Parse(args.Checksum.Value.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum: %w", err)
+		}
+		opts = append(opts, llb.Checksum(dgst))
+	}
+	if args.Permissions.Valid {
+		opts = append(opts, llb.Chmod(fs.FileMode(args.Permissions.Value.Int())))
+	}
+
+	hack := httpdns.DaggerHTTPURLHack{URL: args.URL}
+	for _, h := range collectInputsSlice(args.Headers) {
+		hack.Headers = append(hack.Headers, httpdns.HTTPHeader{Name: h.Name, Value: h.Value})
+	}
+	if args.AuthHeader.Valid {
+		secret, err := args.AuthHeader.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		hack.AuthHeaderSecret = secret.Self.Name
+	}
 
 	useDNS := len(svcs) > 0
 
@@ -67,7 +112,7 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	}
 
 	var st llb.State
-	if useDNS {
+	if useDNS || len(hack.Headers) > 0 || hack.AuthHeaderSecret != "" {
 		// NB: only configure search domains if we're directly using a service, or
 		// if we're nested.
 		//
@@ -75,7 +120,10 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 		// that use a Buildkit frontend (# syntax = ...).
 		//
 		// TODO: add API cap
-		st = httpdns.State(args.URL, clientMetadata.ClientIDs(), opts...)
+		if useDNS {
+			hack.ClientIDs = clientMetadata.ClientIDs()
+		}
+		st = httpdns.StateWithHack(hack, opts...)
 	} else {
 		st = llb.HTTP(args.URL, opts...)
 	}
@@ -55,6 +55,7 @@ func (s *moduleSchema) Install() {
 
 	dagql.Fields[*core.Directory]{
 		dagql.NodeFunc("asModule", s.directoryAsModule).
+			Complexity(costs(buildCost)).
 			Doc(`Load the directory as a Dagger module`).
 			ArgDoc("sourceSubpath",
 				`An optional subpath of the directory which contains the module's source code.`,
@@ -99,6 +100,12 @@ func (s *moduleSchema) Install() {
 		dagql.Func("withInterface", s.moduleWithInterface).
 			Doc(`This module plus the given Interface type and associated functions`),
 
+		dagql.Func("withEnum", s.moduleWithEnum).
+			Doc(`This module plus the given Enum type and associated values`),
+
+		dagql.Func("withScalar", s.moduleWithScalar).
+			Doc(`This module plus the given Scalar type`),
+
 		dagql.NodeFunc("serve", s.moduleServe).
 			Impure(`Mutates the calling session's global schema.`).
 			Doc(`Serve a module's API in the current session.`,
@@ -143,6 +150,26 @@ func (s *moduleSchema) Install() {
 		dagql.Func("withInterface", s.typeDefWithInterface).
 			Doc(`Returns a TypeDef of kind Interface with the provided name.`),
 
+		dagql.Func("withEnum", s.typeDefWithEnum).
+			Doc(`Returns a TypeDef of kind Enum with the provided name.`,
+				`Note that an enum's values may be omitted if the intent is only to
+				refer to an enum. This is how functions are able to return their own,
+				or any other circular reference.`).
+			ArgDoc("name", `The name of the enum`).
+			ArgDoc("description", `A doc string for the enum, if any`),
+
+		dagql.Func("withEnumValue", s.typeDefWithEnumValue).
+			Doc(`Adds a static value for an Enum TypeDef, failing if the type is not an enum.`).
+			ArgDoc("value", `The name of the value in the enum`).
+			ArgDoc("description", `A doc string for the value, if any`),
+
+		dagql.Func("withScalar", s.typeDefWithScalar).
+			Doc(`Returns a TypeDef of kind Scalar with the provided name.`,
+				`Values of the scalar are passed between the caller and the module
+				as-is, without any validation by the engine.`).
+			ArgDoc("name", `The name of the scalar`).
+			ArgDoc("description", `A doc string for the scalar, if any`),
+
 		dagql.Func("withField", s.typeDefWithObjectField).
 			Doc(`Adds a static field for an Object TypeDef, failing if the type is not an object.`).
 			ArgDoc("name", `The name of the field in the object`).
@@ -159,6 +186,9 @@ func (s *moduleSchema) Install() {
 	dagql.Fields[*core.ObjectTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.InterfaceTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.InputTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.EnumTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.EnumValueTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.ScalarTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.FieldTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.ListTypeDef]{}.Install(s.dag)
 
@@ -213,6 +243,33 @@ func (s *moduleSchema) typeDefWithInterface(ctx context.Context, def *core.TypeD
 	return def.WithInterface(args.Name, args.Description), nil
 }
 
+func (s *moduleSchema) typeDefWithEnum(ctx context.Context, def *core.TypeDef, args struct {
+	Name        string
+	Description string `default:""`
+}) (*core.TypeDef, error) {
+	if args.Name == "" {
+		return nil, fmt.Errorf("enum type def must have a name")
+	}
+	return def.WithEnum(args.Name, args.Description), nil
+}
+
+func (s *moduleSchema) typeDefWithEnumValue(ctx context.Context, def *core.TypeDef, args struct {
+	Value       string
+	Description string `default:""`
+}) (*core.TypeDef, error) {
+	return def.WithEnumValue(args.Value, args.Description)
+}
+
+func (s *moduleSchema) typeDefWithScalar(ctx context.Context, def *core.TypeDef, args struct {
+	Name        string
+	Description string `default:""`
+}) (*core.TypeDef, error) {
+	if args.Name == "" {
+		return nil, fmt.Errorf("scalar type def must have a name")
+	}
+	return def.WithScalar(args.Name, args.Description), nil
+}
+
 func (s *moduleSchema) typeDefWithObjectField(ctx context.Context, def *core.TypeDef, args struct {
 	Name        string
 	TypeDef     core.TypeDefID
@@ -500,3 +557,23 @@ func (s *moduleSchema) moduleWithInterface(ctx context.Context, modMeta *core.Mo
 	}
 	return modMeta.WithInterface(ctx, def.Self)
 }
+
+func (s *moduleSchema) moduleWithEnum(ctx context.Context, modMeta *core.Module, args struct {
+	Enum core.TypeDefID
+}) (_ *core.Module, rerr error) {
+	def, err := args.Enum.Load(ctx, s.dag)
+	if err != nil {
+		return nil, err
+	}
+	return modMeta.WithEnum(ctx, def.Self)
+}
+
+func (s *moduleSchema) moduleWithScalar(ctx context.Context, modMeta *core.Module, args struct {
+	Scalar core.TypeDefID
+}) (_ *core.Module, rerr error) {
+	def, err := args.Scalar.Load(ctx, s.dag)
+	if err != nil {
+		return nil, err
+	}
+	return modMeta.WithScalar(ctx, def.Self)
+}
@@ -32,15 +32,22 @@ func (s *querySchema) Install() {
 	core.ImageMediaTypesEnum.Install(s.srv)
 	core.CacheSharingModes.Install(s.srv)
 	core.TypeDefKinds.Install(s.srv)
+	core.ReturnTypes.Install(s.srv)
+	core.FileTypes.Install(s.srv)
+	core.ArchiveFormats.Install(s.srv)
+	core.ArchiveCompressions.Install(s.srv)
 
 	dagql.MustInputSpec(pipeline.Label{}).Install(s.srv)
 	dagql.MustInputSpec(core.PortForward{}).Install(s.srv)
 	dagql.MustInputSpec(core.BuildArg{}).Install(s.srv)
+	dagql.MustInputSpec(core.HTTPHeader{}).Install(s.srv)
 
 	dagql.Fields[EnvVariable]{}.Install(s.srv)
 
 	dagql.Fields[core.Port]{}.Install(s.srv)
 
+	dagql.Fields[core.FileInfo]{}.Install(s.srv)
+
 	dagql.Fields[Label]{}.Install(s.srv)
 
 	dagql.Fields[*core.Query]{
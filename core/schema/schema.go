@@ -7,14 +7,19 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/containerd/containerd/content"
 	"github.com/dagger/dagger/auth"
 	"github.com/dagger/dagger/cmd/codegen/introspection"
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/tracing"
@@ -33,8 +38,23 @@ type InitializeArgs struct {
 	LeaseManager   *leaseutil.Manager
 	Auth           *auth.RegistryAuthProvider
 	Secrets        *core.SecretStore
+
+	// CacheOpts configures the eviction of results from the in-memory cache.
+	CacheOpts dagql.CacheOpts
+
+	// Limits bounds the queries made in the session.
+	Limits dagql.Limits
+
+	// DiskCache, if set, persists the results of pure selections across
+	// engine restarts.
+	DiskCache *dagql.DiskCache
 }
 
+// maxInternedIDs bounds the number of IDs that clients may refer to by
+// reference in a session. Clients only pass references to IDs that were
+// returned to them shortly before, so only the most recent ones are kept.
+const maxInternedIDs = 10000
+
 type APIServer struct {
 	// The root of the schema, housing all of the state and dependencies for the
 	// server for easy access from descendent objects.
@@ -54,10 +74,20 @@ func New(ctx context.Context, params InitializeArgs) (*APIServer, error) {
 	root.Auth = params.Auth
 
 	dag := dagql.NewServer(root)
+	dag.Cache = dagql.NewCacheWithOpts(params.CacheOpts)
+	if params.DiskCache != nil {
+		dag.Cache = params.DiskCache.Wrap(dag.Cache)
+	}
 
 	// stash away the cache so we can share it between other servers
 	root.Cache = dag.Cache
 
+	dag.Limits = params.Limits
+	root.Limits = params.Limits
+
+	dag.IDs = idproto.NewStore(maxInternedIDs)
+	root.IDs = dag.IDs
+
 	dag.Around(tracing.AroundFunc)
 
 	coreMod := &CoreMod{dag: dag}
@@ -137,7 +167,7 @@ func (s *APIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	srv := handler.NewDefaultServer(schema)
+	srv := newGraphQLHandler(schema)
 	// NB: break glass when needed:
 	// srv.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
 	// 	res := next(ctx)
@@ -180,6 +210,20 @@ func (s *APIServer) CurrentServedDeps(ctx context.Context) (*core.ModDeps, error
 	return callCtx.Deps, nil
 }
 
+// CacheStats reports the usage of the in-memory cache shared by the server's
+// schemas, if it reports any.
+func (s *APIServer) CacheStats() dagql.CacheStats {
+	if stats, ok := s.root.Cache.(dagql.CacheStatsReporter); ok {
+		return stats.Stats()
+	}
+	return dagql.CacheStats{}
+}
+
+// Close releases the IDs interned during the session.
+func (s *APIServer) Close() error {
+	return s.root.IDs.Close()
+}
+
 func (s *APIServer) Introspect(ctx context.Context) (string, error) {
 	return s.root.DefaultDeps.SchemaIntrospectionJSON(ctx)
 }
@@ -229,3 +273,28 @@ func gqlFieldName(name string) string {
 	// gql field name is uncapitalized camel case
 	return strcase.ToLowerCamel(name)
 }
+
+// newGraphQLHandler is like handler.NewDefaultServer, but also accepts
+// subscriptions over server-sent events, in addition to websockets.
+func newGraphQLHandler(schema graphql.ExecutableSchema) *handler.Server {
+	srv := handler.New(schema)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	// NB: must come before POST, which would handle the request otherwise
+	srv.AddTransport(transport.SSE{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(100),
+	})
+
+	return srv
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/99designs/gqlgen/client"
 	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
 	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func TestNamespaceObjects(t *testing.T) {
@@ -72,11 +75,23 @@ func TestCoreModTypeDefs(t *testing.T) {
 			typeByName[typeDef.AsObject.Value.Name] = typeDef
 		case core.TypeDefKindInput:
 			typeByName[typeDef.AsInput.Value.Name] = typeDef
+		case core.TypeDefKindEnum:
+			typeByName[typeDef.AsEnum.Value.Name] = typeDef
 		}
 	}
 
 	// just verify some subset of objects+functions as a sanity check
 
+	// Query
+	queryTypeDef, ok := typeByName["Query"]
+	require.True(t, ok)
+	queryObj := queryTypeDef.AsObject.Value
+
+	containerFn, ok := queryObj.FunctionByName("container")
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindObject, containerFn.ReturnType.Kind)
+	require.Equal(t, "Container", containerFn.ReturnType.AsObject.Value.Name)
+
 	// Container
 	ctrTypeDef, ok := typeByName["Container"]
 	require.True(t, ok)
@@ -115,6 +130,35 @@ func TestCoreModTypeDefs(t *testing.T) {
 	require.Equal(t, core.TypeDefKindString, withMountedDirectoryFnOwnerArg.TypeDef.Kind)
 	require.True(t, withMountedDirectoryFnOwnerArg.TypeDef.Optional)
 
+	withExposedPortFn, ok := ctrObj.FunctionByName("withExposedPort")
+	require.True(t, ok)
+
+	withExposedPortFnProtocolArg := withExposedPortFn.Args[1]
+	require.Equal(t, "protocol", withExposedPortFnProtocolArg.Name)
+	require.Equal(t, core.TypeDefKindEnum, withExposedPortFnProtocolArg.TypeDef.Kind)
+	require.JSONEq(t, `"TCP"`, string(withExposedPortFnProtocolArg.DefaultValue))
+
+	withExposedPortFnSkipHealthcheckArg := withExposedPortFn.Args[3]
+	require.Equal(t, "experimentalSkipHealthcheck", withExposedPortFnSkipHealthcheckArg.Name)
+	require.JSONEq(t, `false`, string(withExposedPortFnSkipHealthcheckArg.DefaultValue))
+
+	withExecFn, ok := ctrObj.FunctionByName("withExec")
+	require.True(t, ok)
+	var withExecFnExpectArg *core.FunctionArg
+	for _, arg := range withExecFn.Args {
+		if arg.Name == "expect" {
+			withExecFnExpectArg = arg
+		}
+	}
+	require.NotNil(t, withExecFnExpectArg)
+	require.Equal(t, core.TypeDefKindEnum, withExecFnExpectArg.TypeDef.Kind)
+	require.Equal(t, "ReturnType", withExecFnExpectArg.TypeDef.AsEnum.Value.Name)
+	require.JSONEq(t, `"SUCCESS"`, string(withExecFnExpectArg.DefaultValue))
+
+	exitCodeFn, ok := ctrObj.FunctionByName("exitCode")
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindInteger, exitCodeFn.ReturnType.Kind)
+
 	// PortForward input type
 	portForwardTypeDef, ok := typeByName["PortForward"]
 	require.True(t, ok)
@@ -140,7 +184,21 @@ func TestCoreModTypeDefs(t *testing.T) {
 	require.Equal(t, core.TypeDefKindInteger, backendPortField.TypeDef.Kind)
 	require.False(t, backendPortField.TypeDef.Optional)
 	require.NotNil(t, protocolField)
-	require.Equal(t, core.TypeDefKindString, protocolField.TypeDef.Kind)
+	require.Equal(t, core.TypeDefKindEnum, protocolField.TypeDef.Kind)
+	require.Equal(t, "NetworkProtocol", protocolField.TypeDef.AsEnum.Value.Name)
+
+	// NetworkProtocol enum type
+	networkProtocolTypeDef, ok := typeByName["NetworkProtocol"]
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindEnum, networkProtocolTypeDef.Kind)
+	var protocolValues []string
+	for _, val := range networkProtocolTypeDef.AsEnum.Value.Values {
+		protocolValues = append(protocolValues, val.Name)
+	}
+	require.ElementsMatch(t, []string{"TCP", "UDP"}, protocolValues)
+
+	_, ok = typeByName["__TypeKind"]
+	require.False(t, ok)
 
 	// File
 	fileTypeDef, ok := typeByName["File"]
@@ -164,4 +222,120 @@ func TestCoreModTypeDefs(t *testing.T) {
 	require.Equal(t, "allowParentDirPath", exportFnAllowParentDirPathArg.Name)
 	require.Equal(t, core.TypeDefKindBoolean, exportFnAllowParentDirPathArg.TypeDef.Kind)
 	require.True(t, exportFnAllowParentDirPathArg.TypeDef.Optional)
+
+	statFn, ok := fileObj.FunctionByName("stat")
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindObject, statFn.ReturnType.Kind)
+	require.Equal(t, "FileInfo", statFn.ReturnType.AsObject.Value.Name)
+
+	digestFn, ok := fileObj.FunctionByName("digest")
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindString, digestFn.ReturnType.Kind)
+	require.Len(t, digestFn.Args, 1)
+	require.Equal(t, "excludeMetadata", digestFn.Args[0].Name)
+	require.True(t, digestFn.Args[0].TypeDef.Optional)
+	require.JSONEq(t, `false`, string(digestFn.Args[0].DefaultValue))
+
+	extractFn, ok := fileObj.FunctionByName("extract")
+	require.True(t, ok)
+	require.Equal(t, "Directory", extractFn.ReturnType.AsObject.Value.Name)
+	require.Equal(t, "format", extractFn.Args[0].Name)
+	require.Equal(t, core.TypeDefKindEnum, extractFn.Args[0].TypeDef.Kind)
+	require.Equal(t, "ArchiveFormat", extractFn.Args[0].TypeDef.AsEnum.Value.Name)
+	require.True(t, extractFn.Args[0].TypeDef.Optional)
+
+	// Directory
+	dirTypeDef, ok := typeByName["Directory"]
+	require.True(t, ok)
+	dirObj := dirTypeDef.AsObject.Value
+
+	asArchiveFn, ok := dirObj.FunctionByName("asArchive")
+	require.True(t, ok)
+	require.Equal(t, "File", asArchiveFn.ReturnType.AsObject.Value.Name)
+	require.Len(t, asArchiveFn.Args, 4)
+	require.Equal(t, "format", asArchiveFn.Args[0].Name)
+	require.JSONEq(t, `"TAR"`, string(asArchiveFn.Args[0].DefaultValue))
+	require.Equal(t, "compression", asArchiveFn.Args[1].Name)
+	require.Equal(t, "ArchiveCompression", asArchiveFn.Args[1].TypeDef.AsEnum.Value.Name)
+	require.JSONEq(t, `"NONE"`, string(asArchiveFn.Args[1].DefaultValue))
+
+	// FileType enum type
+	fileTypeTypeDef, ok := typeByName["FileType"]
+	require.True(t, ok)
+	require.Equal(t, core.TypeDefKindEnum, fileTypeTypeDef.Kind)
+	var fileTypeValues []string
+	for _, val := range fileTypeTypeDef.AsEnum.Value.Values {
+		fileTypeValues = append(fileTypeValues, val.Name)
+	}
+	require.ElementsMatch(t, []string{"REGULAR_TYPE", "DIRECTORY_TYPE", "SYMLINK_TYPE", "UNKNOWN_TYPE"}, fileTypeValues)
+}
+
+type streamingRoot struct{}
+
+func (streamingRoot) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Query",
+		NonNull:   true,
+	}
+}
+
+func TestCoreFieldCosts(t *testing.T) {
+	ctx := context.Background()
+	api, err := New(ctx, InitializeArgs{})
+	require.NoError(t, err)
+
+	dag, err := api.root.DefaultDeps.Schema(ctx)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		typeName, field string
+		args            map[string]any
+		cost            int
+	}{
+		{"Container", "withExec", map[string]any{"args": []any{"true"}}, execCost},
+		{"Container", "from", map[string]any{"address": "alpine"}, transferCost},
+		{"Directory", "dockerBuild", nil, buildCost},
+		{"Host", "directory", map[string]any{"path": "."}, transferCost},
+	} {
+		cost, ok := dag.Complexity(tc.typeName, tc.field, 3, tc.args)
+		require.True(t, ok, "%s.%s has no cost", tc.typeName, tc.field)
+		require.Equal(t, tc.cost+3, cost, "%s.%s", tc.typeName, tc.field)
+	}
+
+	// cheap fields don't declare a cost
+	_, ok := dag.Complexity("Container", "withEnvVariable", 3, map[string]any{"name": "FOO", "value": "bar"})
+	require.False(t, ok)
+}
+
+func TestGraphQLHandlerSSE(t *testing.T) {
+	t.Parallel()
+
+	srv := dagql.NewServer(streamingRoot{})
+	dagql.Subscribe(srv, Subscription{})
+	dagql.Fields[Subscription]{
+		dagql.Func("chunks", func(ctx context.Context, self Subscription, _ struct{}) (dagql.Stream[dagql.String], error) {
+			ch := make(chan dagql.String, 2)
+			ch <- "hello, "
+			ch <- "world!"
+			close(ch)
+			return dagql.ChanStream(ch), nil
+		}).Impure("Streams chunks."),
+	}.Install(srv)
+
+	gql := client.New(newGraphQLHandler(srv))
+	sse := gql.SSE(context.Background(), `subscription { chunks }`)
+	defer sse.Close()
+
+	var chunks []string
+	for {
+		var res client.SSEResponse
+		require.NoError(t, sse.Next(&res))
+		if res.Data == nil {
+			// completed
+			break
+		}
+		require.Nil(t, res.Errors)
+		chunks = append(chunks, res.Data.(map[string]any)["chunks"].(string))
+	}
+	require.Equal(t, []string{"hello, ", "world!"}, chunks)
 }
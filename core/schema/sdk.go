@@ -72,6 +72,8 @@ type moduleSDK struct {
 func (s *moduleSchema) newModuleSDK(ctx context.Context, root *core.Query, sdkModMeta dagql.Instance[*core.Module]) (*moduleSDK, error) {
 	dag := dagql.NewServer[*core.Query](root)
 	dag.Cache = root.Cache
+	dag.Limits = root.Limits
+	dag.IDs = root.IDs
 	if err := sdkModMeta.Self.Install(ctx, dag); err != nil {
 		return nil, fmt.Errorf("failed to install sdk module %s: %w", sdkModMeta.Self.Name(), err)
 	}
@@ -23,7 +23,21 @@ func (s *serviceSchema) Install() {
 	dagql.Fields[*core.Container]{
 		dagql.Func("asService", s.containerAsService).
 			Doc(`Turn the container into a Service.`,
-				`Be sure to set any exposed ports before this conversion.`),
+				`Be sure to set any exposed ports before this conversion.`).
+			ArgDoc("timeout",
+				`Maximum time in seconds the service may run for before being killed.`,
+				`If 0, the service may run indefinitely.`).
+			ArgDoc("memoryLimit",
+				`Maximum amount of memory in bytes the service may use before being
+				killed.`,
+				`If 0, memory is not limited.`).
+			ArgDoc("cpuShares",
+				`Relative CPU weight of the service, compared to other containers
+				(e.g., 1024).`,
+				`If 0, the default weight is used.`).
+			ArgDoc("pidsLimit",
+				`Maximum number of processes the service may run.`,
+				`If 0, the number of processes is not limited.`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.Service]{
@@ -43,11 +57,13 @@ func (s *serviceSchema) Install() {
 			ArgDoc("scheme", `Return a URL with the given scheme, eg. http for http://`),
 
 		dagql.NodeFunc("start", s.start).
+			Complexity(costs(execCost)).
 			Impure("Imperatively mutates runtime state.").
 			Doc(`Start the service and wait for its health checks to succeed.`,
 				`Services bound to a Container do not need to be manually started.`),
 
 		dagql.NodeFunc("up", s.up).
+			Complexity(costs(execCost)).
 			Impure("Starts a host tunnel, possibly with ports that change each time it's started.").
 			Doc(`Creates a tunnel that forwards traffic from the caller's network to this service.`),
 
@@ -57,8 +73,12 @@ func (s *serviceSchema) Install() {
 	}.Install(s.srv)
 }
 
-func (s *serviceSchema) containerAsService(ctx context.Context, parent *core.Container, args struct{}) (*core.Service, error) {
-	return parent.Service(ctx)
+type containerAsServiceArgs struct {
+	core.ContainerExecLimits
+}
+
+func (s *serviceSchema) containerAsService(ctx context.Context, parent *core.Container, args containerAsServiceArgs) (*core.Service, error) {
+	return parent.Service(ctx, args.ContainerExecLimits)
 }
 
 func (s *serviceSchema) hostname(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (dagql.String, error) {
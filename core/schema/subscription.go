@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"context"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type subscriptionSchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &subscriptionSchema{}
+
+// Subscription is the root of subscription operations, each of which follows
+// a stream of values from a pipeline.
+type Subscription struct{}
+
+func (Subscription) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Subscription",
+		NonNull:   true,
+	}
+}
+
+func (Subscription) TypeDescription() string {
+	return "The root of subscriptions, which stream values from a pipeline as they're produced."
+}
+
+func (s *subscriptionSchema) Install() {
+	dagql.Subscribe(s.srv, Subscription{})
+
+	dagql.Fields[Subscription]{
+		dagql.Func("containerStdout", s.containerStdout).
+			Complexity(costs(execCost)).
+			Impure("Follows the output of the command as it runs.").
+			Doc(`Streams chunks of the output of the last command executed by a
+				container as they are written.`,
+				`If the command is cached, its whole output is sent at once.`,
+				`Will execute default command if none is set, or error if there's no default.`).
+			ArgDoc("container", `The container whose command to follow.`),
+
+		dagql.Func("serviceLogs", s.serviceLogs).
+			Complexity(costs(execCost)).
+			Impure("Follows the output of the running service.").
+			Doc(`Streams chunks of the combined stdout and stderr of a service as
+				they are written, starting the service if it isn't running.`,
+				`The stream ends when the service exits.`).
+			ArgDoc("service", `The service whose output to follow.`),
+	}.Install(s.srv)
+}
+
+type containerStdoutArgs struct {
+	Container core.ContainerID
+}
+
+func (s *subscriptionSchema) containerStdout(ctx context.Context, parent Subscription, args containerStdoutArgs) (dagql.Stream[dagql.String], error) {
+	ctr, err := args.Container.Load(ctx, s.srv)
+	if err != nil {
+		return dagql.Stream[dagql.String]{}, err
+	}
+	return ctr.Self.StdoutStream(ctx)
+}
+
+type serviceLogsArgs struct {
+	Service core.ServiceID
+}
+
+func (s *subscriptionSchema) serviceLogs(ctx context.Context, parent Subscription, args serviceLogsArgs) (dagql.Stream[dagql.String], error) {
+	svc, err := args.Service.Load(ctx, s.srv)
+	if err != nil {
+		return dagql.Stream[dagql.String]{}, err
+	}
+	return svc.Self.Logs(ctx, svc.ID())
+}
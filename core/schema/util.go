@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
 	"github.com/dagger/dagger/engine/buildkit"
 )
 
@@ -23,6 +24,39 @@ func Syncer[T Evaluatable]() dagql.Field[T] {
 	})
 }
 
+// Estimated costs of fields that do significant work in the engine, enforced
+// against dagql.Limits.MaxCost. Every other field costs 1.
+const (
+	// transferCost is the cost of pulling from or pushing to a remote, or of
+	// transferring to or from the client's host.
+	transferCost = 10
+
+	// execCost is the cost of running a command.
+	execCost = 20
+
+	// buildCost is the cost of a build that may run any number of commands.
+	buildCost = 50
+)
+
+// costs returns a ComplexityFunc for a field that does work of the given
+// estimated cost, on top of the cost of its sub-selections.
+func costs(cost int) dagql.ComplexityFunc {
+	return func(_ map[string]dagql.Input, childComplexity int) int {
+		return cost + childComplexity
+	}
+}
+
+// callArg returns the value passed for the named argument in call, or nil if
+// it wasn't set.
+func callArg(call *idproto.ID, name string) *idproto.Literal {
+	for _, arg := range call.Args {
+		if arg.Name == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
 func collectInputs[T dagql.Type](inputs dagql.Optional[dagql.ArrayInput[dagql.InputObject[T]]]) []T {
 	if !inputs.Valid {
 		return nil
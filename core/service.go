@@ -9,6 +9,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/dagger/dagger/core/pipeline"
@@ -32,6 +33,8 @@ type Service struct {
 
 	// Container is the container to run as a service.
 	Container *Container `json:"container"`
+	// Limits bounds the time and resources available to the container.
+	Limits ContainerExecLimits `json:"limits"`
 
 	// TunnelUpstream is the service that this service is tunnelling to.
 	TunnelUpstream *dagql.Instance[*Service] `json:"upstream,omitempty"`
@@ -178,6 +181,31 @@ func (svc *Service) StartAndTrack(ctx context.Context, id *idproto.ID) error {
 	return err
 }
 
+// Logs starts the service if it isn't already running, and follows its
+// output. The service is detached from once the stream ends.
+func (svc *Service) Logs(ctx context.Context, id *idproto.ID) (dagql.Stream[dagql.String], error) {
+	running, err := svc.Query.Services.Start(ctx, id, svc)
+	if err != nil {
+		return dagql.Stream[dagql.String]{}, err
+	}
+	if running.Logs == nil {
+		svc.Query.Services.Detach(ctx, running)
+		return dagql.Stream[dagql.String]{}, fmt.Errorf("logs are only available for container services")
+	}
+
+	logs := running.Logs.Follow()
+	var detachOnce sync.Once
+	return dagql.NewStream(func(ctx context.Context) (dagql.String, error) {
+		chunk, err := logs.Next(ctx)
+		if err != nil {
+			detachOnce.Do(func() {
+				svc.Query.Services.Detach(context.WithoutCancel(ctx), running)
+			})
+		}
+		return chunk, err
+	}), nil
+}
+
 func (svc *Service) Stop(ctx context.Context, id *idproto.ID) error {
 	return svc.Query.Services.Stop(ctx, id)
 }
@@ -347,11 +375,17 @@ func (svc *Service) startContainer(
 
 	env := append([]string{}, execOp.Meta.Env...)
 	env = append(env, proxyEnvList(execOp.Meta.ProxyEnv)...)
+	limitsEnv, err := svc.Limits.env()
+	if err != nil {
+		return nil, err
+	}
+	env = append(env, limitsEnv...)
 	if interactive {
 		env = append(env, ShimEnableTTYEnvVar+"=1")
 	}
 
 	outBuf := new(bytes.Buffer)
+	logs := NewLogBroadcaster()
 	var stdinCtr, stdoutClient, stderrClient io.ReadCloser
 	var stdinClient, stdoutCtr, stderrCtr io.WriteCloser
 	if forwardStdin != nil {
@@ -361,13 +395,13 @@ func (svc *Service) startContainer(
 	if forwardStdout != nil {
 		stdoutClient, stdoutCtr = io.Pipe()
 	} else {
-		stdoutCtr = nopCloser{io.MultiWriter(vtx.Stdout(), outBuf)}
+		stdoutCtr = nopCloser{io.MultiWriter(vtx.Stdout(), outBuf, logs)}
 	}
 
 	if forwardStderr != nil {
 		stderrClient, stderrCtr = io.Pipe()
 	} else {
-		stderrCtr = nopCloser{io.MultiWriter(vtx.Stderr(), outBuf)}
+		stderrCtr = nopCloser{io.MultiWriter(vtx.Stderr(), outBuf, logs)}
 	}
 
 	svcProc, err := gc.Start(ctx, bkgw.StartRequest{
@@ -408,6 +442,7 @@ func (svc *Service) startContainer(
 			if stderrClient != nil {
 				stderrClient.Close()
 			}
+			logs.Close()
 			close(exited)
 		}()
 
@@ -461,6 +496,7 @@ func (svc *Service) startContainer(
 					return err
 				}
 			},
+			Logs: logs,
 		}, nil
 	case err := <-exited:
 		if err != nil {
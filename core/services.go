@@ -61,6 +61,10 @@ type RunningService struct {
 
 	// Block until the service has exited or the provided context is canceled.
 	Wait func(context.Context) error
+
+	// Logs is the combined stdout and stderr of a Container service, unless
+	// they're forwarded to a client instead.
+	Logs *LogBroadcaster
 }
 
 // ServiceKey is a unique identifier for a service.
@@ -91,7 +91,7 @@ func (container *Container) runTerminal(
 		return fmt.Errorf("failed to create container for interactive terminal: %w", err)
 	}
 
-	svc, err := container.Service(ctx)
+	svc, err := container.Service(ctx, ContainerExecLimits{})
 	if err != nil {
 		return fmt.Errorf("failed to create service for interactive terminal: %w", err)
 	}
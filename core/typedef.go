@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/dagger/dagger/dagql"
@@ -65,7 +66,9 @@ func (fn Function) Clone() *Function {
 	return &cp
 }
 
-func (fn *Function) FieldSpec() (dagql.FieldSpec, error) {
+// FieldSpec returns the spec of the function's field in the schema, resolving
+// the types of its args against the module serving it.
+func (fn *Function) FieldSpec(ctx context.Context, mod *Module) (dagql.FieldSpec, error) {
 	spec := dagql.FieldSpec{
 		Name:           fn.Name,
 		Description:    formatGqlDescription(fn.Description),
@@ -73,7 +76,11 @@ func (fn *Function) FieldSpec() (dagql.FieldSpec, error) {
 		ImpurityReason: "Module functions are currently always impure.", // TODO
 	}
 	for _, arg := range fn.Args {
-		input := arg.TypeDef.ToInput()
+		argType, err := mod.resolveEnums(ctx, arg.TypeDef)
+		if err != nil {
+			return spec, fmt.Errorf("failed to resolve type of arg %q: %w", arg.Name, err)
+		}
+		input := argType.ToInput()
 		var defaultVal dagql.Input
 		if arg.DefaultValue != nil {
 			var val any
@@ -281,6 +288,8 @@ type TypeDef struct {
 	AsObject    dagql.Nullable[*ObjectTypeDef]    `field:"true" doc:"If kind is OBJECT, the object-specific type definition. If kind is not OBJECT, this will be null."`
 	AsInterface dagql.Nullable[*InterfaceTypeDef] `field:"true" doc:"If kind is INTERFACE, the interface-specific type definition. If kind is not INTERFACE, this will be null."`
 	AsInput     dagql.Nullable[*InputTypeDef]     `field:"true" doc:"If kind is INPUT, the input-specific type definition. If kind is not INPUT, this will be null."`
+	AsEnum      dagql.Nullable[*EnumTypeDef]      `field:"true" doc:"If kind is ENUM, the enum-specific type definition. If kind is not ENUM, this will be null."`
+	AsScalar    dagql.Nullable[*ScalarTypeDef]    `field:"true" doc:"If kind is SCALAR, the scalar-specific type definition. If kind is not SCALAR, this will be null."`
 }
 
 func (typeDef TypeDef) Clone() *TypeDef {
@@ -297,6 +306,12 @@ func (typeDef TypeDef) Clone() *TypeDef {
 	if typeDef.AsInput.Valid {
 		cp.AsInput.Value = typeDef.AsInput.Value.Clone()
 	}
+	if typeDef.AsEnum.Valid {
+		cp.AsEnum.Value = typeDef.AsEnum.Value.Clone()
+	}
+	if typeDef.AsScalar.Valid {
+		cp.AsScalar.Value = typeDef.AsScalar.Value.Clone()
+	}
 	return &cp
 }
 
@@ -320,6 +335,8 @@ func (typeDef *TypeDef) ToTyped() dagql.Typed {
 		typed = dagql.Int(0)
 	case TypeDefKindBoolean:
 		typed = dagql.Boolean(false)
+	case TypeDefKindFloat:
+		typed = dagql.Float(0)
 	case TypeDefKindList:
 		typed = dagql.DynamicArrayOutput{Elem: typeDef.AsList.Value.ElementTypeDef.ToTyped()}
 	case TypeDefKindObject:
@@ -330,6 +347,10 @@ func (typeDef *TypeDef) ToTyped() dagql.Typed {
 		typed = Void{}
 	case TypeDefKindInput:
 		typed = typeDef.AsInput.Value.ToInputObjectSpec()
+	case TypeDefKindEnum:
+		typed = &ModuleEnum{TypeDef: typeDef.AsEnum.Value}
+	case TypeDefKindScalar:
+		typed = &ModuleScalar{TypeDef: typeDef.AsScalar.Value}
 	default:
 		panic(fmt.Sprintf("unknown type kind: %s", typeDef.Kind))
 	}
@@ -348,6 +369,8 @@ func (typeDef *TypeDef) ToInput() dagql.Input {
 		typed = dagql.Int(0)
 	case TypeDefKindBoolean:
 		typed = dagql.Boolean(false)
+	case TypeDefKindFloat:
+		typed = dagql.Float(0)
 	case TypeDefKindList:
 		typed = dagql.DynamicArrayInput{
 			Elem: typeDef.AsList.Value.ElementTypeDef.ToInput(),
@@ -358,6 +381,10 @@ func (typeDef *TypeDef) ToInput() dagql.Input {
 		typed = DynamicID{typeName: typeDef.AsInterface.Value.Name}
 	case TypeDefKindVoid:
 		typed = Void{}
+	case TypeDefKindEnum:
+		typed = &ModuleEnum{TypeDef: typeDef.AsEnum.Value}
+	case TypeDefKindScalar:
+		typed = &ModuleScalar{TypeDef: typeDef.AsScalar.Value}
 	default:
 		panic(fmt.Sprintf("unknown type kind: %s", typeDef.Kind))
 	}
@@ -406,6 +433,36 @@ func (typeDef *TypeDef) WithInterface(name, desc string) *TypeDef {
 	return typeDef
 }
 
+func (typeDef *TypeDef) WithEnum(name, desc string) *TypeDef {
+	typeDef = typeDef.WithKind(TypeDefKindEnum)
+	typeDef.AsEnum = dagql.NonNull(NewEnumTypeDef(name, desc))
+	return typeDef
+}
+
+func (typeDef *TypeDef) WithEnumValue(name, desc string) (*TypeDef, error) {
+	if !typeDef.AsEnum.Valid {
+		return nil, fmt.Errorf("cannot add value to non-enum type: %s", typeDef.Kind)
+	}
+	if err := validateEnumValueName(name); err != nil {
+		return nil, err
+	}
+	if _, ok := typeDef.AsEnum.Value.ValueByName(name); ok {
+		return nil, fmt.Errorf("enum %q already has a value named %q", typeDef.AsEnum.Value.OriginalName, name)
+	}
+	typeDef = typeDef.Clone()
+	typeDef.AsEnum.Value.Values = append(typeDef.AsEnum.Value.Values, &EnumValueTypeDef{
+		Name:        name,
+		Description: desc,
+	})
+	return typeDef, nil
+}
+
+func (typeDef *TypeDef) WithScalar(name, desc string) *TypeDef {
+	typeDef = typeDef.WithKind(TypeDefKindScalar)
+	typeDef.AsScalar = dagql.NonNull(NewScalarTypeDef(name, desc))
+	return typeDef
+}
+
 func (typeDef *TypeDef) WithOptional(optional bool) *TypeDef {
 	typeDef = typeDef.Clone()
 	typeDef.Optional = optional
@@ -465,7 +522,7 @@ func (typeDef *TypeDef) IsSubtypeOf(otherDef *TypeDef) bool {
 	}
 
 	switch typeDef.Kind {
-	case TypeDefKindString, TypeDefKindInteger, TypeDefKindBoolean, TypeDefKindVoid:
+	case TypeDefKindString, TypeDefKindInteger, TypeDefKindBoolean, TypeDefKindFloat, TypeDefKindVoid:
 		return typeDef.Kind == otherDef.Kind
 	case TypeDefKindList:
 		if otherDef.Kind != TypeDefKindList {
@@ -488,6 +545,17 @@ func (typeDef *TypeDef) IsSubtypeOf(otherDef *TypeDef) bool {
 			return false
 		}
 		return typeDef.AsInterface.Value.IsSubtypeOf(otherDef.AsInterface.Value)
+	case TypeDefKindEnum:
+		if otherDef.Kind != TypeDefKindEnum {
+			return false
+		}
+		// Same as objects, enums with the same name within a schema are the same enum.
+		return typeDef.AsEnum.Value.Name == otherDef.AsEnum.Value.Name
+	case TypeDefKindScalar:
+		if otherDef.Kind != TypeDefKindScalar {
+			return false
+		}
+		return typeDef.AsScalar.Value.Name == otherDef.AsScalar.Value.Name
 	default:
 		return false
 	}
@@ -784,6 +852,137 @@ func (typeDef *InputTypeDef) ToInputObjectSpec() dagql.InputObjectSpec {
 	return spec
 }
 
+type EnumTypeDef struct {
+	// Name is the standardized name of the enum (CamelCase), as used for the enum in the graphql schema
+	Name        string              `field:"true" doc:"The name of the enum."`
+	Description string              `field:"true" doc:"A doc string for the enum, if any."`
+	Values      []*EnumValueTypeDef `field:"true" doc:"The values defined on this enum, if any."`
+
+	// SourceModuleName is currently only set when returning the TypeDef from the Enums field on Module
+	SourceModuleName string `field:"true" doc:"If this EnumTypeDef is associated with a Module, the name of the module. Unset otherwise."`
+
+	// Below are not in public API
+
+	// The original name of the enum as provided by the SDK that defined it, used
+	// when invoking the SDK so it doesn't need to think as hard about case conversions
+	OriginalName string
+}
+
+func NewEnumTypeDef(name, description string) *EnumTypeDef {
+	return &EnumTypeDef{
+		Name:         strcase.ToCamel(name),
+		OriginalName: name,
+		Description:  description,
+	}
+}
+
+func (*EnumTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EnumTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*EnumTypeDef) TypeDescription() string {
+	return "A definition of a custom enum defined in a Module."
+}
+
+func (enum EnumTypeDef) Clone() *EnumTypeDef {
+	cp := enum
+
+	cp.Values = make([]*EnumValueTypeDef, len(enum.Values))
+	for i, val := range enum.Values {
+		cp.Values[i] = val.Clone()
+	}
+
+	return &cp
+}
+
+func (enum *EnumTypeDef) ValueByName(name string) (*EnumValueTypeDef, bool) {
+	for _, val := range enum.Values {
+		if val.Name == name {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+type EnumValueTypeDef struct {
+	Name        string `field:"true" doc:"The name of the enum value."`
+	Description string `field:"true" doc:"A doc string for the enum value, if any."`
+}
+
+func (*EnumValueTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "EnumValueTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*EnumValueTypeDef) TypeDescription() string {
+	return dagql.FormatDescription(
+		`A definition of a value in a custom enum defined in a Module.`,
+		`The name of the value is what is sent over the wire, so it must be a
+		valid GraphQL enum value.`)
+}
+
+func (val EnumValueTypeDef) Clone() *EnumValueTypeDef {
+	cp := val
+	return &cp
+}
+
+type ScalarTypeDef struct {
+	// Name is the standardized name of the scalar (CamelCase), as used for the scalar in the graphql schema
+	Name        string `field:"true" doc:"The name of the scalar."`
+	Description string `field:"true" doc:"A doc string for the scalar, if any."`
+
+	// SourceModuleName is currently only set when returning the TypeDef from the Scalars field on Module
+	SourceModuleName string `field:"true" doc:"If this ScalarTypeDef is associated with a Module, the name of the module. Unset otherwise."`
+
+	// Below are not in public API
+
+	// The original name of the scalar as provided by the SDK that defined it, used
+	// when invoking the SDK so it doesn't need to think as hard about case conversions
+	OriginalName string
+}
+
+func NewScalarTypeDef(name, description string) *ScalarTypeDef {
+	return &ScalarTypeDef{
+		Name:         strcase.ToCamel(name),
+		OriginalName: name,
+		Description:  description,
+	}
+}
+
+func (*ScalarTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ScalarTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*ScalarTypeDef) TypeDescription() string {
+	return "A definition of a custom scalar defined in a Module."
+}
+
+func (scalar ScalarTypeDef) Clone() *ScalarTypeDef {
+	cp := scalar
+	return &cp
+}
+
+var enumValueNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+func validateEnumValueName(name string) error {
+	if !enumValueNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid enum value name %q: must match %s", name, enumValueNameRegex)
+	}
+	switch name {
+	case "true", "false", "null":
+		return fmt.Errorf("invalid enum value name %q: reserved by GraphQL", name)
+	}
+	return nil
+}
+
 type TypeDefKind string
 
 func (k TypeDefKind) String() string {
@@ -799,6 +998,8 @@ var (
 		"An integer value.")
 	TypeDefKindBoolean = TypeDefKinds.Register("BOOLEAN_KIND",
 		"A boolean value.")
+	TypeDefKindFloat = TypeDefKinds.Register("FLOAT_KIND",
+		"A float value.")
 	TypeDefKindList = TypeDefKinds.Register("LIST_KIND",
 		"A list of values all having the same type.",
 		"Always paired with a ListTypeDef.")
@@ -812,6 +1013,12 @@ var (
 	TypeDefKindInput = TypeDefKinds.Register("INPUT_KIND",
 		`A graphql input type, used only when representing the core API via TypeDefs.`,
 	)
+	TypeDefKindEnum = TypeDefKinds.Register("ENUM_KIND",
+		"A GraphQL enum type and its values.",
+		"Always paired with an EnumTypeDef.")
+	TypeDefKindScalar = TypeDefKinds.Register("SCALAR_KIND",
+		"A scalar value of any basic kind.",
+		"Always paired with a ScalarTypeDef.")
 	TypeDefKindVoid = TypeDefKinds.Register("VOID_KIND",
 		"A special kind used to signify that no value is returned.",
 		`This is used for functions that have no return value. The outer TypeDef
@@ -1,10 +1,12 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/dagger/dagger/dagql"
+	"github.com/stretchr/testify/require"
 )
 
 // Samples contains a valid type definition for each kind. If you add a new
@@ -19,6 +21,9 @@ var Samples = map[TypeDefKind]*TypeDef{
 	TypeDefKindBoolean: {
 		Kind: TypeDefKindBoolean,
 	},
+	TypeDefKindFloat: {
+		Kind: TypeDefKindFloat,
+	},
 	TypeDefKindList: {
 		Kind: TypeDefKindList,
 		AsList: dagql.NonNull(&ListTypeDef{
@@ -39,6 +44,21 @@ var Samples = map[TypeDefKind]*TypeDef{
 			Name: "FooInterface",
 		}),
 	},
+	TypeDefKindEnum: {
+		Kind: TypeDefKindEnum,
+		AsEnum: dagql.NonNull(&EnumTypeDef{
+			Name: "FooEnum",
+			Values: []*EnumValueTypeDef{
+				{Name: "BAR"},
+			},
+		}),
+	},
+	TypeDefKindScalar: {
+		Kind: TypeDefKindScalar,
+		AsScalar: dagql.NonNull(&ScalarTypeDef{
+			Name: "FooScalar",
+		}),
+	},
 	TypeDefKindVoid: {
 		Kind: TypeDefKindVoid,
 	},
@@ -67,3 +87,107 @@ func TestTypeDefConversions(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeDefWithEnumValue(t *testing.T) {
+	def := (&TypeDef{}).WithEnum("buildMode", "")
+	require.Equal(t, "BuildMode", def.AsEnum.Value.Name)
+	require.Equal(t, "buildMode", def.AsEnum.Value.OriginalName)
+
+	def, err := def.WithEnumValue("DEBUG", "")
+	require.NoError(t, err)
+	def, err = def.WithEnumValue("RELEASE", "")
+	require.NoError(t, err)
+	require.Len(t, def.AsEnum.Value.Values, 2)
+
+	_, err = def.WithEnumValue("RELEASE", "")
+	require.ErrorContains(t, err, "already has a value")
+
+	_, err = def.WithEnumValue("not-valid", "")
+	require.ErrorContains(t, err, "invalid enum value name")
+
+	_, err = def.WithEnumValue("null", "")
+	require.ErrorContains(t, err, "reserved")
+
+	_, err = (&TypeDef{Kind: TypeDefKindString}).WithEnumValue("DEBUG", "")
+	require.Error(t, err)
+
+	input, err := def.ToInput().Decoder().DecodeInput("DEBUG")
+	require.NoError(t, err)
+	require.Equal(t, "DEBUG", input.(*ModuleEnum).Value)
+
+	_, err = def.ToInput().Decoder().DecodeInput("PROFILE")
+	require.Error(t, err)
+}
+
+func TestFunctionFieldSpecEnumRef(t *testing.T) {
+	def := (&TypeDef{}).WithEnum("buildMode", "")
+	def, err := def.WithEnumValue("DEBUG", "")
+	require.NoError(t, err)
+	mod := &Module{
+		Deps:     NewModDeps(nil, nil),
+		EnumDefs: []*TypeDef{def},
+	}
+
+	// args only refer to the enum, without its values
+	ref := (&TypeDef{}).WithEnum("buildMode", "")
+	fn := NewFunction("build", &TypeDef{Kind: TypeDefKindString}).
+		WithArg("mode", ref, "", nil).
+		WithArg("modes", (&TypeDef{}).WithListOf(ref), "", nil)
+
+	spec, err := fn.FieldSpec(context.Background(), mod)
+	require.NoError(t, err)
+
+	dec := spec.Args[0].Type.Decoder()
+	_, err = dec.DecodeInput("DEBUG")
+	require.NoError(t, err)
+	_, err = dec.DecodeInput("PROFILE")
+	require.ErrorContains(t, err, "invalid value")
+
+	_, err = spec.Args[1].Type.Decoder().DecodeInput([]any{"PROFILE"})
+	require.ErrorContains(t, err, "invalid value")
+
+	_, err = fn.WithArg("other", (&TypeDef{}).WithEnum("other", ""), "", nil).
+		FieldSpec(context.Background(), mod)
+	require.ErrorContains(t, err, "not defined")
+}
+
+func TestModuleScalarDecodeInput(t *testing.T) {
+	typeDef := (&TypeDef{}).WithScalar("semver", "A semantic version.")
+	require.Equal(t, TypeDefKindScalar, typeDef.Kind)
+	require.Equal(t, "Semver", typeDef.AsScalar.Value.Name)
+	require.Equal(t, "semver", typeDef.AsScalar.Value.OriginalName)
+
+	dec := typeDef.ToInput().Decoder()
+
+	val, err := dec.DecodeInput("v1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.3", val.(*ModuleScalar).Value)
+	require.Equal(t, "v1.2.3", val.ToLiteral().ToInput())
+
+	val, err = dec.DecodeInput(map[string]any{"b": int64(1), "a": "x"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"a": "x", "b": int64(1)}, val.ToLiteral().ToInput())
+
+	_, err = dec.DecodeInput(struct{}{})
+	require.Error(t, err)
+}
+
+func TestFunctionFieldSpecDefaultValues(t *testing.T) {
+	fn := NewFunction("greet", &TypeDef{Kind: TypeDefKindString}).
+		WithArg("name", &TypeDef{Kind: TypeDefKindString}, "", JSON(`"world"`)).
+		WithArg("count", &TypeDef{Kind: TypeDefKindInteger}, "", JSON(`3`)).
+		WithArg("mode", Samples[TypeDefKindEnum], "", JSON(`"BAR"`)).
+		WithArg("suffix", &TypeDef{Kind: TypeDefKindString}, "", nil)
+
+	spec, err := fn.FieldSpec(context.Background(), &Module{})
+	require.NoError(t, err)
+	require.Len(t, spec.Args, 4)
+
+	require.Equal(t, `"world"`, spec.Args[0].Default.ToLiteral().ToAST().String())
+	require.Equal(t, `3`, spec.Args[1].Default.ToLiteral().ToAST().String())
+	require.Equal(t, `BAR`, spec.Args[2].Default.ToLiteral().ToAST().String())
+	require.Nil(t, spec.Args[3].Default)
+
+	_, err = fn.WithArg("bad", &TypeDef{Kind: TypeDefKindInteger}, "", JSON(`"nope"`)).FieldSpec(context.Background(), &Module{})
+	require.Error(t, err)
+}
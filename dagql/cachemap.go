@@ -1,22 +1,79 @@
 package dagql
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/opencontainers/go-digest"
 )
 
 type cacheMap[K comparable, T any] struct {
 	l     sync.Mutex
-	calls map[K]*cache[T]
+	calls map[K]*cache[K, T]
+
+	// lru orders the calls that may be evicted from most to least recently
+	// used. Pinned calls and calls still in flight are left out, so that
+	// eviction never has to walk past them.
+	lru *list.List
+
+	opts CacheOpts
+
+	// now returns the current time, for expiring calls.
+	now func() time.Time
+
+	hits, misses, evictions int64
 }
 
-type cache[T any] struct {
+type cache[K comparable, T any] struct {
 	wg  sync.WaitGroup
 	val T
 	err error
+
+	// guarded by the cacheMap's lock
+	key      K
+	done     bool
+	pinned   bool
+	lastUsed time.Time
+	elem     *list.Element
+}
+
+// CacheOpts configures the eviction policies of a cache created with
+// NewCacheWithOpts.
+//
+// Results of impure selections are never cached in the first place, and
+// results of volatile selections are kept for the lifetime of the cache, i.e.
+// the session, so that they resolve consistently within it.
+type CacheOpts struct {
+	// MaxEntries is the maximum number of results to keep. Once exceeded, the
+	// least recently used results are evicted. Zero means no limit.
+	MaxEntries int
+
+	// TTL evicts results that haven't been used for longer than this. Zero
+	// means no limit.
+	TTL time.Duration
+}
+
+// CacheStats reports the usage of a cache.
+type CacheStats struct {
+	// Entries is the number of results currently held.
+	Entries int
+
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// HitRate returns the ratio of lookups that found a result, or 0 if there
+// haven't been any.
+func (stats CacheStats) HitRate() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
 }
 
 // NewCache creates a new cache map suitable for assigning on a Server or
@@ -25,12 +82,37 @@ func NewCache() Cache {
 	return newCacheMap[digest.Digest, Typed]()
 }
 
+// NewCacheWithOpts creates a new cache map like NewCache, which evicts
+// results according to the given policies.
+//
+// Evicted results are simply evaluated again the next time they're selected.
+func NewCacheWithOpts(opts CacheOpts) Cache {
+	m := newCacheMap[digest.Digest, Typed]()
+	m.opts = opts
+	return m
+}
+
 func newCacheMap[K comparable, T any]() *cacheMap[K, T] {
 	return &cacheMap[K, T]{
-		calls: map[K]*cache[T]{},
+		calls: map[K]*cache[K, T]{},
+		lru:   list.New(),
+		now:   time.Now,
 	}
 }
 
+type cachePinnedContextKey struct{}
+
+// pinCache marks the result of a call to GetOrInitialize with the returned
+// context as exempt from eviction.
+func pinCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cachePinnedContextKey{}, true)
+}
+
+func cachePinned(ctx context.Context) bool {
+	pinned, _ := ctx.Value(cachePinnedContextKey{}).(bool)
+	return pinned
+}
+
 type cacheMapContextKey[K comparable, T any] struct {
 	key K
 	m   *cacheMap[K, T]
@@ -40,9 +122,14 @@ var ErrCacheMapRecursiveCall = fmt.Errorf("recursive call detected")
 
 func (m *cacheMap[K, T]) Set(key K, val T) {
 	m.l.Lock()
-	m.calls[key] = &cache[T]{
-		val: val,
+	if c, ok := m.calls[key]; ok {
+		m.remove(c)
 	}
+	m.insert(&cache[K, T]{
+		key:  key,
+		val:  val,
+		done: true,
+	})
 	m.l.Unlock()
 }
 
@@ -57,7 +144,8 @@ func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn fun
 	}
 
 	m.l.Lock()
-	if c, ok := m.calls[key]; ok {
+	if c, ok := m.lookup(key); ok {
+		m.hits++
 		m.l.Unlock()
 		c.wg.Wait()
 		if onHit != nil {
@@ -66,20 +154,30 @@ func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn fun
 		return c.val, c.err
 	}
 
-	c := &cache[T]{}
+	m.misses++
+	c := &cache[K, T]{key: key, pinned: cachePinned(ctx)}
 	c.wg.Add(1)
-	m.calls[key] = c
+	m.insert(c)
 	m.l.Unlock()
 
 	ctx = context.WithValue(ctx, cacheMapContextKey[K, T]{key: key, m: m}, struct{}{})
+	if c.pinned {
+		// calls made while initializing aren't pinned themselves
+		ctx = context.WithValue(ctx, cachePinnedContextKey{}, false)
+	}
 	c.val, c.err = fn(ctx)
-	c.wg.Done()
 
+	m.l.Lock()
+	c.done = true
 	if c.err != nil {
-		m.l.Lock()
-		delete(m.calls, key)
-		m.l.Unlock()
+		m.remove(c)
+	} else if !c.pinned && m.calls[c.key] == c {
+		c.lastUsed = m.now()
+		c.elem = m.lru.PushFront(c)
 	}
+	m.l.Unlock()
+
+	c.wg.Done()
 
 	return c.val, c.err
 }
@@ -91,13 +189,85 @@ func (m *cacheMap[K, T]) Get(ctx context.Context, key K) (T, error) {
 	}
 
 	m.l.Lock()
-	if c, ok := m.calls[key]; ok {
+	if c, ok := m.lookup(key); ok {
+		m.hits++
 		m.l.Unlock()
 		c.wg.Wait()
 		return c.val, c.err
 	}
+	m.misses++
 	m.l.Unlock()
 
 	var zero T
 	return zero, fmt.Errorf("key not found")
 }
+
+// Stats reports the usage of the cache.
+func (m *cacheMap[K, T]) Stats() CacheStats {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return CacheStats{
+		Entries:   len(m.calls),
+		Hits:      m.hits,
+		Misses:    m.misses,
+		Evictions: m.evictions,
+	}
+}
+
+// lookup returns the call for key, marking it as recently used, unless it has
+// expired. Must be called with the lock held.
+func (m *cacheMap[K, T]) lookup(key K) (*cache[K, T], bool) {
+	c, ok := m.calls[key]
+	if !ok {
+		return nil, false
+	}
+	now := m.now()
+	if m.expired(c, now) {
+		m.remove(c)
+		m.evictions++
+		return nil, false
+	}
+	c.lastUsed = now
+	if c.elem != nil {
+		m.lru.MoveToFront(c.elem)
+	}
+	return c, true
+}
+
+// insert adds a call, as the most recently used one if it's done, and then
+// evicts calls exceeding the configured limits. Calls still in flight join
+// the LRU list once they're done. Must be called with the lock held.
+func (m *cacheMap[K, T]) insert(c *cache[K, T]) {
+	now := m.now()
+	c.lastUsed = now
+	if c.done && !c.pinned {
+		c.elem = m.lru.PushFront(c)
+	}
+	m.calls[c.key] = c
+
+	for e := m.lru.Back(); e != nil; e = m.lru.Back() {
+		old := e.Value.(*cache[K, T])
+		tooMany := m.opts.MaxEntries > 0 && len(m.calls) > m.opts.MaxEntries
+		if !tooMany && !m.expired(old, now) {
+			// everything more recent is within the limits too
+			break
+		}
+		m.remove(old)
+		m.evictions++
+	}
+}
+
+// remove forgets a call. Must be called with the lock held.
+func (m *cacheMap[K, T]) remove(c *cache[K, T]) {
+	if m.calls[c.key] == c {
+		delete(m.calls, c.key)
+	}
+	if c.elem != nil {
+		m.lru.Remove(c.elem)
+		c.elem = nil
+	}
+}
+
+func (m *cacheMap[K, T]) expired(c *cache[K, T], now time.Time) bool {
+	return m.opts.TTL > 0 && c.done && !c.pinned && now.Sub(c.lastUsed) > m.opts.TTL
+}
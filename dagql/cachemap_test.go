@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
@@ -103,3 +104,135 @@ func TestCacheMapRecursiveCall(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, 101, v)
 }
+
+func TestCacheMapMaxEntries(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.opts.MaxEntries = 2
+	ctx := context.Background()
+
+	calls := 0
+	get := func(key int) int {
+		val, err := c.GetOrInitialize(ctx, key, func(_ context.Context) (int, error) {
+			calls++
+			return key * 10, nil
+		})
+		assert.NilError(t, err)
+		return val
+	}
+
+	assert.Equal(t, 10, get(1))
+	assert.Equal(t, 20, get(2))
+	assert.Equal(t, 10, get(1)) // 1 is now more recently used than 2
+	assert.Equal(t, 30, get(3)) // evicts 2
+	assert.Equal(t, 3, calls)
+
+	assert.Equal(t, 10, get(1))
+	assert.Equal(t, 30, get(3))
+	assert.Equal(t, 3, calls)
+
+	assert.Equal(t, 20, get(2))
+	assert.Equal(t, 4, calls)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(4), stats.Misses)
+	assert.Equal(t, int64(3), stats.Hits)
+	assert.Equal(t, int64(2), stats.Evictions)
+	assert.Equal(t, 3.0/7.0, stats.HitRate())
+}
+
+func TestCacheMapTTL(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.opts.TTL = time.Minute
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	calls := 0
+	get := func(key int) int {
+		val, err := c.GetOrInitialize(ctx, key, func(_ context.Context) (int, error) {
+			calls++
+			return calls, nil
+		})
+		assert.NilError(t, err)
+		return val
+	}
+
+	assert.Equal(t, 1, get(1))
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, 1, get(1))
+
+	// using it reset its age
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, 1, get(1))
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 2, get(1))
+
+	// expired results are also evicted when adding others
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 3, get(2))
+	assert.Equal(t, 1, c.Stats().Entries)
+}
+
+func TestCacheMapPinned(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.opts.MaxEntries = 1
+	c.opts.TTL = time.Minute
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	calls := 0
+	get := func(ctx context.Context, key int) int {
+		val, err := c.GetOrInitialize(ctx, key, func(ctx context.Context) (int, error) {
+			calls++
+			if key == 1 {
+				// calls made while initializing a pinned one aren't pinned
+				assert.Assert(t, !cachePinned(ctx))
+			}
+			return calls, nil
+		})
+		assert.NilError(t, err)
+		return val
+	}
+
+	assert.Equal(t, 1, get(pinCache(ctx), 1))
+	assert.Equal(t, 2, get(ctx, 2))
+	assert.Equal(t, 3, get(ctx, 3)) // evicts 2, but not 1
+	now = now.Add(time.Hour)
+	assert.Equal(t, 1, get(ctx, 1))
+	assert.Equal(t, 4, get(ctx, 2))
+	assert.Equal(t, int64(2), c.Stats().Evictions)
+
+	// pinned results are kept out of the LRU list entirely
+	assert.Equal(t, 2, c.Stats().Entries)
+	assert.Equal(t, 1, c.lru.Len())
+}
+
+func TestCacheMapInFlightNotEvicted(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.opts.MaxEntries = 1
+	ctx := context.Background()
+
+	val, err := c.GetOrInitialize(ctx, 1, func(ctx context.Context) (int, error) {
+		// inserting another call must not evict the one still running
+		_, err := c.GetOrInitialize(ctx, 2, func(context.Context) (int, error) {
+			return 2, nil
+		})
+		assert.NilError(t, err)
+		return 1, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, val)
+
+	val, err = c.GetOrInitialize(ctx, 1, func(context.Context) (int, error) {
+		return 0, errors.New("should be cached")
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, val)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/dagger/dagger/dagql/internal/pipes"
 	"github.com/dagger/dagger/dagql/internal/points"
 	"github.com/dagger/dagger/dagql/introspection"
+	"github.com/opencontainers/go-digest"
 	"github.com/vektah/gqlparser/v2/ast"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
@@ -529,6 +530,160 @@ func TestLoadingFromID(t *testing.T) {
 	}
 }
 
+func TestLoadingFromRef(t *testing.T) {
+	newServer := func(maxIDs int) (*dagql.Server, *client.Client) {
+		srv := dagql.NewServer(Query{})
+		srv.IDs = idproto.NewStore(maxIDs)
+		points.Install[Query](srv)
+		return srv, client.New(handler.NewDefaultServer(srv))
+	}
+	srv, gql := newServer(0)
+	defer srv.IDs.Close()
+
+	var res struct {
+		Point struct {
+			ShiftLeft struct {
+				ID        string
+				Neighbors []struct {
+					ID string
+				}
+			}
+		}
+	}
+	raw, err := gql.RawPost(`query {
+		point(x: 6, y: 7) {
+			shiftLeft {
+				id
+				neighbors {
+					id
+				}
+			}
+		}
+	}`)
+	assert.NilError(t, err)
+	dataJSON, err := json.Marshal(raw.Data)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(dataJSON, &res))
+
+	var ext struct {
+		IDRefs map[string]string
+	}
+	extJSON, err := json.Marshal(raw.Extensions)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(extJSON, &ext))
+
+	ids := map[string]string{
+		"point.shiftLeft.id": res.Point.ShiftLeft.ID,
+	}
+	for i, neighbor := range res.Point.ShiftLeft.Neighbors {
+		ids[fmt.Sprintf("point.shiftLeft.neighbors.%d.id", i)] = neighbor.ID
+	}
+	assert.Equal(t, len(ids), len(ext.IDRefs))
+	for path, id := range ids {
+		ref := ext.IDRefs[path]
+		assert.Assert(t, idproto.IsRef(ref), "no reference for %s", path)
+		assert.Assert(t, len(ref) < len(id))
+
+		var res struct {
+			LoadPointFromID struct {
+				ID string
+			}
+		}
+		req(t, gql, `query {
+			loadPointFromID(id: "`+ref+`") {
+				id
+			}
+		}`, &res)
+		assert.Equal(t, id, res.LoadPointFromID.ID)
+
+		res.LoadPointFromID.ID = ""
+		err := gql.Post(`query($to: PointID!) {
+			loadPointFromID(id: $to) {
+				id
+			}
+		}`, &res, client.Var("to", ref))
+		assert.NilError(t, err)
+		assert.Equal(t, id, res.LoadPointFromID.ID)
+	}
+
+	t.Run("references are decoded by the argument's ID type", func(t *testing.T) {
+		srv, gql := newServer(0)
+		defer srv.IDs.Close()
+		srv.InstallScalar(anyID{})
+		dagql.Fields[Query]{
+			dagql.Func("describe", func(ctx context.Context, self Query, args struct {
+				Thing anyID
+			}) (dagql.String, error) {
+				return dagql.NewString(args.Thing.id.Type.NamedType), nil
+			}),
+		}.Install(srv)
+
+		raw, err := gql.RawPost(`query { point(x: 1, y: 2) { id } }`)
+		assert.NilError(t, err)
+		ref := raw.Extensions["idRefs"].(map[string]any)["point.id"].(string)
+
+		// like an interface's ID, which accepts the IDs of its implementations
+		var res struct {
+			Describe string
+		}
+		req(t, gql, `query { describe(thing: "`+ref+`") }`, &res)
+		assert.Equal(t, "Point", res.Describe)
+
+		// concrete ID types still reject the IDs of other types
+		raw, err = gql.RawPost(`query { point(x: 1, y: 2) { line(to: "` + ref + `") { id } } }`)
+		assert.NilError(t, err)
+		lineRef := raw.Extensions["idRefs"].(map[string]any)["point.line.id"].(string)
+		err = gql.Post(`query { point(x: 0, y: 0) { line(to: "`+lineRef+`") { length } } }`, &struct{}{})
+		assert.ErrorContains(t, err, "got Line! ID")
+	})
+
+	t.Run("unknown references are rejected", func(t *testing.T) {
+		ref, err := idproto.DigestRef(digest.FromString("bogus"))
+		assert.NilError(t, err)
+		err = gql.Post(`query {
+			loadPointFromID(id: "`+ref+`") {
+				id
+			}
+		}`, &struct{}{})
+		assert.ErrorContains(t, err, "unknown ID reference")
+	})
+
+	t.Run("references are only resolved by the server that returned them", func(t *testing.T) {
+		other, otherGQL := newServer(0)
+		defer other.IDs.Close()
+		err := otherGQL.Post(`query {
+			loadPointFromID(id: "`+ext.IDRefs["point.shiftLeft.id"]+`") {
+				id
+			}
+		}`, &struct{}{})
+		assert.ErrorContains(t, err, "unknown ID reference")
+
+		var id idproto.ID
+		assert.ErrorContains(t, id.Decode(ext.IDRefs["point.shiftLeft.id"]), "outside of the session")
+	})
+
+	t.Run("the least recently used IDs are forgotten", func(t *testing.T) {
+		small, smallGQL := newServer(2)
+		defer small.IDs.Close()
+		refs := []string{}
+		for x := 0; x < 3; x++ {
+			raw, err := smallGQL.RawPost(fmt.Sprintf(`query { point(x: %d, y: 0) { id } }`, x))
+			assert.NilError(t, err)
+			refs = append(refs, raw.Extensions["idRefs"].(map[string]any)["point.id"].(string))
+		}
+		assert.Equal(t, 2, small.IDs.Len())
+		err := smallGQL.Post(`query { loadPointFromID(id: "`+refs[0]+`") { x } }`, &struct{}{})
+		assert.ErrorContains(t, err, "unknown ID reference")
+		var res struct {
+			LoadPointFromID struct {
+				X int
+			}
+		}
+		req(t, smallGQL, `query { loadPointFromID(id: "`+refs[2]+`") { x } }`, &res)
+		assert.Equal(t, 2, res.LoadPointFromID.X)
+	})
+}
+
 func TestIDsReflectQuery(t *testing.T) {
 	srv := dagql.NewServer(Query{})
 	points.Install[Query](srv)
@@ -1393,6 +1548,240 @@ func TestParallelism(t *testing.T) {
 	})
 }
 
+type Subscription struct{}
+
+func (Subscription) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Subscription",
+		NonNull:   true,
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	srv := dagql.NewServer(Query{})
+	points.Install[Query](srv)
+	dagql.Subscribe(srv, Subscription{})
+
+	gql := client.New(handler.NewDefaultServer(srv))
+
+	dagql.Fields[Subscription]{
+		dagql.Func("walk", func(ctx context.Context, self Subscription, args struct {
+			From  dagql.ID[*points.Point]
+			Steps int
+		}) (dagql.Stream[*points.Point], error) {
+			from, err := args.From.Load(ctx, srv)
+			if err != nil {
+				return dagql.Stream[*points.Point]{}, err
+			}
+			ch := make(chan *points.Point, args.Steps)
+			go func() {
+				defer close(ch)
+				for i := 1; i <= args.Steps; i++ {
+					ch <- &points.Point{X: from.Self.X + i, Y: from.Self.Y}
+				}
+			}()
+			return dagql.ChanStream(ch), nil
+		}).Impure("Streams new points."),
+		dagql.Func("countdown", func(ctx context.Context, self Subscription, args struct {
+			From int
+		}) (dagql.Stream[dagql.Int], error) {
+			n := args.From
+			return dagql.NewStream(func(context.Context) (dagql.Int, error) {
+				if n == 0 {
+					return 0, io.EOF
+				}
+				n--
+				return dagql.NewInt(n + 1), nil
+			}), nil
+		}).Impure("Streams a countdown."),
+	}.Install(srv)
+
+	t.Run("streams each value", func(t *testing.T) {
+		sub := gql.Websocket(`subscription {
+			countdown(from: 3)
+		}`)
+		defer sub.Close()
+
+		for _, expected := range []int{3, 2, 1} {
+			var res struct {
+				Countdown int
+			}
+			assert.NilError(t, sub.Next(&res))
+			assert.Equal(t, expected, res.Countdown)
+		}
+
+		var res any
+		assert.ErrorContains(t, sub.Next(&res), "complete")
+	})
+
+	t.Run("cannot sub-select streams", func(t *testing.T) {
+		var pointRes struct {
+			Point struct {
+				ID string
+			}
+		}
+		req(t, gql, `query { point(x: 6, y: 7) { id } }`, &pointRes)
+
+		sub := gql.Websocket(`subscription($from: PointID!) {
+			walk(from: $from, steps: 2) {
+				x
+			}
+		}`, client.Var("from", pointRes.Point.ID))
+		defer sub.Close()
+
+		var res any
+		assert.ErrorContains(t, sub.Next(&res), "cannot sub-select streams")
+	})
+
+	t.Run("streams are only selectable in subscriptions", func(t *testing.T) {
+		var res any
+		err := gql.Post(`query {
+			countdown(from: 3)
+		}`, &res)
+		assert.ErrorContains(t, err, "countdown")
+	})
+
+	t.Run("streams are only returned by the subscription root", func(t *testing.T) {
+		defer func() {
+			err, _ := recover().(error)
+			assert.ErrorContains(t, err, "only fields of the subscription root may return a stream")
+		}()
+		dagql.Fields[*points.Point]{
+			dagql.Func("countdown", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Stream[dagql.Int], error) {
+				return dagql.Stream[dagql.Int]{}, nil
+			}).Impure("Streams a countdown."),
+		}.Install(srv)
+	})
+}
+
+func TestLimits(t *testing.T) {
+	srv := dagql.NewServer(Query{})
+	points.Install[Query](srv)
+
+	gql := client.New(handler.NewDefaultServer(srv))
+
+	t.Run("depth", func(t *testing.T) {
+		srv.Limits = dagql.Limits{MaxDepth: 3}
+		defer func() { srv.Limits = dagql.Limits{} }()
+
+		var res any
+		req(t, gql, `query {
+			point(x: 6, y: 7) {
+				shiftLeft {
+					x
+				}
+			}
+		}`, &res)
+
+		err := gql.Post(`query {
+			point(x: 6, y: 7) {
+				x
+				shiftLeft {
+					shiftLeft {
+						x
+					}
+				}
+			}
+		}`, &res)
+		assert.ErrorContains(t, err, "query depth of 4 exceeds the maximum of 3: point.shiftLeft.shiftLeft.x")
+	})
+
+	t.Run("cost", func(t *testing.T) {
+		srv.Limits = dagql.Limits{MaxCost: 20}
+		defer func() { srv.Limits = dagql.Limits{} }()
+
+		var res any
+		req(t, gql, `query {
+			point(x: 6, y: 7) {
+				neighbors {
+					x
+				}
+			}
+		}`, &res)
+
+		// point: 1 + (x: 1) + (neighbors: 1 + 4 * (neighbors: 1 + 4 * (x: 1)))
+		err := gql.Post(`query {
+			point(x: 6, y: 7) {
+				x
+				neighbors {
+					neighbors {
+						x
+					}
+				}
+			}
+		}`, &res)
+		assert.ErrorContains(t, err, "query cost of 23 exceeds the maximum of 20: point.neighbors.neighbors.x")
+	})
+
+	t.Run("fan-out", func(t *testing.T) {
+		srv.Limits = dagql.Limits{MaxFanOut: 3}
+		defer func() { srv.Limits = dagql.Limits{} }()
+
+		var res any
+		err := gql.Post(`query {
+			point(x: 6, y: 7) {
+				neighbors {
+					x
+				}
+			}
+		}`, &res)
+		assert.ErrorContains(t, err, "point: neighbors: query fan-out of 4 exceeds the maximum of 3: point.neighbors")
+	})
+
+	t.Run("depth of ID arguments", func(t *testing.T) {
+		var ids struct {
+			Shallow struct {
+				ID string
+			}
+			Deep struct {
+				ShiftLeft struct {
+					ShiftLeft struct {
+						ID string
+					}
+				}
+			}
+		}
+		req(t, gql, `query {
+			shallow: point(x: 1, y: 1) {
+				id
+			}
+			deep: point(x: 6, y: 7) {
+				shiftLeft {
+					shiftLeft {
+						id
+					}
+				}
+			}
+		}`, &ids)
+
+		srv.Limits = dagql.Limits{MaxDepth: 4}
+		defer func() { srv.Limits = dagql.Limits{} }()
+
+		query := `query($to: PointID!) {
+			point(x: 0, y: 0) {
+				line(to: $to) {
+					length
+				}
+			}
+		}`
+
+		var res any
+		err := gql.Post(query, &res, client.Var("to", ids.Shallow.ID))
+		assert.NilError(t, err)
+
+		// point: 1 + (line: 1 + (to: point.shiftLeft.shiftLeft))
+		err = gql.Post(query, &res, client.Var("to", ids.Deep.ShiftLeft.ShiftLeft.ID))
+		assert.ErrorContains(t, err, "query depth of 5 exceeds the maximum of 4: point.line(to)")
+	})
+}
+
+func TestLimitsNarrow(t *testing.T) {
+	engine := dagql.Limits{MaxDepth: 10, MaxCost: 100}
+	session := dagql.Limits{MaxDepth: 20, MaxCost: 50, MaxFanOut: 5}
+	assert.DeepEqual(t, dagql.Limits{MaxDepth: 10, MaxCost: 50, MaxFanOut: 5}, engine.Narrow(session))
+	assert.DeepEqual(t, engine, engine.Narrow(dagql.Limits{}))
+}
+
 type Builtins struct {
 	Boolean     bool    `field:"true" default:"true"`
 	Int         int     `field:"true" default:"42"`
@@ -1634,3 +2023,58 @@ func debugID(t *testing.T, msgf string, idStr string, args ...any) {
 	assert.NilError(t, err)
 	t.Logf(msgf, append([]any{id.Display()}, args...)...)
 }
+
+// anyID is an ID scalar that accepts the ID of any object, like the ID of an
+// interface accepts the IDs of the objects implementing it.
+type anyID struct {
+	id *idproto.ID
+}
+
+var _ dagql.IDType = anyID{}
+
+func (anyID) Type() *ast.Type {
+	return &ast.Type{NamedType: "AnyID", NonNull: true}
+}
+
+func (anyID) TypeName() string {
+	return "AnyID"
+}
+
+func (anyID) TypeDefinition() *ast.Definition {
+	return &ast.Definition{Kind: ast.Scalar, Name: "AnyID"}
+}
+
+func (anyID) Decoder() dagql.InputDecoder {
+	return anyID{}
+}
+
+func (anyID) DecodeInput(val any) (dagql.Input, error) {
+	switch x := val.(type) {
+	case *idproto.ID:
+		return anyID{id: x}, nil
+	case string:
+		var id idproto.ID
+		if err := id.Decode(x); err != nil {
+			return nil, err
+		}
+		return anyID{id: &id}, nil
+	default:
+		return nil, fmt.Errorf("cannot create AnyID from %T", val)
+	}
+}
+
+func (i anyID) ID() *idproto.ID {
+	return i.id
+}
+
+func (i anyID) ToLiteral() *idproto.Literal {
+	return &idproto.Literal{Value: &idproto.Literal_Id{Id: i.id}}
+}
+
+func (i anyID) MarshalJSON() ([]byte, error) {
+	enc, err := i.id.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(enc)
+}
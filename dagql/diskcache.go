@@ -0,0 +1,443 @@
+package dagql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/opencontainers/go-digest"
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxDiskCacheEntrySize is the size past which a result isn't stored, however
+// large the cache may grow.
+const maxDiskCacheEntrySize = 1 << 20
+
+var (
+	diskCacheBucket     = []byte("results")
+	diskCacheMetaBucket = []byte("meta")
+	diskCacheVersionKey = []byte("version")
+)
+
+// DiskCacheOpts configures the limits of a DiskCache.
+type DiskCacheOpts struct {
+	// MaxSize is the maximum total size of the stored results, in bytes. Once
+	// exceeded, the least recently used results are evicted. Zero means no
+	// limit.
+	MaxSize int64
+
+	// MaxAge evicts results that haven't been used for longer than this. Zero
+	// means no limit.
+	//
+	// The last use of a result is only recorded once it's older than a tenth
+	// of MaxAge, so results may be evicted up to that much early.
+	MaxAge time.Duration
+
+	// Version identifies the implementation of the API, e.g. the engine
+	// version. Results stored by a different version are discarded, since
+	// the same ID may resolve differently.
+	Version string
+}
+
+// DiskCache persists the results of pure selections to disk, keyed by the
+// digest of their ID, so that they survive restarts.
+//
+// Only scalar results are stored, since objects hold references to state
+// that doesn't outlive the process. Selections with tainted IDs never reach
+// the cache in the first place, and results derived from a call to a
+// volatile field, such as one resolving a remote ref, are never stored.
+type DiskCache struct {
+	db   *bolt.DB
+	opts DiskCacheOpts
+
+	// size is the total size of the stored entries.
+	size  int64
+	sizeL sync.Mutex
+}
+
+// diskCacheEntry is the stored form of a result.
+type diskCacheEntry struct {
+	// Type is the name of the result's scalar type.
+	Type string `json:"type"`
+
+	// Value is the JSON encoding of the result.
+	Value json.RawMessage `json:"value"`
+
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// lastUsedResolution is how old the recorded last use of a result may get
+// before a hit records it again, so that hits don't each need a write.
+func (c *DiskCache) lastUsedResolution() time.Duration {
+	if c.opts.MaxAge > 0 {
+		return c.opts.MaxAge / 10
+	}
+	return time.Hour
+}
+
+// OpenDiskCache opens or creates a DiskCache stored in the file at path,
+// evicting any results that exceed the configured limits.
+func OpenDiskCache(path string, opts DiskCacheOpts) (*DiskCache, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	c := &DiskCache{
+		db:   db,
+		opts: opts,
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(diskCacheMetaBucket)
+		if err != nil {
+			return err
+		}
+		if string(meta.Get(diskCacheVersionKey)) != opts.Version {
+			if err := tx.DeleteBucket(diskCacheBucket); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+				return err
+			}
+			if err := meta.Put(diskCacheVersionKey, []byte(opts.Version)); err != nil {
+				return err
+			}
+		}
+		b, err := tx.CreateBucketIfNotExists(diskCacheBucket)
+		if err != nil {
+			return err
+		}
+		return b.ForEach(func(k, v []byte) error {
+			c.size += int64(len(k) + len(v))
+			return nil
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := c.evict(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the underlying database.
+func (c *DiskCache) Close() error {
+	return c.db.Close()
+}
+
+// Size returns the total size of the stored results, in bytes.
+func (c *DiskCache) Size() int64 {
+	c.sizeL.Lock()
+	defer c.sizeL.Unlock()
+	return c.size
+}
+
+// Wrap returns a Cache which consults the disk before calling into inner's
+// initializers, and stores their scalar results.
+//
+// The inner cache still holds every result, and deduplicates concurrent
+// calls, so each DiskCache is typically wrapped once per Server.
+func (c *DiskCache) Wrap(inner Cache) Cache {
+	return diskCacheLayer{
+		inner: inner,
+		disk:  c,
+	}
+}
+
+type diskCacheLayer struct {
+	inner Cache
+	disk  *DiskCache
+}
+
+func (l diskCacheLayer) GetOrInitialize(ctx context.Context, key digest.Digest, fn func(context.Context) (Typed, error)) (Typed, error) {
+	return l.inner.GetOrInitialize(ctx, key, func(ctx context.Context) (Typed, error) {
+		srv := currentServer(ctx)
+		if srv == nil {
+			return fn(ctx)
+		}
+		if val, ok := l.disk.load(srv, key); ok {
+			return val, nil
+		}
+		val, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if id := CurrentID(ctx); id == nil || !stable(srv, id) {
+			return val, nil
+		}
+		if err := l.disk.store(srv, key, val); err != nil {
+			slog.Warn("failed to persist result", "key", key, "error", err)
+		}
+		return val, nil
+	})
+}
+
+func (l diskCacheLayer) Stats() CacheStats {
+	if stats, ok := l.inner.(CacheStatsReporter); ok {
+		return stats.Stats()
+	}
+	return CacheStats{}
+}
+
+// load returns the stored result for key, decoding it with the server's
+// scalar types.
+func (c *DiskCache) load(srv *Server, key digest.Digest) (Typed, bool) {
+	var entry diskCacheEntry
+	var found bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(diskCacheBucket).Get([]byte(key))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &entry)
+	})
+	if err != nil || !found {
+		if err != nil {
+			c.remove(key)
+		}
+		return nil, false
+	}
+
+	if c.opts.MaxAge > 0 && time.Since(entry.LastUsed) > c.opts.MaxAge {
+		c.remove(key)
+		return nil, false
+	}
+
+	scalar, ok := srv.ScalarType(entry.Type)
+	if !ok {
+		// e.g. an enum from a module that isn't loaded
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(entry.Value))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		c.remove(key)
+		return nil, false
+	}
+	val, err := scalar.DecodeInput(raw)
+	if err != nil {
+		c.remove(key)
+		return nil, false
+	}
+
+	if time.Since(entry.LastUsed) > c.lastUsedResolution() {
+		entry.LastUsed = time.Now()
+		if err := c.put(key, entry); err != nil {
+			slog.Warn("failed to update cached result", "key", key, "error", err)
+		}
+	}
+	return val, true
+}
+
+// store persists val under key if it's a scalar known to the server.
+func (c *DiskCache) store(srv *Server, key digest.Digest, val Typed) error {
+	if !persistable(srv, val) {
+		return nil
+	}
+	payload, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	entry := diskCacheEntry{
+		Type:     val.Type().Name(),
+		Value:    payload,
+		LastUsed: time.Now(),
+	}
+	if len(payload) > maxDiskCacheEntrySize {
+		return nil
+	}
+	if c.opts.MaxSize > 0 && int64(len(payload)) > c.opts.MaxSize/10 {
+		// don't let a single large result evict everything else
+		return nil
+	}
+	if err := c.put(key, entry); err != nil {
+		return err
+	}
+	if c.opts.MaxSize > 0 && c.Size() > c.opts.MaxSize {
+		return c.evict()
+	}
+	return nil
+}
+
+// persistable returns true if val can be stored and decoded again later.
+func persistable(srv *Server, val Typed) bool {
+	if val == nil {
+		return false
+	}
+	if _, ok := val.(Input); !ok {
+		return false
+	}
+	if _, ok := val.(IDable); ok {
+		// IDs are cheap to derive, and always need their object loaded anyway
+		return false
+	}
+	if _, ok := val.(Enumerable); ok {
+		return false
+	}
+	typ := val.Type()
+	if typ.Elem != nil {
+		return false
+	}
+	_, ok := srv.ScalarType(typ.Name())
+	return ok
+}
+
+// stable returns true if no call in the ID, or in the IDs passed to it as
+// arguments, is to a volatile field. Calls to fields the server doesn't know,
+// e.g. from a module that isn't loaded, aren't considered stable.
+func stable(srv *Server, id *idproto.ID) bool {
+	for ; id != nil; id = id.Parent {
+		recv := srv.Root().Type().Name()
+		if id.Parent != nil {
+			recv = id.Parent.Type.NamedType
+		}
+		objType, ok := srv.ObjectType(recv)
+		if !ok {
+			return false
+		}
+		spec, ok := objType.FieldSpec(id.Field)
+		if !ok {
+			return false
+		}
+		if spec.Volatile != nil && spec.Volatile(id) {
+			return false
+		}
+		for _, arg := range id.Args {
+			if !stableLiteral(srv, arg.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stableLiteral(srv *Server, lit *idproto.Literal) bool {
+	switch x := lit.Value.(type) {
+	case *idproto.Literal_Id:
+		return stable(srv, x.Id)
+	case *idproto.Literal_List:
+		for _, v := range x.List.Values {
+			if !stableLiteral(srv, v) {
+				return false
+			}
+		}
+	case *idproto.Literal_Object:
+		for _, v := range x.Object.Values {
+			if !stableLiteral(srv, v.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *DiskCache) put(key digest.Digest, entry diskCacheEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	var delta int64
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(diskCacheBucket)
+		delta = int64(len(key) + len(payload))
+		if old := b.Get([]byte(key)); old != nil {
+			delta -= int64(len(key) + len(old))
+		}
+		return b.Put([]byte(key), payload)
+	})
+	if err != nil {
+		return err
+	}
+	c.grow(delta)
+	return nil
+}
+
+func (c *DiskCache) remove(key digest.Digest) {
+	var delta int64
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(diskCacheBucket)
+		old := b.Get([]byte(key))
+		if old == nil {
+			return nil
+		}
+		delta = -int64(len(key) + len(old))
+		return b.Delete([]byte(key))
+	})
+	if err != nil {
+		slog.Warn("failed to remove cached result", "key", key, "error", err)
+		return
+	}
+	c.grow(delta)
+}
+
+func (c *DiskCache) grow(delta int64) {
+	c.sizeL.Lock()
+	c.size += delta
+	c.sizeL.Unlock()
+}
+
+// evict removes results that are too old, and then the least recently used
+// results until the cache is back under 90% of its maximum size.
+func (c *DiskCache) evict() error {
+	overSize := c.opts.MaxSize > 0 && c.Size() > c.opts.MaxSize
+	if !overSize && c.opts.MaxAge == 0 {
+		return nil
+	}
+
+	type usage struct {
+		key      []byte
+		size     int64
+		lastUsed time.Time
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(diskCacheBucket)
+		var usages []usage
+		var size int64
+		err := b.ForEach(func(k, v []byte) error {
+			var entry diskCacheEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				// unreadable; evict it first
+				entry.LastUsed = time.Time{}
+			}
+			usages = append(usages, usage{
+				key:      append([]byte(nil), k...),
+				size:     int64(len(k) + len(v)),
+				lastUsed: entry.LastUsed,
+			})
+			size += int64(len(k) + len(v))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sort.Slice(usages, func(i, j int) bool {
+			return usages[i].lastUsed.Before(usages[j].lastUsed)
+		})
+
+		target := c.opts.MaxSize * 9 / 10
+		for _, u := range usages {
+			expired := c.opts.MaxAge > 0 && time.Since(u.lastUsed) > c.opts.MaxAge
+			tooBig := c.opts.MaxSize > 0 && size > target
+			if !expired && !tooBig {
+				break
+			}
+			if err := b.Delete(u.key); err != nil {
+				return err
+			}
+			size -= u.size
+		}
+
+		c.sizeL.Lock()
+		c.size = size
+		c.sizeL.Unlock()
+		return nil
+	})
+}
@@ -0,0 +1,269 @@
+package dagql_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/idproto"
+	"github.com/dagger/dagger/dagql/internal/points"
+	"gotest.tools/v3/assert"
+)
+
+func TestDiskCache(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+
+	pureCalls, impureCalls, objectCalls := 0, 0, 0
+	start := func(t *testing.T) (*client.Client, *dagql.DiskCache) {
+		t.Helper()
+		disk, err := dagql.OpenDiskCache(dbPath, dagql.DiskCacheOpts{})
+		assert.NilError(t, err)
+
+		srv := dagql.NewServer(Query{})
+		srv.Cache = disk.Wrap(srv.Cache)
+		points.Install[Query](srv)
+		dagql.Fields[*points.Point]{
+			dagql.Func("manhattan", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Int, error) {
+				pureCalls++
+				return dagql.NewInt(self.X + self.Y), nil
+			}),
+			dagql.Func("roll", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Int, error) {
+				impureCalls++
+				return dagql.NewInt(impureCalls), nil
+			}).Impure("Returns a different number each time."),
+			dagql.Func("mirror", func(ctx context.Context, self *points.Point, _ struct{}) (*points.Point, error) {
+				objectCalls++
+				return &points.Point{X: self.Y, Y: self.X}, nil
+			}),
+		}.Install(srv)
+		return client.New(handler.NewDefaultServer(srv)), disk
+	}
+
+	type result struct {
+		Point struct {
+			Manhattan int
+			Roll      int
+			Mirror    struct {
+				X int
+			}
+		}
+	}
+	query := `query {
+		point(x: 6, y: 7) {
+			manhattan
+			roll
+			mirror {
+				x
+			}
+		}
+	}`
+
+	gql, disk := start(t)
+	var res result
+	req(t, gql, query, &res)
+	assert.Equal(t, 13, res.Point.Manhattan)
+	assert.Equal(t, 1, res.Point.Roll)
+	assert.Equal(t, 7, res.Point.Mirror.X)
+	assert.Equal(t, 1, pureCalls)
+	assert.Equal(t, 1, impureCalls)
+	assert.Equal(t, 1, objectCalls)
+	assert.Assert(t, disk.Size() > 0)
+	assert.NilError(t, disk.Close())
+
+	// after a "restart", only the scalar result of the pure call is remembered
+	gql, disk = start(t)
+	res = result{}
+	req(t, gql, query, &res)
+	assert.Equal(t, 13, res.Point.Manhattan)
+	assert.Equal(t, 2, res.Point.Roll)
+	assert.Equal(t, 7, res.Point.Mirror.X)
+	assert.Equal(t, 1, pureCalls)
+	assert.Equal(t, 2, impureCalls)
+	assert.Equal(t, 2, objectCalls)
+	assert.NilError(t, disk.Close())
+}
+
+func TestDiskCacheMaxSize(t *testing.T) {
+	const maxSize = 4096
+
+	disk, err := dagql.OpenDiskCache(filepath.Join(t.TempDir(), "cache.db"), dagql.DiskCacheOpts{
+		MaxSize: maxSize,
+	})
+	assert.NilError(t, err)
+	defer disk.Close()
+
+	srv := dagql.NewServer(Query{})
+	srv.Cache = disk.Wrap(srv.Cache)
+	points.Install[Query](srv)
+	calls := map[int]int{}
+	dagql.Fields[*points.Point]{
+		dagql.Func("manhattan", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Int, error) {
+			calls[self.X]++
+			return dagql.NewInt(self.X + self.Y), nil
+		}),
+	}.Install(srv)
+	gql := client.New(handler.NewDefaultServer(srv))
+
+	for i := 0; i < 100; i++ {
+		var res struct {
+			Point struct {
+				Manhattan int
+			}
+		}
+		req(t, gql, fmt.Sprintf(`query { point(x: %d, y: 0) { manhattan } }`, i), &res)
+		assert.Equal(t, i, res.Point.Manhattan)
+		assert.Assert(t, disk.Size() <= maxSize, "size %d exceeds %d", disk.Size(), maxSize)
+	}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 1, calls[i])
+	}
+}
+
+func TestDiskCacheMaxEntrySize(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+
+	calls := map[int]int{}
+	run := func(t *testing.T) {
+		t.Helper()
+		// no MaxSize; large results still aren't stored
+		disk, err := dagql.OpenDiskCache(dbPath, dagql.DiskCacheOpts{})
+		assert.NilError(t, err)
+		defer disk.Close()
+
+		srv := dagql.NewServer(Query{})
+		srv.Cache = disk.Wrap(srv.Cache)
+		points.Install[Query](srv)
+		dagql.Fields[*points.Point]{
+			dagql.Func("padded", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.String, error) {
+				calls[self.X]++
+				return dagql.NewString(strings.Repeat("x", self.X)), nil
+			}),
+		}.Install(srv)
+		gql := client.New(handler.NewDefaultServer(srv))
+
+		for _, size := range []int{10, 2 << 20} {
+			var res struct {
+				Point struct {
+					Padded string
+				}
+			}
+			req(t, gql, fmt.Sprintf(`query { point(x: %d, y: 0) { padded } }`, size), &res)
+			assert.Equal(t, size, len(res.Point.Padded))
+		}
+	}
+
+	run(t)
+	run(t)
+	assert.Equal(t, 1, calls[10])
+	assert.Equal(t, 2, calls[2<<20])
+}
+
+func TestDiskCacheVersion(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+
+	calls := 0
+	run := func(t *testing.T, version string) {
+		t.Helper()
+		disk, err := dagql.OpenDiskCache(dbPath, dagql.DiskCacheOpts{Version: version})
+		assert.NilError(t, err)
+		defer disk.Close()
+
+		srv := dagql.NewServer(Query{})
+		srv.Cache = disk.Wrap(srv.Cache)
+		points.Install[Query](srv)
+		dagql.Fields[*points.Point]{
+			dagql.Func("manhattan", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Int, error) {
+				calls++
+				return dagql.NewInt(self.X + self.Y), nil
+			}),
+		}.Install(srv)
+		gql := client.New(handler.NewDefaultServer(srv))
+
+		var res struct {
+			Point struct {
+				Manhattan int
+			}
+		}
+		req(t, gql, `query { point(x: 6, y: 7) { manhattan } }`, &res)
+		assert.Equal(t, 13, res.Point.Manhattan)
+	}
+
+	run(t, "v1")
+	run(t, "v1")
+	assert.Equal(t, 1, calls)
+
+	// results from another version are discarded
+	run(t, "v2")
+	assert.Equal(t, 2, calls)
+}
+
+func TestDiskCacheVolatile(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+
+	calls := map[int]int{}
+	run := func(t *testing.T) {
+		t.Helper()
+		disk, err := dagql.OpenDiskCache(dbPath, dagql.DiskCacheOpts{})
+		assert.NilError(t, err)
+		defer disk.Close()
+
+		srv := dagql.NewServer(Query{})
+		srv.Cache = disk.Wrap(srv.Cache)
+		points.Install[Query](srv)
+		dagql.Fields[*points.Point]{
+			dagql.Func("manhattan", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.Int, error) {
+				calls[self.X]++
+				return dagql.NewInt(self.X + self.Y), nil
+			}),
+			dagql.Func("drift", func(ctx context.Context, self *points.Point, args struct {
+				By int `default:"1"`
+			}) (*points.Point, error) {
+				return &points.Point{X: self.X + args.By, Y: self.Y}, nil
+			}).Volatile(func(call *idproto.ID) bool {
+				// only drifting by 0 is guaranteed to land in the same place
+				for _, arg := range call.Args {
+					if arg.Name == "by" {
+						return arg.Value.GetInt() != 0
+					}
+				}
+				return true
+			}),
+		}.Install(srv)
+		gql := client.New(handler.NewDefaultServer(srv))
+
+		var res struct {
+			Point struct {
+				Manhattan int
+				Drift     struct {
+					Manhattan int
+				}
+				Still struct {
+					Manhattan int
+				}
+			}
+		}
+		req(t, gql, `query {
+			point(x: 6, y: 7) {
+				manhattan
+				drift { manhattan }
+				still: drift(by: 0) { manhattan }
+			}
+		}`, &res)
+		assert.Equal(t, 13, res.Point.Manhattan)
+		assert.Equal(t, 14, res.Point.Drift.Manhattan)
+		assert.Equal(t, 13, res.Point.Still.Manhattan)
+	}
+
+	run(t)
+	run(t)
+
+	// results derived from a volatile call are only cached in memory, so only
+	// the drifted point is computed again
+	assert.Equal(t, 2, calls[6])
+	assert.Equal(t, 2, calls[7])
+}
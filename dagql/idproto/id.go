@@ -223,7 +223,14 @@ func (id *ID) Encode() (string, error) {
 	return base64.URLEncoding.EncodeToString(proto), nil
 }
 
+// Decode decodes an ID from its encoded form.
+//
+// References to IDs can't be decoded on their own; they're resolved by the
+// server holding the ID in its Store instead.
 func (id *ID) Decode(str string) error {
+	if IsRef(str) {
+		return fmt.Errorf("cannot decode ID reference %q outside of the session that returned it", str)
+	}
 	bytes, err := base64.URLEncoding.DecodeString(str)
 	if err != nil {
 		return fmt.Errorf("cannot decode ID from %q: %w", str, err)
@@ -26,8 +26,8 @@ const (
 // value.
 //
 // It may be binary=>base64-encoded to be used as a GraphQL ID value for
-// objects. Alternatively it may be stored in a database and referred to via an
-// RFC-6920 ni://sha-256;... URI.
+// objects. Alternatively it may be interned in a Store and referred to via an
+// RFC-6920 ni:///sha-256;... URI.
 type ID struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -0,0 +1,152 @@
+package idproto
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// refPrefix is the prefix of RFC-6920 URIs naming a SHA-256 digest.
+const refPrefix = "ni:///sha-256;"
+
+// Ref returns an RFC-6920 URI referring to the ID by its digest, which can be
+// passed in its stead to the server whose Store holds the ID.
+func (id *ID) Ref() (string, error) {
+	dgst, err := id.Digest()
+	if err != nil {
+		return "", err
+	}
+	return DigestRef(dgst)
+}
+
+// DigestRef returns the RFC-6920 URI for the given SHA-256 digest.
+func DigestRef(dgst digest.Digest) (string, error) {
+	if dgst.Algorithm() != digest.SHA256 {
+		return "", fmt.Errorf("unsupported digest algorithm: %s", dgst.Algorithm())
+	}
+	sum, err := hex.DecodeString(dgst.Encoded())
+	if err != nil {
+		return "", fmt.Errorf("invalid digest %q: %w", dgst, err)
+	}
+	return refPrefix + base64.RawURLEncoding.EncodeToString(sum), nil
+}
+
+// IsRef returns true if the string is an RFC-6920 URI rather than an encoded
+// ID.
+func IsRef(str string) bool {
+	return strings.HasPrefix(str, refPrefix)
+}
+
+// ParseRef returns the digest named by an RFC-6920 URI.
+func ParseRef(ref string) (digest.Digest, error) {
+	enc, ok := strings.CutPrefix(ref, refPrefix)
+	if !ok {
+		return "", fmt.Errorf("not a sha-256 ni URI: %q", ref)
+	}
+	sum, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return "", fmt.Errorf("invalid ni URI %q: %w", ref, err)
+	}
+	if len(sum) != sha256.Size {
+		return "", fmt.Errorf("invalid ni URI %q: expected %d bytes, got %d", ref, sha256.Size, len(sum))
+	}
+	return digest.NewDigestFromEncoded(digest.SHA256, hex.EncodeToString(sum)), nil
+}
+
+// Store interns IDs by their digest so they can be referred to by the short
+// URIs returned by Ref.
+//
+// A Store holds a bounded number of IDs, forgetting the least recently used
+// ones first, so references are meant to be used shortly after the ID was
+// returned rather than kept around.
+type Store struct {
+	mu  sync.Mutex
+	max int
+	ids map[digest.Digest]*list.Element
+
+	// lru orders the IDs from most to least recently used.
+	lru *list.List
+}
+
+type storeEntry struct {
+	dgst digest.Digest
+	id   *ID
+}
+
+// NewStore returns an empty Store holding at most max IDs, or any number of
+// them if max is zero.
+func NewStore(max int) *Store {
+	return &Store{
+		max: max,
+		ids: map[digest.Digest]*list.Element{},
+		lru: list.New(),
+	}
+}
+
+// Intern stores the ID and returns its reference.
+func (store *Store) Intern(id *ID) (string, error) {
+	dgst, err := id.Digest()
+	if err != nil {
+		return "", err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if elem, ok := store.ids[dgst]; ok {
+		store.lru.MoveToFront(elem)
+	} else {
+		store.ids[dgst] = store.lru.PushFront(storeEntry{dgst: dgst, id: id})
+		for store.max > 0 && store.lru.Len() > store.max {
+			oldest := store.lru.Remove(store.lru.Back()).(storeEntry)
+			delete(store.ids, oldest.dgst)
+		}
+	}
+	return DigestRef(dgst)
+}
+
+// Get returns the ID with the given digest, if it's held by the store.
+func (store *Store) Get(dgst digest.Digest) (*ID, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	elem, ok := store.ids[dgst]
+	if !ok {
+		return nil, false
+	}
+	store.lru.MoveToFront(elem)
+	return elem.Value.(storeEntry).id, true
+}
+
+// Resolve returns the ID that the reference refers to.
+func (store *Store) Resolve(ref string) (*ID, error) {
+	dgst, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := store.Get(dgst)
+	if !ok {
+		return nil, fmt.Errorf("unknown ID reference %q; it may have expired, pass the full ID instead", ref)
+	}
+	return id, nil
+}
+
+// Len returns the number of interned IDs.
+func (store *Store) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.lru.Len()
+}
+
+// Close drops the interned IDs, after which their references can no longer
+// be resolved.
+func (store *Store) Close() error {
+	store.mu.Lock()
+	store.ids = map[digest.Digest]*list.Element{}
+	store.lru.Init()
+	store.mu.Unlock()
+	return nil
+}
@@ -28,7 +28,12 @@ func New() progrock.Frontend {
 var _ progrock.Writer = (*Frontend)(nil)
 
 func (f *Frontend) WriteStatus(status *progrock.StatusUpdate) error {
+	var sawIDs bool
 	for _, v := range status.Metas {
+		if v.Name != "id" {
+			continue
+		}
+		sawIDs = true
 		var id idproto.ID
 		if err := v.Data.UnmarshalTo(&id); err != nil {
 			return fmt.Errorf("unmarshal payload: %w", err)
@@ -39,7 +44,7 @@ func (f *Frontend) WriteStatus(status *progrock.StatusUpdate) error {
 		}
 		f.allIDs[dig.String()] = &id
 	}
-	if len(status.Metas) > 0 {
+	if sawIDs {
 		f.leafIDs = make(map[string]*idproto.ID)
 		for vid, id := range f.allIDs {
 			f.leafIDs[vid] = id
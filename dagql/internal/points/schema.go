@@ -122,6 +122,9 @@ func Install[R dagql.Typed](srv *dagql.Server) {
 				{X: self.X, Y: self.Y - 1},
 				{X: self.X, Y: self.Y + 1},
 			}, nil
+		}).Complexity(func(_ map[string]dagql.Input, childComplexity int) int {
+			// each neighbor is sub-selected
+			return 1 + 4*childComplexity
 		}),
 		dagql.Func("line", func(ctx context.Context, self *Point, args struct {
 			To dagql.ID[*Point]
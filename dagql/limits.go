@@ -0,0 +1,231 @@
+package dagql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dagger/dagger/dagql/idproto"
+)
+
+// Limits bounds the queries that a Server executes, so that a runaway query
+// can't monopolize it. Zero values mean no limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of a query's selections.
+	MaxDepth int
+
+	// MaxCost is the maximum estimated cost of a query, as computed by the
+	// Complexity of each selected field.
+	MaxCost int
+
+	// MaxFanOut is the maximum number of elements of a list that may be
+	// sub-selected.
+	MaxFanOut int
+}
+
+// Narrow returns the stricter of each of the given limits, e.g. to apply
+// a session's own limits within those of the engine.
+func (limits Limits) Narrow(other Limits) Limits {
+	narrow := func(a, b int) int {
+		if a == 0 || (b > 0 && b < a) {
+			return b
+		}
+		return a
+	}
+	return Limits{
+		MaxDepth:  narrow(limits.MaxDepth, other.MaxDepth),
+		MaxCost:   narrow(limits.MaxCost, other.MaxCost),
+		MaxFanOut: narrow(limits.MaxFanOut, other.MaxFanOut),
+	}
+}
+
+// ComplexityFunc estimates the cost of selecting a field with the given
+// arguments, given the estimated cost of its sub-selections.
+type ComplexityFunc func(args map[string]Input, childComplexity int) int
+
+// LimitError is returned for queries that exceed the Limits of a Server.
+type LimitError struct {
+	// Limit is the name of the exceeded limit, e.g. "depth".
+	Limit string
+
+	Max    int
+	Actual int
+
+	// Path is the path of the offending selections, if known ahead of time.
+	Path []string
+}
+
+func (err *LimitError) Error() string {
+	msg := fmt.Sprintf("query %s of %d exceeds the maximum of %d", err.Limit, err.Actual, err.Max)
+	if len(err.Path) > 0 {
+		msg += ": " + strings.Join(err.Path, ".")
+	}
+	return msg
+}
+
+var _ ExtendedError = (*LimitError)(nil)
+
+func (err *LimitError) Extensions() map[string]any {
+	ext := map[string]any{
+		"code":   "LIMIT_EXCEEDED",
+		"limit":  err.Limit,
+		"max":    err.Max,
+		"actual": err.Actual,
+	}
+	if len(err.Path) > 0 {
+		ext["path"] = err.Path
+	}
+	return ext
+}
+
+// checkLimits rejects selections on the given type that exceed the server's
+// limits, before any of them are executed.
+func (s *Server) checkLimits(self string, sels []Selection) error {
+	if limit := s.Limits.MaxDepth; limit > 0 {
+		depth, path := selectionDepth(sels, map[*idproto.ID]int{})
+		if depth > limit {
+			if len(path) > limit+1 {
+				// stop at the first selection that's too deep
+				path = path[:limit+1]
+			}
+			return &LimitError{
+				Limit:  "depth",
+				Max:    limit,
+				Actual: depth,
+				Path:   path,
+			}
+		}
+	}
+	if limit := s.Limits.MaxCost; limit > 0 {
+		cost, path, err := s.selectionCost(self, sels)
+		if err != nil {
+			return err
+		}
+		if cost > limit {
+			return &LimitError{
+				Limit:  "cost",
+				Max:    limit,
+				Actual: cost,
+				// point to where most of the cost comes from
+				Path: path,
+			}
+		}
+	}
+	return nil
+}
+
+// selectionDepth returns the nesting depth of the selections, along with the
+// path to the deepest one.
+//
+// IDs passed as arguments are loaded by nested calls too, so their depth
+// counts towards the depth of the selection they're passed to.
+func selectionDepth(sels []Selection, memo map[*idproto.ID]int) (int, []string) {
+	var depth int
+	var path []string
+	for _, sel := range sels {
+		subDepth, subPath := selectionDepth(sel.Subselections, memo)
+		name := sel.Name()
+		for _, arg := range sel.Selector.Args {
+			if argDepth := literalDepth(arg.Value.ToLiteral(), memo); argDepth > subDepth {
+				subDepth = argDepth
+				subPath = nil
+				name = fmt.Sprintf("%s(%s)", sel.Name(), arg.Name)
+			}
+		}
+		if subDepth+1 > depth {
+			depth = subDepth + 1
+			path = append([]string{name}, subPath...)
+		}
+	}
+	return depth, path
+}
+
+// idDepth returns the number of nested calls made to load the ID.
+func idDepth(id *idproto.ID, memo map[*idproto.ID]int) int {
+	if depth, ok := memo[id]; ok {
+		return depth
+	}
+	var depth int
+	if id.Parent != nil {
+		depth = idDepth(id.Parent, memo)
+	}
+	for _, arg := range id.Args {
+		depth = max(depth, literalDepth(arg.Value, memo))
+	}
+	depth++
+	memo[id] = depth
+	return depth
+}
+
+// literalDepth returns the depth of the deepest ID in the literal.
+func literalDepth(lit *idproto.Literal, memo map[*idproto.ID]int) int {
+	var depth int
+	switch x := lit.Value.(type) {
+	case *idproto.Literal_Id:
+		depth = idDepth(x.Id, memo)
+	case *idproto.Literal_List:
+		for _, v := range x.List.Values {
+			depth = max(depth, literalDepth(v, memo))
+		}
+	case *idproto.Literal_Object:
+		for _, v := range x.Object.Values {
+			depth = max(depth, literalDepth(v.Value, memo))
+		}
+	}
+	return depth
+}
+
+// selectionCost returns the estimated cost of the selections on the given
+// type, along with the path to the most expensive one.
+func (s *Server) selectionCost(self string, sels []Selection) (int, []string, error) {
+	class, ok := s.objects[self]
+	if !ok {
+		return 0, nil, fmt.Errorf("selectionCost: not an Object type: %q", self)
+	}
+	var total, most int
+	var path []string
+	for _, sel := range sels {
+		spec, ok := class.FieldSpec(sel.Selector.Field)
+		if !ok {
+			return 0, nil, fmt.Errorf("%s has no such field: %q", self, sel.Selector.Field)
+		}
+		var childCost int
+		var childPath []string
+		if len(sel.Subselections) > 0 {
+			var err error
+			childCost, childPath, err = s.selectionCost(spec.Type.Type().Name(), sel.Subselections)
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+		cost := 1 + childCost
+		if spec.Complexity != nil {
+			args, err := applyDefaults(spec, sel.Selector.Args)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%s: %w", sel.Name(), err)
+			}
+			cost = spec.Complexity(args, childCost)
+		}
+		total += cost
+		if cost > most || path == nil {
+			most = cost
+			path = append([]string{sel.Name()}, childPath...)
+		}
+	}
+	return total, path, nil
+}
+
+type selectionPathContextKey struct{}
+
+// withSelectionPath records the selection being resolved in the context, so
+// that limits enforced during execution can point to it.
+func withSelectionPath(ctx context.Context, name string) context.Context {
+	path := selectionPath(ctx)
+	return context.WithValue(ctx, selectionPathContextKey{}, append(path[:len(path):len(path)], name))
+}
+
+// selectionPath returns the path of the selection being resolved.
+func selectionPath(ctx context.Context) []string {
+	path, _ := ctx.Value(selectionPathContextKey{}).([]string)
+	return path
+}
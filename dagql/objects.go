@@ -87,6 +87,14 @@ func (class Class[T]) Field(name string) (Field[T], bool) {
 	return *field, ok
 }
 
+func (class Class[T]) FieldSpec(name string) (FieldSpec, bool) {
+	field, ok := class.Field(name)
+	if !ok {
+		return FieldSpec{}, false
+	}
+	return field.Spec, true
+}
+
 func (class Class[T]) Install(fields ...Field[T]) {
 	class.fieldsL.Lock()
 	defer class.fieldsL.Unlock()
@@ -164,6 +172,12 @@ func (cls Class[T]) ParseField(ctx context.Context, astField *ast.Field, vars ma
 		if val == nil {
 			continue
 		}
+		if srv := currentServer(ctx); srv != nil {
+			val, err = srv.resolveRefs(argSpec.Type.Type(), val)
+			if err != nil {
+				return Selector{}, nil, fmt.Errorf("arg %q: %w", arg.Name, err)
+			}
+		}
 		input, err := argSpec.Type.Decoder().DecodeInput(val)
 		if err != nil {
 			return Selector{}, nil, fmt.Errorf("init arg %q value as %T (%s) using %T: %w", arg.Name, argSpec.Type, argSpec.Type.Type(), argSpec.Type.Decoder(), err)
@@ -379,8 +393,18 @@ type FieldSpec struct {
 	DeprecatedReason string
 	// Module is the module that provides the field's implementation.
 	Module *idproto.ID
+	// Complexity estimates the cost of selecting the field. If nil, the cost
+	// is 1 plus the cost of the field's sub-selections.
+	Complexity ComplexityFunc
+	// Volatile reports whether a call to the field resolves state that can
+	// move between runs of the server, such as a remote ref. Results derived
+	// from such calls are never persisted. If nil, no call is volatile.
+	Volatile VolatileFunc
 }
 
+// VolatileFunc reports whether the given call to a field is volatile.
+type VolatileFunc func(call *idproto.ID) bool
+
 func (spec FieldSpec) FieldDefinition() *ast.FieldDefinition {
 	def := &ast.FieldDefinition{
 		Name:        spec.Name,
@@ -490,6 +514,11 @@ func (fields Fields[T]) Install(server *Server) {
 	var t T
 	typeName := t.Type().Name()
 	class := fields.findOrInitializeType(server, typeName)
+	for _, field := range fields {
+		if _, isStream := field.Spec.Type.(streamer); isStream && (server.sub == nil || server.sub.Type().Name() != typeName) {
+			panic(fmt.Errorf("field %s.%s: only fields of the subscription root may return a stream", typeName, field.Spec.Name))
+		}
+	}
 	objectFields, err := reflectFieldsForType(t, false, builtinOrTyped)
 	if err != nil {
 		panic(fmt.Errorf("fields for %T: %w", t, err))
@@ -603,6 +632,28 @@ func (field Field[T]) Impure(reason string, paras ...string) Field[T] {
 	return field
 }
 
+// Volatile marks calls to the field as resolving state that can move between
+// runs of the server, such as a remote ref, so that results derived from them
+// are only cached in memory and never persisted by a DiskCache.
+//
+// If fn is given, only the calls that it returns true for are volatile, e.g.
+// to exempt calls that pin the state by digest.
+func (field Field[T]) Volatile(fn ...VolatileFunc) Field[T] {
+	if len(fn) > 0 {
+		field.Spec.Volatile = fn[0]
+	} else {
+		field.Spec.Volatile = func(*idproto.ID) bool { return true }
+	}
+	return field
+}
+
+// Complexity sets the function used to estimate the cost of selecting the
+// field, e.g. to account for the number of elements in a list.
+func (field Field[T]) Complexity(fn ComplexityFunc) Field[T] {
+	field.Spec.Complexity = fn
+	return field
+}
+
 // Impure marks the field as "impure", meaning its result may change over time,
 // or it has side effects.
 func (field Field[T]) Meta() Field[T] {
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -24,6 +27,7 @@ import (
 // runtime.
 type Server struct {
 	root        Object
+	sub         Object
 	telemetry   AroundFunc
 	objects     map[string]ObjectType
 	scalars     map[string]ScalarType
@@ -36,6 +40,14 @@ type Server struct {
 	//
 	// TODO: copy-on-write
 	Cache Cache
+
+	// Limits bounds the queries executed by the server.
+	Limits Limits
+
+	// IDs, if set, interns every ID returned by the server, so that clients
+	// may refer to them by reference instead. It can be replicated to another
+	// *Server to share references.
+	IDs *idproto.Store
 }
 
 // AroundFunc is a function that is called around every non-cached selection.
@@ -58,6 +70,13 @@ type Cache interface {
 	) (Typed, error)
 }
 
+// CacheStatsReporter is implemented by caches that report their usage, such
+// as the ones created with NewCache.
+type CacheStatsReporter interface {
+	// Stats reports the usage of the cache.
+	Stats() CacheStats
+}
+
 // TypeDef is a type whose sole practical purpose is to define a GraphQL type,
 // so it explicitly includes the Definitive interface.
 type TypeDef interface {
@@ -282,11 +301,18 @@ func (s *Server) Schema() *ast.Schema { // TODO: change this to be updated whene
 	defer s.installLock.Unlock()
 	// TODO track when the schema changes, cache until it changes again
 	queryType := s.Root().Type().Name()
+	var subscriptionType string
+	if s.sub != nil {
+		subscriptionType = s.sub.Type().Name()
+	}
 	schema := &ast.Schema{}
 	for _, t := range s.objects { // TODO stable order
 		def := definition(ast.Object, t)
-		if def.Name == queryType {
+		switch def.Name {
+		case queryType:
 			schema.Query = def
+		case subscriptionType:
+			schema.Subscription = def
 		}
 		schema.AddTypes(def)
 	}
@@ -303,10 +329,34 @@ func (s *Server) Schema() *ast.Schema { // TODO: change this to be updated whene
 	return schema
 }
 
-// Complexity returns the complexity of the given field.
+// Complexity returns the complexity of the given field, as estimated by its
+// ComplexityFunc, if it has one.
 func (s *Server) Complexity(typeName, field string, childComplexity int, args map[string]interface{}) (int, bool) {
-	// TODO
-	return 1, false
+	class, ok := s.objects[typeName]
+	if !ok {
+		return 1, false
+	}
+	spec, ok := class.FieldSpec(field)
+	if !ok || spec.Complexity == nil {
+		return 1, false
+	}
+	inputs := Inputs{}
+	for name, val := range args {
+		argSpec, ok := spec.Args.Lookup(name)
+		if !ok || val == nil {
+			continue
+		}
+		input, err := argSpec.Type.Decoder().DecodeInput(val)
+		if err != nil {
+			return 1, false
+		}
+		inputs = append(inputs, NamedInput{Name: name, Value: input})
+	}
+	decoded, err := applyDefaults(spec, inputs)
+	if err != nil {
+		return 1, false
+	}
+	return spec.Complexity(decoded, childComplexity), true
 }
 
 // ExtendedError is an error that can provide extra data in an error response.
@@ -317,6 +367,12 @@ type ExtendedError interface {
 
 // Exec implements graphql.ExecutableSchema.
 func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
+	if graphql.HasOperationContext(ctx1) {
+		op := graphql.GetOperationContext(ctx1).Operation
+		if op != nil && op.Operation == ast.Subscription {
+			return s.execSubscription()
+		}
+	}
 	return func(ctx context.Context) *graphql.Response {
 		gqlOp := graphql.GetOperationContext(ctx)
 
@@ -324,21 +380,10 @@ func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
 			return graphql.ErrorResponse(ctx, "validate: %s", err)
 		}
 
-		results, err := s.ExecOp(ctx, gqlOp)
+		refs := new(sync.Map)
+		results, err := s.ExecOp(withIDRefs(ctx, refs), gqlOp)
 		if err != nil {
-			gqlOp.Error(ctx, err)
-			gqlErr := &gqlerror.Error{
-				Err:     err,
-				Message: err.Error(),
-				// TODO Path would correspond nicely to an ID
-			}
-			var ext ExtendedError
-			if errors.As(err, &ext) {
-				gqlErr.Extensions = ext.Extensions()
-			}
-			return &graphql.Response{
-				Errors: gqlerror.List{gqlErr},
-			}
+			return errorResponse(ctx, gqlOp, err)
 		}
 
 		data, err := json.Marshal(results)
@@ -347,12 +392,103 @@ func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
 			return graphql.ErrorResponse(ctx, "marshal: %s", err)
 		}
 
+		idRefs := map[string]any{}
+		refs.Range(func(path, ref any) bool {
+			idRefs[path.(string)] = ref
+			return true
+		})
+		if len(idRefs) > 0 {
+			// the references to the returned IDs, which the client may pass
+			// instead of the full IDs
+			graphql.RegisterExtension(ctx, "idRefs", idRefs)
+		}
+
 		return &graphql.Response{
 			Data: json.RawMessage(data),
 		}
 	}
 }
 
+// execSubscription returns a handler which resolves the subscription on its
+// first call, and then sends a response for each value of the selected
+// stream, until it ends.
+func (s *Server) execSubscription() graphql.ResponseHandler {
+	var sub *subscription
+	var done bool
+	return func(ctx context.Context) *graphql.Response {
+		if done {
+			return nil
+		}
+		gqlOp := graphql.GetOperationContext(ctx)
+
+		if sub == nil {
+			if err := gqlOp.Validate(ctx); err != nil {
+				done = true
+				return graphql.ErrorResponse(ctx, "validate: %s", err)
+			}
+			var err error
+			sub, err = s.subscribe(ctx, gqlOp)
+			if err != nil {
+				done = true
+				return errorResponse(ctx, gqlOp, err)
+			}
+		}
+
+		data, err := sub.next(ctx)
+		if err != nil {
+			done = true
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errorResponse(ctx, gqlOp, err)
+		}
+		return &graphql.Response{
+			Data: json.RawMessage(data),
+		}
+	}
+}
+
+// subscribe resolves the selections of a subscription, which must select
+// exactly one stream.
+func (s *Server) subscribe(ctx context.Context, gqlOp *graphql.OperationContext) (*subscription, error) {
+	if s.sub == nil {
+		return nil, fmt.Errorf("subscriptions not supported")
+	}
+	op := gqlOp.Operation
+	sels, err := s.parseASTSelections(ctx, gqlOp, s.sub.Type(), op.SelectionSet)
+	if err != nil {
+		return nil, fmt.Errorf("subscription:\n%s\n\nerror: parse selections: %w", gqlOp.RawQuery, err)
+	}
+	if err := s.checkLimits(s.sub.Type().Name(), sels); err != nil {
+		return nil, err
+	}
+	sub := &subscription{}
+	sub.results, err = s.Resolve(context.WithValue(ctx, subscriptionCtx{}, sub), s.sub, sels...)
+	if err != nil {
+		return nil, fmt.Errorf("resolve: %w", err)
+	}
+	if sub.stream == nil {
+		return nil, fmt.Errorf("subscriptions must select exactly one stream")
+	}
+	return sub, nil
+}
+
+func errorResponse(ctx context.Context, gqlOp *graphql.OperationContext, err error) *graphql.Response {
+	gqlOp.Error(ctx, err)
+	gqlErr := &gqlerror.Error{
+		Err:     err,
+		Message: err.Error(),
+		// TODO Path would correspond nicely to an ID
+	}
+	var ext ExtendedError
+	if errors.As(err, &ext) {
+		gqlErr.Extensions = ext.Extensions()
+	}
+	return &graphql.Response{
+		Errors: gqlerror.List{gqlErr},
+	}
+}
+
 func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (map[string]any, error) {
 	if gqlOp.Doc == nil {
 		var err error
@@ -373,6 +509,9 @@ func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (m
 			if err != nil {
 				return nil, fmt.Errorf("query:\n%s\n\nerror: parse selections: %w", gqlOp.RawQuery, err)
 			}
+			if err := s.checkLimits(s.root.Type().Name(), sels); err != nil {
+				return nil, err
+			}
 			results, err = s.Resolve(ctx, s.root, sels...)
 			if err != nil {
 				return nil, fmt.Errorf("resolve: %w", err)
@@ -381,7 +520,7 @@ func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (m
 			// TODO
 			return nil, fmt.Errorf("mutations not supported")
 		case ast.Subscription:
-			// TODO
+			// subscriptions stream multiple responses, so they go through Exec
 			return nil, fmt.Errorf("subscriptions not supported")
 		}
 	}
@@ -399,7 +538,7 @@ func (s *Server) Resolve(ctx context.Context, self Object, sels ...Selection) (m
 	for _, sel := range sels {
 		sel := sel
 		pool.Go(func() error {
-			res, err := s.resolvePath(ctx, self, sel)
+			res, err := s.resolvePath(withSelectionPath(ctx, sel.Name()), self, sel)
 			if err != nil {
 				return fmt.Errorf("%s: %w", sel.Name(), err)
 			}
@@ -506,6 +645,22 @@ func CurrentID(ctx context.Context) *idproto.ID {
 	return val.(*idproto.ID)
 }
 
+type serverCtx struct{}
+
+func serverToContext(ctx context.Context, srv *Server) context.Context {
+	return context.WithValue(ctx, serverCtx{}, srv)
+}
+
+// currentServer returns the Server performing the current selection, which
+// knows how to decode the scalars it returns.
+func currentServer(ctx context.Context) *Server {
+	val := ctx.Value(serverCtx{})
+	if val == nil {
+		return nil
+	}
+	return val.(*Server)
+}
+
 func (s *Server) cachedSelect(ctx context.Context, self Object, sel Selector) (res Typed, chained *idproto.ID, rerr error) {
 	chainedID, err := self.IDFor(ctx, sel)
 	if err != nil {
@@ -526,7 +681,11 @@ func (s *Server) cachedSelect(ctx context.Context, self Object, sel Selector) (r
 	if chainedID.IsTainted() {
 		val, err = doSelect(ctx)
 	} else {
-		val, err = s.Cache.GetOrInitialize(ctx, dig, doSelect)
+		if spec, ok := self.ObjectType().FieldSpec(sel.Field); ok && spec.Volatile != nil && spec.Volatile(chainedID) {
+			// keep it for the rest of the session, so it doesn't move under us
+			ctx = pinCache(ctx)
+		}
+		val, err = s.Cache.GetOrInitialize(serverToContext(ctx, s), dig, doSelect)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -545,11 +704,25 @@ func (s *Server) resolvePath(ctx context.Context, self Object, sel Selection) (r
 		return nil, nil
 	}
 
+	if stream, ok := val.(streamer); ok {
+		// the subscription sends the stream's values in this spot
+		return attachStream(ctx, sel, stream)
+	}
+
 	enum, ok := val.(Enumerable)
 	if ok {
 		// we're sub-selecting into an enumerable value, so we need to resolve each
 		// element
 
+		if limit := s.Limits.MaxFanOut; limit > 0 && len(sel.Subselections) > 0 && enum.Len() > limit {
+			return nil, &LimitError{
+				Limit:  "fan-out",
+				Max:    limit,
+				Actual: enum.Len(),
+				Path:   selectionPath(ctx),
+			}
+		}
+
 		// TODO arrays of arrays
 		results := []any{} // TODO subtle: favor [] over null result
 		for nth := 1; nth <= enum.Len(); nth++ {
@@ -564,7 +737,11 @@ func (s *Server) resolvePath(ctx context.Context, self Object, sel Selection) (r
 					continue
 				}
 			}
+			nthCtx := withSelectionPath(ctx, strconv.Itoa(nth-1))
 			if len(sel.Subselections) == 0 {
+				if err := s.intern(nthCtx, val); err != nil {
+					return nil, err
+				}
 				results = append(results, val)
 			} else {
 				nthID := chainedID.Clone()
@@ -573,7 +750,7 @@ func (s *Server) resolvePath(ctx context.Context, self Object, sel Selection) (r
 				if err != nil {
 					return nil, fmt.Errorf("instantiate %dth array element: %w", nth, err)
 				}
-				res, err := s.Resolve(ctx, node, sel.Subselections...)
+				res, err := s.Resolve(nthCtx, node, sel.Subselections...)
 				if err != nil {
 					return nil, err
 				}
@@ -584,6 +761,9 @@ func (s *Server) resolvePath(ctx context.Context, self Object, sel Selection) (r
 	}
 
 	if len(sel.Subselections) == 0 {
+		if err := s.intern(ctx, val); err != nil {
+			return nil, err
+		}
 		return val, nil
 	}
 
@@ -596,6 +776,107 @@ func (s *Server) resolvePath(ctx context.Context, self Object, sel Selection) (r
 	return s.Resolve(ctx, node, sel.Subselections...)
 }
 
+// intern stores the value in the server's ID store if it's an ID, and reports
+// its reference to the client, so that it can refer to the ID by reference
+// instead.
+func (s *Server) intern(ctx context.Context, val Typed) error {
+	refs := idRefsFromContext(ctx)
+	if s.IDs == nil || refs == nil {
+		return nil
+	}
+	id, ok := val.(IDType)
+	if !ok || id.ID() == nil {
+		return nil
+	}
+	ref, err := s.IDs.Intern(id.ID())
+	if err != nil {
+		return err
+	}
+	refs.Store(strings.Join(selectionPath(ctx), "."), ref)
+	return nil
+}
+
+type idRefsContextKey struct{}
+
+// withIDRefs collects the references to the IDs returned by a query into
+// refs, keyed by the path of the selection that returned them, e.g.
+// "point.neighbors.0.id".
+func withIDRefs(ctx context.Context, refs *sync.Map) context.Context {
+	return context.WithValue(ctx, idRefsContextKey{}, refs)
+}
+
+func idRefsFromContext(ctx context.Context) *sync.Map {
+	refs, _ := ctx.Value(idRefsContextKey{}).(*sync.Map)
+	return refs
+}
+
+// resolveRefs replaces any references to IDs in an argument value of the
+// given type with the IDs they refer to, which must have been returned by the
+// server earlier in the session.
+func (s *Server) resolveRefs(t *ast.Type, val any) (any, error) {
+	if s.IDs == nil {
+		return val, nil
+	}
+	if t.Elem != nil {
+		list, ok := val.([]any)
+		if !ok {
+			// a single value is coerced into a list
+			return s.resolveRefs(t.Elem, val)
+		}
+		resolved := make([]any, len(list))
+		for i, v := range list {
+			var err error
+			resolved[i], err = s.resolveRefs(t.Elem, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	}
+	switch x := val.(type) {
+	case string:
+		if !idproto.IsRef(x) {
+			return val, nil
+		}
+		idType, ok := s.scalars[t.NamedType].(IDType)
+		if !ok {
+			return val, nil
+		}
+		id, err := s.IDs.Resolve(x)
+		if err != nil {
+			return nil, err
+		}
+		// let the ID type decide which IDs it accepts, e.g. an interface's
+		// accepts the IDs of any object implementing it
+		if _, err := idType.DecodeInput(id); err != nil {
+			return nil, err
+		}
+		return id, nil
+	case map[string]any:
+		def, ok := s.typeDefs[t.NamedType]
+		if !ok {
+			return val, nil
+		}
+		fields := def.TypeDefinition().Fields
+		resolved := make(map[string]any, len(x))
+		for name, v := range x {
+			field := fields.ForName(name)
+			if field == nil {
+				resolved[name] = v
+				continue
+			}
+			var err error
+			resolved[name], err = s.resolveRefs(field.Type, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	default:
+		return val, nil
+	}
+}
+
 func (s *Server) toSelectable(chainedID *idproto.ID, val Typed) (Object, error) {
 	if sel, ok := val.(Object); ok {
 		// We always support returning something that's already Selectable, e.g. an
@@ -612,6 +893,9 @@ func (s *Server) toSelectable(chainedID *idproto.ID, val Typed) (Object, error)
 func (s *Server) parseASTSelections(ctx context.Context, gqlOp *graphql.OperationContext, self *ast.Type, astSels ast.SelectionSet) ([]Selection, error) {
 	vars := gqlOp.Variables
 
+	// resolve references to IDs through this server's store
+	ctx = serverToContext(ctx, s)
+
 	class := s.objects[self.Name()]
 	if class == nil {
 		return nil, fmt.Errorf("parseASTSelections: not an Object type: %q", self.Name())
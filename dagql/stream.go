@@ -0,0 +1,137 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Stream is a field result that yields values over time, such as chunks of
+// logs. Only fields of the subscription root may return a Stream, and a
+// subscription sends a response for each of its values.
+//
+// Fields returning a Stream should be marked Impure, since a Stream can only
+// be consumed once.
+type Stream[T Typed] struct {
+	// Next blocks until the next value is available, returning io.EOF once
+	// there are no more.
+	Next func(context.Context) (T, error)
+}
+
+var _ Typed = Stream[String]{}
+
+// Subscribe installs root as the root of the server's subscription
+// operations. Its fields are the only ones that may return a Stream, and must
+// be installed after calling Subscribe.
+func Subscribe[T Typed](srv *Server, root T) {
+	class := NewClass[T](ClassOpts[T]{
+		NoIDs: true,
+	})
+	srv.InstallObject(class)
+	srv.sub = Instance[T]{
+		Self:  root,
+		Class: class,
+	}
+}
+
+// NewStream returns a Stream that calls next to get each value.
+func NewStream[T Typed](next func(context.Context) (T, error)) Stream[T] {
+	return Stream[T]{Next: next}
+}
+
+// ChanStream returns a Stream that yields each value sent on ch, and ends
+// when ch is closed.
+func ChanStream[T Typed](ch <-chan T) Stream[T] {
+	return NewStream(func(ctx context.Context) (T, error) {
+		select {
+		case val, ok := <-ch:
+			if !ok {
+				var zero T
+				return zero, io.EOF
+			}
+			return val, nil
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
+
+// Type returns the type of each value in the stream.
+func (s Stream[T]) Type() *ast.Type {
+	var zero T
+	return zero.Type()
+}
+
+func (s Stream[T]) next(ctx context.Context) (Typed, error) {
+	return s.Next(ctx)
+}
+
+// streamer is implemented by Stream[T] for any T.
+type streamer interface {
+	Typed
+	next(context.Context) (Typed, error)
+}
+
+// subscription tracks the stream selected by a subscription operation.
+type subscription struct {
+	mu     sync.Mutex
+	stream streamer
+	slot   *streamSlot
+
+	// results are the resolved selections, containing the slot
+	results map[string]any
+}
+
+type subscriptionCtx struct{}
+
+// attach registers a stream selected while resolving the subscription,
+// returning the slot to place in the results in its stead.
+func (sub *subscription) attach(stream streamer) (*streamSlot, error) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.stream != nil {
+		return nil, fmt.Errorf("subscriptions must select exactly one stream")
+	}
+	sub.stream = stream
+	sub.slot = &streamSlot{}
+	return sub.slot, nil
+}
+
+// next waits for the next value of the stream and returns the results with
+// it in place, or io.EOF once the stream has ended.
+func (sub *subscription) next(ctx context.Context) ([]byte, error) {
+	val, err := sub.stream.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub.slot.val = val
+	return json.Marshal(sub.results)
+}
+
+// streamSlot holds the current value of a stream within the results of a
+// subscription.
+type streamSlot struct {
+	val Typed
+}
+
+func (slot *streamSlot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(slot.val)
+}
+
+// attachStream registers a stream with the subscription being resolved, or
+// returns an error if the stream was selected outside of a subscription.
+func attachStream(ctx context.Context, sel Selection, stream streamer) (any, error) {
+	sub, ok := ctx.Value(subscriptionCtx{}).(*subscription)
+	if !ok {
+		return nil, fmt.Errorf("%s can only be selected in a subscription", sel.Selector.Field)
+	}
+	if len(sel.Subselections) > 0 {
+		return nil, fmt.Errorf("cannot sub-select streams")
+	}
+	return sub.attach(stream)
+}
@@ -42,6 +42,8 @@ type ObjectType interface {
 	// ParseField parses the given field and returns a Selector and an expected
 	// return type.
 	ParseField(context.Context, *ast.Field, map[string]any) (Selector, *ast.Type, error)
+	// FieldSpec returns the spec of the named field.
+	FieldSpec(string) (FieldSpec, bool)
 	// Extend registers an additional field onto the type.
 	//
 	// Unlike natively added fields, the extended func is limited to the external
@@ -242,6 +244,12 @@ func (f Float) TypeDefinition() *ast.Definition {
 
 func (Float) DecodeInput(val any) (Input, error) {
 	switch x := val.(type) {
+	case int: // GraphQL coerces Int inputs to Float
+		return NewFloat(float64(x)), nil
+	case int32:
+		return NewFloat(float64(x)), nil
+	case int64:
+		return NewFloat(float64(x)), nil
 	case float32:
 		return NewFloat(float64(x)), nil
 	case float64:
@@ -550,6 +558,9 @@ func (i ID[T]) TypeDefinition() *ast.Definition {
 func (i ID[T]) DecodeInput(val any) (Input, error) {
 	switch x := val.(type) {
 	case *idproto.ID:
+		if err := i.checkType(x); err != nil {
+			return nil, err
+		}
 		return ID[T]{id: x, inner: i.inner}, nil
 	case string:
 		if err := (&i).Decode(x); err != nil {
@@ -609,18 +620,26 @@ func (i *ID[T]) Decode(str string) error {
 	if str == "" {
 		return fmt.Errorf("cannot decode empty string as ID")
 	}
-	expectedName := i.inner.Type().Name()
 	var idp idproto.ID
 	if err := idp.Decode(str); err != nil {
 		return err
 	}
+	if err := i.checkType(&idp); err != nil {
+		return err
+	}
+	i.id = &idp
+	return nil
+}
+
+// checkType returns an error if idp isn't the ID of a T.
+func (i ID[T]) checkType(idp *idproto.ID) error {
+	expectedName := i.inner.Type().Name()
 	if idp.Type == nil {
 		return fmt.Errorf("expected %q ID, got untyped ID", expectedName)
 	}
 	if idp.Type.NamedType != expectedName {
 		return fmt.Errorf("expected %q ID, got %s ID", expectedName, idp.Type.ToAST())
 	}
-	i.id = &idp
 	return nil
 }
 
@@ -0,0 +1,137 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/continuity/fs"
+	bkcache "github.com/moby/buildkit/cache"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/bklog"
+)
+
+// cacheDirMetadata is the ref metadata in which buildkit records the ID of the
+// cache mount a record belongs to, suffixed with ":" and the ID of the
+// mount's source ref, if it has one.
+const cacheDirMetadata = "cache-dir"
+
+// CacheMountLimits limit the contents of a persistent cache mount. Zero means
+// no limit.
+type CacheMountLimits struct {
+	// MaxSize is the size in bytes past which the contents are wiped.
+	MaxSize int64
+
+	// TTL is how long the contents are kept once they stop being used.
+	TTL time.Duration
+}
+
+// cacheMountLimits maps the IDs of cache mounts to their CacheMountLimits.
+var cacheMountLimits sync.Map
+
+// SetCacheMountLimits sets the limits enforced on the contents of the cache
+// mount with the given ID whenever an exec mounts it, replacing any set
+// before.
+func SetCacheMountLimits(id string, limits CacheMountLimits) {
+	cacheMountLimits.Store(id, limits)
+}
+
+// LimitCacheMounts wraps the worker's cache manager so that the limits set
+// with SetCacheMountLimits are enforced when an exec acquires a cache mount,
+// right before it's mounted.
+//
+// Contents that exceed their limits are wiped entirely rather than trimmed.
+// The limits are only checked when the mount is acquired, so an exec may grow
+// the contents past them, and a mount already shared with a running exec is
+// reused as is.
+func LimitCacheMounts(cm bkcache.Manager) bkcache.Manager {
+	return &limitedCacheManager{Manager: cm}
+}
+
+type limitedCacheManager struct {
+	bkcache.Manager
+}
+
+// GetMutable is called by buildkit to reuse the record of a cache mount for
+// an exec.
+func (cm *limitedCacheManager) GetMutable(ctx context.Context, id string, opts ...bkcache.RefOption) (bkcache.MutableRef, error) {
+	ref, err := cm.Manager.GetMutable(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	mountID, _, _ := strings.Cut(ref.GetString(cacheDirMetadata), ":")
+	if mountID == "" {
+		return ref, nil
+	}
+	limits, ok := cacheMountLimits.Load(mountID)
+	if !ok {
+		return ref, nil
+	}
+	if err := cm.enforceLimits(ctx, ref, limits.(CacheMountLimits)); err != nil {
+		ref.Release(context.TODO())
+		return nil, fmt.Errorf("enforce limits of cache mount %s: %w", mountID, err)
+	}
+	return ref, nil
+}
+
+// enforceLimits wipes the contents of the acquired cache mount record if they
+// haven't been used for longer than the TTL, or exceed the max size.
+func (cm *limitedCacheManager) enforceLimits(ctx context.Context, ref bkcache.MutableRef, limits CacheMountLimits) error {
+	var expired bool
+	if limits.TTL > 0 {
+		records, err := cm.Manager.DiskUsage(ctx, bkclient.DiskUsageInfo{
+			Filter: []string{"id==" + ref.ID()},
+		})
+		if err != nil {
+			return fmt.Errorf("disk usage: %w", err)
+		}
+		for _, record := range records {
+			if record.LastUsedAt != nil && time.Since(*record.LastUsedAt) > limits.TTL {
+				expired = true
+			}
+		}
+	}
+	if !expired && limits.MaxSize <= 0 {
+		return nil
+	}
+
+	mountable, err := ref.Mount(ctx, false, nil)
+	if err != nil {
+		return fmt.Errorf("mount: %w", err)
+	}
+	mounter := snapshot.LocalMounter(mountable)
+	dir, err := mounter.Mount()
+	if err != nil {
+		return fmt.Errorf("mount: %w", err)
+	}
+	defer mounter.Unmount()
+
+	if !expired {
+		// the size of a record in use isn't known to the cache manager, since
+		// it may be changing, so measure it
+		usage, err := fs.DiskUsage(ctx, dir)
+		if err != nil {
+			return fmt.Errorf("disk usage: %w", err)
+		}
+		if usage.Size <= limits.MaxSize {
+			return nil
+		}
+	}
+
+	bklog.G(ctx).Debugf("wiping contents of cache mount record %s exceeding its limits", ref.ID())
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("wipe: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("wipe: %w", err)
+		}
+	}
+	return nil
+}
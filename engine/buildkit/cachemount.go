@@ -0,0 +1,196 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// cacheMountIndex indexes the records of the cache mounts recorded with
+	// RecordCacheMount, so that they can be listed.
+	cacheMountIndex = "dagger.cachemount"
+
+	// cacheMountIDMetadata is the ref metadata recording the ID of the cache
+	// mount a record belongs to.
+	cacheMountIDMetadata = "dagger.cachemount.id"
+
+	// cacheMountKeyMetadata is the ref metadata recording the human-readable
+	// key of the cache volume a record belongs to.
+	cacheMountKeyMetadata = "dagger.cachemount.key"
+)
+
+// CacheMount is the disk usage of a persistent cache mount, summed across the
+// records backing it, e.g. one per PRIVATE or LOCKED user.
+type CacheMount struct {
+	// ID is the ID passed to llb.AsPersistentCacheDir.
+	ID string
+
+	// Key is the key of the cache volume, if it's been recorded.
+	Key string
+
+	Size       int64
+	LastUsedAt *time.Time
+	InUse      bool
+
+	recordIDs []string
+}
+
+// CacheMounts returns the persistent cache mounts recorded with
+// RecordCacheMount that are held by the worker, sorted by ID.
+func (c *Client) CacheMounts(ctx context.Context) ([]*CacheMount, error) {
+	mds, err := c.Worker.CacheManager().Search(ctx, cacheMountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("search cache mounts: %w", err)
+	}
+	recordMounts := map[string]string{}
+	keys := map[string]string{}
+	for _, md := range mds {
+		id := md.GetString(cacheMountIDMetadata)
+		if id == "" {
+			continue
+		}
+		recordMounts[md.ID()] = id
+		if key := md.GetString(cacheMountKeyMetadata); key != "" {
+			keys[id] = key
+		}
+	}
+
+	byID, err := c.cacheMountUsage(ctx, recordMounts, keys)
+	if err != nil {
+		return nil, err
+	}
+	mnts := make([]*CacheMount, 0, len(byID))
+	for _, mnt := range byID {
+		mnts = append(mnts, mnt)
+	}
+	sort.Slice(mnts, func(i, j int) bool {
+		return mnts[i].ID < mnts[j].ID
+	})
+	return mnts, nil
+}
+
+// CacheMount returns the usage of the cache mount with the given ID, which is
+// empty if the mount hasn't been used yet.
+func (c *Client) CacheMount(ctx context.Context, id string) (*CacheMount, error) {
+	mds, err := mounts.SearchCacheDir(ctx, c.Worker.CacheManager(), id)
+	if err != nil {
+		return nil, fmt.Errorf("search cache mount %s: %w", id, err)
+	}
+	recordMounts := map[string]string{}
+	keys := map[string]string{}
+	for _, md := range mds {
+		recordMounts[md.ID()] = id
+		if key := md.GetString(cacheMountKeyMetadata); key != "" {
+			keys[id] = key
+		}
+	}
+
+	byID, err := c.cacheMountUsage(ctx, recordMounts, keys)
+	if err != nil {
+		return nil, err
+	}
+	if mnt, ok := byID[id]; ok {
+		return mnt, nil
+	}
+	return &CacheMount{ID: id}, nil
+}
+
+// cacheMountUsage sums the disk usage of the given cache mount records, keyed
+// by record ID, into the cache mounts they belong to.
+func (c *Client) cacheMountUsage(ctx context.Context, recordMounts, keys map[string]string) (map[string]*CacheMount, error) {
+	byID := map[string]*CacheMount{}
+	if len(recordMounts) == 0 {
+		return byID, nil
+	}
+
+	records, err := c.Worker.CacheManager().DiskUsage(ctx, bkclient.DiskUsageInfo{
+		Filter: []string{"type==" + string(bkclient.UsageRecordTypeCacheMount)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("disk usage: %w", err)
+	}
+	for _, record := range records {
+		id, ok := recordMounts[record.ID]
+		if !ok {
+			continue
+		}
+		mnt, ok := byID[id]
+		if !ok {
+			mnt = &CacheMount{ID: id, Key: keys[id]}
+			byID[id] = mnt
+		}
+		mnt.Size += record.Size
+		mnt.InUse = mnt.InUse || record.InUse
+		if record.LastUsedAt != nil && (mnt.LastUsedAt == nil || record.LastUsedAt.After(*mnt.LastUsedAt)) {
+			mnt.LastUsedAt = record.LastUsedAt
+		}
+		mnt.recordIDs = append(mnt.recordIDs, record.ID)
+	}
+	return byID, nil
+}
+
+// RecordCacheMount records the ID of the cache mount and the human-readable
+// key of the cache volume backing it in the metadata of the mount's records,
+// so that they can be listed by CacheMounts.
+func (c *Client) RecordCacheMount(ctx context.Context, id, key string) error {
+	mds, err := mounts.SearchCacheDir(ctx, c.Worker.CacheManager(), id)
+	if err != nil {
+		return fmt.Errorf("search cache mount %s: %w", id, err)
+	}
+	for _, md := range mds {
+		if md.GetString(cacheMountIDMetadata) != id {
+			if err := md.SetString(cacheMountIDMetadata, id, cacheMountIndex); err != nil {
+				return fmt.Errorf("set cache mount id: %w", err)
+			}
+		}
+		if md.GetString(cacheMountKeyMetadata) != key {
+			if err := md.SetString(cacheMountKeyMetadata, key, ""); err != nil {
+				return fmt.Errorf("set cache mount key: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// PruneCacheMount removes the records of the cache mount with the given ID
+// that are not in use, returning the number of bytes freed.
+func (c *Client) PruneCacheMount(ctx context.Context, id string) (int64, error) {
+	mnt, err := c.CacheMount(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if len(mnt.recordIDs) == 0 {
+		return 0, nil
+	}
+
+	filters := make([]string, 0, len(mnt.recordIDs))
+	for _, recordID := range mnt.recordIDs {
+		filters = append(filters, "id=="+recordID)
+	}
+
+	ch := make(chan bkclient.UsageInfo)
+	var freed int64
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		for record := range ch {
+			freed += record.Size
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		defer close(ch)
+		return c.Worker.CacheManager().Prune(ctx, ch, bkclient.PruneInfo{
+			Filter: filters,
+		})
+	})
+	if err := eg.Wait(); err != nil {
+		return 0, fmt.Errorf("prune cache mount %s: %w", id, err)
+	}
+	return freed, nil
+}
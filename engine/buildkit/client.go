@@ -227,44 +227,10 @@ func (c *Client) Solve(ctx context.Context, req bkgw.SolveRequest) (_ *Result, r
 
 	// include exec metadata that isn't included in the cache key
 	if req.Definition != nil && req.Definition.Def != nil {
-		clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+		req.Definition, err = withExecMetadata(ctx, req.Definition)
 		if err != nil {
 			return nil, err
 		}
-
-		dag, err := DefToDAG(req.Definition)
-		if err != nil {
-			return nil, err
-		}
-		if err := dag.Walk(func(dag *OpDAG) error {
-			execOp, ok := dag.AsExec()
-			if !ok {
-				return nil
-			}
-			if execOp.Meta == nil {
-				execOp.Meta = &bksolverpb.Meta{}
-			}
-			if execOp.Meta.ProxyEnv == nil {
-				execOp.Meta.ProxyEnv = &bksolverpb.ProxyEnv{}
-			}
-			execMeta := ContainerExecUncachedMetadata{
-				ParentClientIDs: clientMetadata.ClientIDs(),
-				ServerID:        clientMetadata.ServerID,
-			}
-			var err error
-			execOp.Meta.ProxyEnv.FtpProxy, err = execMeta.ToPBFtpProxyVal()
-			if err != nil {
-				return err
-			}
-			return nil
-		}); err != nil {
-			return nil, err
-		}
-		newDef, err := dag.Marshal()
-		if err != nil {
-			return nil, err
-		}
-		req.Definition = newDef
 	}
 
 	llbRes, err := c.llbBridge.Solve(ctx, req, c.ID())
@@ -292,6 +258,46 @@ func (c *Client) Solve(ctx context.Context, req bkgw.SolveRequest) (_ *Result, r
 	return res, nil
 }
 
+// withExecMetadata returns def with the metadata of the current client set on
+// each exec op. The metadata isn't included in the cache key, but it changes
+// the digest of the op.
+func withExecMetadata(ctx context.Context, def *bksolverpb.Definition) (*bksolverpb.Definition, error) {
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dag, err := DefToDAG(def)
+	if err != nil {
+		return nil, err
+	}
+	if err := dag.Walk(func(dag *OpDAG) error {
+		execOp, ok := dag.AsExec()
+		if !ok {
+			return nil
+		}
+		if execOp.Meta == nil {
+			execOp.Meta = &bksolverpb.Meta{}
+		}
+		if execOp.Meta.ProxyEnv == nil {
+			execOp.Meta.ProxyEnv = &bksolverpb.ProxyEnv{}
+		}
+		execMeta := ContainerExecUncachedMetadata{
+			ParentClientIDs: clientMetadata.ClientIDs(),
+			ServerID:        clientMetadata.ServerID,
+		}
+		var err error
+		execOp.Meta.ProxyEnv.FtpProxy, err = execMeta.ToPBFtpProxyVal()
+		if err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return dag.Marshal()
+}
+
 func (c *Client) ResolveImageConfig(ctx context.Context, ref string, opt llb.ResolveImageConfigOpt) (string, digest.Digest, []byte, error) {
 	ctx, cancel, err := c.withClientCloseCancel(ctx)
 	if err != nil {
@@ -0,0 +1,171 @@
+package buildkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/containerd/continuity/fs"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/snapshot"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+// ContentDigest returns a sha256 digest of the file or directory at the given
+// path in the result of def.
+//
+// The digest of a regular file with excludeMetadata set is the digest of its
+// contents, i.e. the same as sha256sum. The digest of a directory covers the
+// name, type and contents of every entry below it, but not the directory
+// itself, so it doesn't matter where the directory was built. Unless
+// excludeMetadata is set, permissions, ownership and modification times
+// (except for symlinks) are included too.
+func (c *Client) ContentDigest(
+	ctx context.Context,
+	def *bksolverpb.Definition,
+	p string,
+	excludeMetadata bool,
+) (digest.Digest, error) {
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return "", err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return "", err
+	}
+
+	mountable, err := ref.getMountable(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mountable == nil {
+		// empty directory, i.e. llb.Scratch()
+		if clean := path.Clean(p); clean == "." || clean == "/" {
+			return digest.FromBytes(nil), nil
+		}
+		return "", fmt.Errorf("%s: no such file or directory", p)
+	}
+
+	mounter := snapshot.LocalMounter(mountable)
+	mountPath, err := mounter.Mount()
+	if err != nil {
+		return "", fmt.Errorf("failed to mount: %w", err)
+	}
+	defer mounter.Unmount()
+
+	fullPath, err := fs.RootPath(mountPath, p)
+	if err != nil {
+		return "", err
+	}
+	return digestPath(fullPath, excludeMetadata)
+}
+
+// digestPath computes the digest described in ContentDigest for a path on the
+// local filesystem.
+func digestPath(root string, excludeMetadata bool) (digest.Digest, error) {
+	stat, err := fsutil.Stat(root)
+	if err != nil {
+		return "", err
+	}
+
+	mode := iofs.FileMode(stat.Mode)
+	if !mode.IsDir() {
+		if mode.IsRegular() && excludeMetadata {
+			return fileDigest(root)
+		}
+		// only the entry itself is hashed, not its name
+		stat.Path = ""
+		h := sha256.New()
+		if err := digestEntry(h, root, stat, excludeMetadata); err != nil {
+			return "", err
+		}
+		return digest.NewDigest(digest.SHA256, h), nil
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(root, func(fullPath string, _ iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fullPath == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, fullPath)
+		if err != nil {
+			return err
+		}
+		stat, err := fsutil.Stat(fullPath)
+		if err != nil {
+			return err
+		}
+		stat.Path = filepath.ToSlash(rel)
+		return digestEntry(h, fullPath, stat, excludeMetadata)
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+// digestEntry writes a single NUL-separated record describing an entry to h.
+func digestEntry(h hash.Hash, fullPath string, stat *fstypes.Stat, excludeMetadata bool) error {
+	mode := iofs.FileMode(stat.Mode)
+
+	var kind string
+	switch {
+	case mode.IsRegular():
+		kind = "file"
+	case mode.IsDir():
+		kind = "dir"
+	case mode&iofs.ModeSymlink != 0:
+		kind = "symlink"
+	default:
+		kind = "other"
+	}
+	fmt.Fprintf(h, "%s\x00%s\x00", stat.Path, kind)
+
+	if !excludeMetadata {
+		perms := mode & (iofs.ModePerm | iofs.ModeSetuid | iofs.ModeSetgid | iofs.ModeSticky)
+		fmt.Fprintf(h, "%o\x00%d\x00%d\x00", perms, stat.Uid, stat.Gid)
+		// symlink mtimes are rarely preserved by copies, so leave them out
+		if kind != "symlink" {
+			fmt.Fprintf(h, "%d\x00", stat.ModTime)
+		}
+	}
+
+	switch kind {
+	case "file":
+		dgst, err := fileDigest(fullPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00", dgst)
+	case "symlink":
+		fmt.Fprintf(h, "%s\x00", stat.Linkname)
+	}
+	return nil
+}
+
+func fileDigest(fullPath string) (digest.Digest, error) {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
@@ -0,0 +1,76 @@
+package buildkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestPath(t *testing.T) {
+	writeTree := func(t *testing.T, mtime time.Time) string {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0o644))
+		require.NoError(t, os.Symlink("../a.txt", filepath.Join(dir, "sub", "link")))
+		for _, p := range []string{"a.txt", "sub/b.txt", "sub"} {
+			require.NoError(t, os.Chtimes(filepath.Join(dir, p), mtime, mtime))
+		}
+		return dir
+	}
+
+	t.Run("file contents", func(t *testing.T) {
+		dir := writeTree(t, time.Unix(1, 0))
+		dgst, err := digestPath(filepath.Join(dir, "a.txt"), true)
+		require.NoError(t, err)
+		require.Equal(t, digest.FromString("a"), dgst)
+
+		withMeta, err := digestPath(filepath.Join(dir, "a.txt"), false)
+		require.NoError(t, err)
+		require.NotEqual(t, dgst, withMeta)
+	})
+
+	t.Run("identical trees", func(t *testing.T) {
+		a := writeTree(t, time.Unix(1, 0))
+		b := writeTree(t, time.Unix(1, 0))
+
+		dgstA, err := digestPath(a, false)
+		require.NoError(t, err)
+		dgstB, err := digestPath(b, false)
+		require.NoError(t, err)
+		require.Equal(t, dgstA, dgstB)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		a := writeTree(t, time.Unix(1, 0))
+		b := writeTree(t, time.Unix(2, 0))
+
+		dgstA, err := digestPath(a, false)
+		require.NoError(t, err)
+		dgstB, err := digestPath(b, false)
+		require.NoError(t, err)
+		require.NotEqual(t, dgstA, dgstB)
+
+		dgstA, err = digestPath(a, true)
+		require.NoError(t, err)
+		dgstB, err = digestPath(b, true)
+		require.NoError(t, err)
+		require.Equal(t, dgstA, dgstB)
+	})
+
+	t.Run("contents", func(t *testing.T) {
+		a := writeTree(t, time.Unix(1, 0))
+		b := writeTree(t, time.Unix(1, 0))
+		require.NoError(t, os.WriteFile(filepath.Join(b, "sub", "b.txt"), []byte("c"), 0o644))
+
+		dgstA, err := digestPath(a, true)
+		require.NoError(t, err)
+		dgstB, err := digestPath(b, true)
+		require.NoError(t, err)
+		require.NotEqual(t, dgstA, dgstB)
+	})
+}
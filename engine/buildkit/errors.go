@@ -1,16 +1,40 @@
 package buildkit
 
+import "fmt"
+
+// ExecErrorReason is why an exec was stopped, if it didn't simply exit with
+// a non-zero code.
+type ExecErrorReason string
+
+const (
+	// ExecErrorReasonTimeout is set when the exec ran past its timeout and was
+	// killed.
+	ExecErrorReasonTimeout ExecErrorReason = "TIMEOUT"
+
+	// ExecErrorReasonOOMKilled is set when the exec ran past its memory limit
+	// and was killed.
+	ExecErrorReasonOOMKilled ExecErrorReason = "OOM_KILLED"
+)
+
 // ExecError is an error that occurred while executing an `Op_Exec`.
 type ExecError struct {
 	original error
 	Cmd      []string
 	ExitCode int
+	Reason   ExecErrorReason
 	Stdout   string
 	Stderr   string
 }
 
 func (e *ExecError) Error() string {
-	return e.original.Error()
+	switch e.Reason {
+	case ExecErrorReasonTimeout:
+		return fmt.Sprintf("command timed out: %s", e.original)
+	case ExecErrorReasonOOMKilled:
+		return fmt.Sprintf("command was killed for exceeding its memory limit: %s", e.original)
+	default:
+		return e.original.Error()
+	}
 }
 
 func (e *ExecError) Unwrap() error {
@@ -18,11 +42,15 @@ func (e *ExecError) Unwrap() error {
 }
 
 func (e *ExecError) Extensions() map[string]interface{} {
-	return map[string]interface{}{
+	ext := map[string]interface{}{
 		"_type":    "EXEC_ERROR",
 		"cmd":      e.Cmd,
 		"exitCode": e.ExitCode,
 		"stdout":   e.Stdout,
 		"stderr":   e.Stderr,
 	}
+	if e.Reason != "" {
+		ext["reason"] = string(e.Reason)
+	}
+	return ext
 }
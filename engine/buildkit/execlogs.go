@@ -0,0 +1,99 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	bkclient "github.com/moby/buildkit/client"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/opencontainers/go-digest"
+)
+
+// ExecOutputEnd reports how the output followed by FollowExecOutput ended.
+type ExecOutputEnd struct {
+	// Cached is true if the op was cached, so none of its output was written.
+	Cached bool
+
+	// Clipped is true if the output exceeded the progress log limits, so only
+	// a prefix of it was written.
+	Clipped bool
+}
+
+// clippedLogPrefix starts the message that buildkit writes to a log stream in
+// place of the output exceeding its limits.
+var clippedLogPrefix = []byte("\n[output clipped, log limit ")
+
+// FollowExecOutput calls fn with each chunk of output written to the given
+// stream (1 for stdout, 2 for stderr) by the exec op that def outputs, as
+// reported in the progress of this client's solves. It returns once the op's
+// progress reports it as completed, or when ctx is canceled.
+//
+// Output that was already written is replayed first. Nothing is written if
+// the op is cached, and nothing past the point where the output was clipped,
+// as reported by the returned ExecOutputEnd.
+func (c *Client) FollowExecOutput(ctx context.Context, def *bksolverpb.Definition, stream int, fn func([]byte) error) (ExecOutputEnd, error) {
+	var end ExecOutputEnd
+
+	dgst, err := execVertexDigest(ctx, def)
+	if err != nil {
+		return end, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	statuses := make(chan *bkclient.SolveStatus, 100)
+	go c.WriteStatusesTo(ctx, statuses)
+	defer func() {
+		cancel()
+		// the statuses are sent until the channel is closed
+		for range statuses {
+		}
+	}()
+
+	for status := range statuses {
+		for _, log := range status.Logs {
+			if log.Vertex != dgst || log.Stream != stream || end.Clipped {
+				continue
+			}
+			data := log.Data
+			if i := bytes.Index(data, clippedLogPrefix); i != -1 {
+				// anything written after this isn't contiguous
+				data = data[:i]
+				end.Clipped = true
+			}
+			if len(data) == 0 {
+				continue
+			}
+			if err := fn(data); err != nil {
+				return end, err
+			}
+		}
+		for _, vtx := range status.Vertexes {
+			if vtx.Digest == dgst && vtx.Completed != nil {
+				end.Cached = vtx.Cached
+				return end, nil
+			}
+		}
+	}
+	return end, ctx.Err()
+}
+
+// execVertexDigest returns the digest that the progress of the exec op that
+// def outputs is reported under, which includes the metadata added by Solve.
+func execVertexDigest(ctx context.Context, def *bksolverpb.Definition) (digest.Digest, error) {
+	def, err := withExecMetadata(ctx, def)
+	if err != nil {
+		return "", err
+	}
+	dag, err := DefToDAG(def)
+	if err != nil {
+		return "", err
+	}
+	if dag.GetOp() == nil && len(dag.Inputs) == 1 {
+		dag = dag.Inputs[0]
+	}
+	if _, ok := dag.AsExec(); !ok {
+		return "", fmt.Errorf("definition does not output an exec")
+	}
+	return *dag.OpDigest, nil
+}
@@ -0,0 +1,156 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/dagger/dagger/engine"
+	"github.com/docker/docker/pkg/idtools"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/executor"
+	resourcestypes "github.com/moby/buildkit/executor/resources/types"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/moby/buildkit/util/progress"
+)
+
+// execMetricsInterval is how often the metrics written by the shim are
+// checked for a new sample while an exec runs.
+const execMetricsInterval = 2 * time.Second
+
+// ReportExecMetrics wraps the worker's executor so that the resources used by
+// each Dagger exec, as sampled by the shim, are written to the progress of
+// the exec's vertex while it runs and once it exits. Each exec gets a
+// directory under root for the shim to write the samples to.
+func ReportExecMetrics(exec executor.Executor, root string) (executor.Executor, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, err
+	}
+	return &metricsExecutor{Executor: exec, root: root}, nil
+}
+
+type metricsExecutor struct {
+	executor.Executor
+	root string
+}
+
+func (e *metricsExecutor) Run(ctx context.Context, id string, rootfs executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) (resourcestypes.Recorder, error) {
+	var isDaggerExec bool
+	for _, mnt := range mounts {
+		if mnt.Dest == MetaMountDestPath {
+			isDaggerExec = true
+			break
+		}
+	}
+	if !isDaggerExec {
+		return e.Executor.Run(ctx, id, rootfs, mounts, process, started)
+	}
+
+	dir, err := os.MkdirTemp(e.root, "exec-")
+	if err != nil {
+		return nil, fmt.Errorf("create exec metrics dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	// the shim runs as the exec's user
+	if err := os.Chmod(dir, 0o777); err != nil {
+		return nil, fmt.Errorf("create exec metrics dir: %w", err)
+	}
+	mounts = append(mounts[:len(mounts):len(mounts)], executor.Mount{
+		Src:  hostDir(dir),
+		Dest: engine.ExecMetricsMountPath,
+	})
+
+	pw, _, _ := progress.NewFromContext(ctx)
+	defer pw.Close()
+	follower := &execMetricsFollower{
+		path: filepath.Join(dir, engine.ExecMetricsFile),
+		pw:   pw,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go follower.run(ctx)
+	defer follower.Stop(ctx)
+
+	return e.Executor.Run(ctx, id, rootfs, mounts, process, started)
+}
+
+// execMetricsFollower writes each new sample written by the shim to the
+// progress of the exec's vertex.
+type execMetricsFollower struct {
+	path string
+	pw   progress.Writer
+	last []byte
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (f *execMetricsFollower) run(ctx context.Context) {
+	defer close(f.done)
+	ticker := time.NewTicker(execMetricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			f.report(ctx)
+		}
+	}
+}
+
+// Stop stops following the samples and reports the final one, which the shim
+// writes before exiting.
+func (f *execMetricsFollower) Stop(ctx context.Context) {
+	close(f.stop)
+	<-f.done
+	f.report(ctx)
+}
+
+func (f *execMetricsFollower) report(ctx context.Context) {
+	sample, err := os.ReadFile(f.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			bklog.G(ctx).WithError(err).Debug("failed to read exec metrics")
+		}
+		return
+	}
+	if len(sample) == 0 || bytes.Equal(sample, f.last) {
+		return
+	}
+	f.last = sample
+	err = f.pw.Write(identity.NewID(), bkclient.VertexLog{
+		Stream: engine.ExecMetricsStream,
+		Data:   sample,
+	})
+	if err != nil {
+		bklog.G(ctx).WithError(err).Debug("failed to report exec metrics")
+	}
+}
+
+// hostDir is a directory of the engine's host bind mounted into an exec.
+type hostDir string
+
+func (dir hostDir) Mount(context.Context, bool) (snapshot.Mountable, error) {
+	return hostDirMounts(dir), nil
+}
+
+type hostDirMounts string
+
+func (dir hostDirMounts) Mount() ([]mount.Mount, func() error, error) {
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  string(dir),
+		Options: []string{"rbind"},
+	}}, func() error { return nil }, nil
+}
+
+func (hostDirMounts) IdentityMapping() *idtools.IdentityMapping {
+	return nil
+}
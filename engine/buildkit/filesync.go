@@ -3,6 +3,7 @@ package buildkit
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,9 +11,11 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/containerd/continuity/fs"
 	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/session"
 	"github.com/dagger/dagger/engine/sources/blob"
 	cacheconfig "github.com/moby/buildkit/cache/config"
 	bkclient "github.com/moby/buildkit/client"
@@ -25,7 +28,10 @@ import (
 	"github.com/moby/buildkit/util/compression"
 	bkworker "github.com/moby/buildkit/worker"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tonistiigi/fsutil"
 	"github.com/vito/progrock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (c *Client) LocalImport(
@@ -207,16 +213,112 @@ func (c *Client) ReadCallerHostFile(ctx context.Context, path string) ([]byte, e
 	defer diffCopyClient.CloseSend()
 	msg := filesync.BytesMessage{}
 	err = diffCopyClient.RecvMsg(&msg)
+	if isNotExist(err) {
+		return nil, fmt.Errorf("%s: %w", path, os.ErrNotExist)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to receive file bytes message: %s", err)
 	}
 	return msg.Data, nil
 }
 
+// isNotExist returns true if reading a file from the caller's host failed
+// because it doesn't exist.
+//
+// Clients that predate the NotFound status code send the os error as a plain
+// message, so fall back to matching on it.
+func isNotExist(err error) bool {
+	if err == nil {
+		return false
+	}
+	if status.Code(err) == codes.NotFound {
+		return true
+	}
+	return strings.Contains(status.Convert(err).Message(), syscall.ENOENT.Error())
+}
+
+const (
+	// DaggerIgnoreFilename is the name of the file whose patterns are excluded
+	// when loading a directory from the host in daggerignore mode, such as when
+	// loading a module's source.
+	DaggerIgnoreFilename = ".daggerignore"
+
+	// GitIgnoreFilename is the name of the file whose patterns are excluded
+	// when loading a directory from the host in gitignore mode.
+	GitIgnoreFilename = ".gitignore"
+)
+
+// ReadIgnorePatterns reads the ignore files with the given names at the root
+// of srcPath on the caller's host and returns their patterns as exclude
+// patterns. The files use .gitignore syntax; missing files are skipped.
+//
+// Only the files at the root are read: ignore files in subdirectories are not
+// honored.
+func (c *Client) ReadIgnorePatterns(ctx context.Context, srcPath string, names ...string) ([]string, error) {
+	var patterns []string
+	for _, name := range names {
+		dt, err := c.ReadCallerHostFile(ctx, path.Join(srcPath, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		patterns = append(patterns, ignoreFilePatterns(dt)...)
+	}
+	return patterns, nil
+}
+
+// ignoreFilePatterns converts the contents of a file in .gitignore syntax to
+// exclude patterns.
+//
+// Directory-only patterns (with a trailing slash) match files too, since
+// exclude patterns can't tell the difference.
+func ignoreFilePatterns(dt []byte) []string {
+	var patterns []string
+	for _, line := range strings.Split(string(dt), "\n") {
+		line = strings.TrimRight(line, "\r ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var negate bool
+		switch {
+		case strings.HasPrefix(line, "!"):
+			negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, "\\!"), strings.HasPrefix(line, "\\#"):
+			line = line[1:]
+		}
+
+		line = strings.TrimSuffix(line, "/")
+		if strings.HasPrefix(line, "/") {
+			// anchored to the root
+			line = strings.TrimPrefix(line, "/")
+		} else if !strings.Contains(line, "/") {
+			// matches at any depth
+			line = "**/" + line
+		}
+		if line == "" || line == "**/" {
+			continue
+		}
+
+		if negate {
+			line = "!" + line
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// LocalDirExport writes the result of def to destPath on the caller's host.
+// If wipe is set, files under destPath that aren't part of the result are
+// removed.
 func (c *Client) LocalDirExport(
 	ctx context.Context,
 	def *bksolverpb.Definition,
 	destPath string,
+	wipe bool,
 ) (rerr error) {
 	ctx = bklog.WithLogger(ctx, bklog.G(ctx).WithField("export_path", destPath))
 	bklog.G(ctx).Debug("exporting local dir")
@@ -266,6 +368,7 @@ func (c *Client) LocalDirExport(
 	ctx = engine.LocalExportOpts{
 		DestClientID: clientMetadata.ClientID,
 		Path:         destPath,
+		Wipe:         wipe,
 	}.AppendToOutgoingContext(ctx)
 
 	_, descRef, err := expInstance.Export(ctx, cacheRes, nil, clientMetadata.ClientID)
@@ -278,6 +381,112 @@ func (c *Client) LocalDirExport(
 	return nil
 }
 
+// LocalDirExportDryRun returns the changes that LocalDirExport would make to
+// destPath on the caller's host, without making them.
+//
+// Each change is a path relative to destPath prefixed with "A " if it would
+// be added, "M " if it would be modified or "D " if it would be removed.
+func (c *Client) LocalDirExportDryRun(
+	ctx context.Context,
+	def *bksolverpb.Definition,
+	destPath string,
+	wipe bool,
+) (_ []string, rerr error) {
+	ctx = bklog.WithLogger(ctx, bklog.G(ctx).WithField("export_path", destPath))
+	bklog.G(ctx).Debug("dry running local dir export")
+	defer func() {
+		lg := bklog.G(ctx)
+		if rerr != nil {
+			lg = lg.WithError(rerr)
+		}
+		lg.Debug("finished dry running local dir export")
+	}()
+
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	destPath = path.Clean(destPath)
+	if destPath == ".." || strings.HasPrefix(destPath, "../") {
+		return nil, fmt.Errorf("path %q escapes workdir; use an absolute path instead", destPath)
+	}
+
+	res, err := c.Solve(ctx, bkgw.SolveRequest{Definition: def, Evaluate: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to solve for local export: %s", err)
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get single ref: %s", err)
+	}
+
+	mountable, err := ref.getMountable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mountable: %s", err)
+	}
+	var srcPath string
+	if mountable == nil {
+		// empty directory, i.e. llb.Scratch()
+		srcPath, err = os.MkdirTemp("", "dagger-export-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(srcPath)
+	} else {
+		mounter := snapshot.LocalMounter(mountable)
+		srcPath, err = mounter.Mount()
+		if err != nil {
+			return nil, fmt.Errorf("failed to mount: %s", err)
+		}
+		defer mounter.Unmount()
+	}
+	srcFS, err := fsutil.NewFS(srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester session ID: %s", err)
+	}
+
+	ctx = engine.LocalExportOpts{
+		DestClientID: clientMetadata.ClientID,
+		Path:         destPath,
+		Wipe:         wipe,
+	}.AppendToOutgoingContext(ctx)
+
+	clientCaller, err := c.SessionManager.Get(ctx, clientMetadata.ClientID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester session: %s", err)
+	}
+	if !clientCaller.Supports(session.ExportDryRunDiffCopyMethod) {
+		return nil, fmt.Errorf("client does not support export dry runs; upgrade it to use this feature")
+	}
+	diffCopyClient, err := session.NewExportDryRunClient(clientCaller.Conn()).DiffCopy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create diff copy client: %s", err)
+	}
+	defer diffCopyClient.CloseSend()
+
+	if err := fsutil.Send(ctx, diffCopyClient, srcFS, nil); err != nil {
+		return nil, fmt.Errorf("failed to send dir: %s", err)
+	}
+
+	// the receiver replies with the changes once it has compared the trees
+	msg := filesync.BytesMessage{}
+	if err := diffCopyClient.RecvMsg(&msg); err != nil {
+		return nil, fmt.Errorf("failed to receive changes: %s", err)
+	}
+	var changes []string
+	if err := json.Unmarshal(msg.Data, &changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes: %s", err)
+	}
+	return changes, nil
+}
+
 func (c *Client) LocalFileExport(
 	ctx context.Context,
 	def *bksolverpb.Definition,
@@ -0,0 +1,43 @@
+package buildkit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIgnoreFilePatterns(t *testing.T) {
+	dt := []byte(`# comment
+
+node_modules/
+/dist
+*.log
+!keep.log
+build/output
+\#literal
+\!bang
+trailing   ` + "\r\n")
+
+	require.Equal(t, []string{
+		"**/node_modules",
+		"dist",
+		"**/*.log",
+		"!**/keep.log",
+		"build/output",
+		"**/#literal",
+		"**/!bang",
+		"**/trailing",
+	}, ignoreFilePatterns(dt))
+}
+
+func TestIsNotExist(t *testing.T) {
+	require.False(t, isNotExist(nil))
+	require.True(t, isNotExist(status.Error(codes.NotFound, "read file: gone")))
+	// clients that predate the NotFound status code
+	require.True(t, isNotExist(status.Error(codes.Unknown, "read file: open /src/.daggerignore: no such file or directory")))
+	require.False(t, isNotExist(status.Error(codes.Unknown, "read file: open /src/.daggerignore: permission denied")))
+	require.False(t, isNotExist(errors.New("connection reset")))
+}
@@ -2,10 +2,12 @@ package buildkit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/containerd/containerd/platforms"
+	"github.com/dagger/dagger/engine"
 	bkclient "github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/frontend"
@@ -169,6 +171,22 @@ func BK2Progrock(event *bkclient.SolveStatus) *progrock.StatusUpdate {
 	}
 
 	for _, s := range event.Logs {
+		if s.Stream == engine.ExecMetricsStream {
+			var metrics engine.ExecMetrics
+			if err := json.Unmarshal(s.Data, &metrics); err != nil {
+				continue
+			}
+			payload, err := metrics.Proto()
+			if err != nil {
+				continue
+			}
+			status.Metas = append(status.Metas, &progrock.VertexMeta{
+				Vertex: s.Vertex.String(),
+				Name:   engine.ExecMetricsMeta,
+				Data:   payload,
+			})
+			continue
+		}
 		status.Logs = append(status.Logs, &progrock.VertexLog{
 			Vertex:    s.Vertex.String(),
 			Stream:    progrock.LogStream(s.Stream),
@@ -0,0 +1,37 @@
+package buildkit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dagger/dagger/engine"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBK2ProgrockExecMetrics(t *testing.T) {
+	sample, err := json.Marshal(engine.ExecMetrics{CPUTime: time.Second, MemoryPeak: 42})
+	require.NoError(t, err)
+
+	vtx := digest.FromString("exec")
+	status := BK2Progrock(&bkclient.SolveStatus{
+		Logs: []*bkclient.VertexLog{
+			{Vertex: vtx, Stream: 2, Data: []byte("some output\n")},
+			{Vertex: vtx, Stream: engine.ExecMetricsStream, Data: sample},
+			{Vertex: vtx, Stream: engine.ExecMetricsStream, Data: []byte("not json")},
+		},
+	})
+
+	// samples are recorded as metas rather than logs, and invalid ones dropped
+	require.Len(t, status.Logs, 1)
+	require.Equal(t, "some output\n", string(status.Logs[0].Data))
+
+	require.Len(t, status.Metas, 1)
+	require.Equal(t, vtx.String(), status.Metas[0].Vertex)
+	require.Equal(t, engine.ExecMetricsMeta, status.Metas[0].Name)
+	metrics, err := engine.ExecMetricsFromProto(status.Metas[0].Data)
+	require.NoError(t, err)
+	require.Equal(t, engine.ExecMetrics{CPUTime: time.Second, MemoryPeak: 42}, metrics)
+}
@@ -324,10 +324,16 @@ func wrapError(ctx context.Context, baseErr error, sessionID string) error {
 		}
 	}
 
+	exitReasonBytes, err := getExecMetaFile(ctx, mntable, "exitReason")
+	if err != nil {
+		return errors.Join(err, baseErr)
+	}
+
 	return &ExecError{
 		original: baseErr,
 		Cmd:      execOp.Exec.Meta.Args,
 		ExitCode: exitCode,
+		Reason:   ExecErrorReason(strings.TrimSpace(string(exitReasonBytes))),
 		Stdout:   strings.TrimSpace(string(stdoutBytes)),
 		Stderr:   strings.TrimSpace(string(stderrBytes)),
 	}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// localStore is a blobStore in a plain directory, e.g. an NFS mount shared by
+// several CI runners.
+type localStore struct {
+	root string
+}
+
+var _ blobStore = localStore{}
+
+func newLocalStore(root string) (localStore, error) {
+	if !filepath.IsAbs(root) {
+		return localStore{}, fmt.Errorf("cache directory must be absolute: %s", root)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return localStore{}, err
+	}
+	return localStore{root: root}, nil
+}
+
+func (s localStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s localStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return os.ReadFile(s.path(key))
+}
+
+func (s localStore) Put(ctx context.Context, key string, data []byte) error {
+	return writeFileAtomic(s.path(key), bytes.NewReader(data))
+}
+
+func (s localStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s localStore) DownloadURL(ctx context.Context, key string) (string, error) {
+	return s.fileURL(key), nil
+}
+
+func (s localStore) UploadURL(ctx context.Context, key string) (string, map[string]string, error) {
+	return s.fileURL(key), nil, nil
+}
+
+func (s localStore) fileURL(key string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(s.path(key))}).String()
+}
+
+// writeFileAtomic writes to a temporary file next to path and renames it into
+// place, so that readers on other engines never see partial content.
+func writeFileAtomic(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// fileTransport serves the file URLs handed out by a localStore to the
+// manager's HTTP client: GET reads a file, honoring "bytes=<offset>-"
+// ranges, and PUT writes one.
+type fileTransport struct{}
+
+var _ http.RoundTripper = fileTransport{}
+
+func (fileTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported scheme %q", req.URL.Scheme)
+	}
+	path := filepath.FromSlash(req.URL.Path)
+
+	switch req.Method {
+	case http.MethodGet:
+		f, err := os.Open(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fileResponse(req, http.StatusNotFound, nil), nil
+			}
+			return nil, err
+		}
+		if rng := req.Header.Get("Range"); rng != "" {
+			offset, err := parseRangeOffset(rng)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
+				f.Close()
+				return nil, err
+			}
+			return fileResponse(req, http.StatusPartialContent, f), nil
+		}
+		return fileResponse(req, http.StatusOK, f), nil
+	case http.MethodPut:
+		if req.Body == nil {
+			return nil, fmt.Errorf("missing body")
+		}
+		defer req.Body.Close()
+		if err := writeFileAtomic(path, req.Body); err != nil {
+			return nil, err
+		}
+		return fileResponse(req, http.StatusOK, nil), nil
+	default:
+		return fileResponse(req, http.StatusMethodNotAllowed, nil), nil
+	}
+}
+
+func fileResponse(req *http.Request, code int, body io.ReadCloser) *http.Response {
+	if body == nil {
+		body = io.NopCloser(strings.NewReader(""))
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode: code,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       body,
+		Request:    req,
+	}
+}
+
+// parseRangeOffset parses the "bytes=<offset>-" ranges sent by urlReaderAt.
+func parseRangeOffset(rng string) (int64, error) {
+	spec, ok := strings.CutPrefix(rng, "bytes=")
+	if !ok || !strings.HasSuffix(spec, "-") {
+		return 0, fmt.Errorf("unsupported range %q", rng)
+	}
+	return strconv.ParseInt(strings.TrimSuffix(spec, "-"), 10, 64)
+}
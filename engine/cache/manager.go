@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -41,9 +42,11 @@ type ManagerConfig struct {
 	ResultStore  solver.CacheResultStorage
 	Worker       worker.Worker
 	MountManager *mounts.MountManager
-	ServiceURL   string
-	Token        string
-	EngineID     string
+	// ServiceURL is either the URL of the hosted cache service, or a file://
+	// or s3:// URL of a self-hosted cache, which needs no Token.
+	ServiceURL string
+	Token      string
+	EngineID   string
 }
 
 const (
@@ -62,16 +65,40 @@ func NewManager(ctx context.Context, managerConfig ManagerConfig) (Manager, erro
 		httpClient:    &http.Client{},
 	}
 
-	if managerConfig.Token == "" {
-		return defaultCacheManager{m.localCache}, nil
+	serviceURL, err := url.Parse(managerConfig.ServiceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache service URL: %w", err)
 	}
-	bklog.G(ctx).Debugf("using cache service at %s", managerConfig.ServiceURL)
+	switch serviceURL.Scheme {
+	case "file":
+		// self-hosted in a local directory
+		bklog.G(ctx).Debugf("using local cache at %s", serviceURL.Path)
+		store, err := newLocalStore(serviceURL.Path)
+		if err != nil {
+			return nil, err
+		}
+		m.cacheClient = newStoreService(store)
+		m.httpClient = &http.Client{Transport: fileTransport{}}
+	case "s3":
+		// self-hosted in an S3-compatible object store
+		bklog.G(ctx).Debugf("using s3 cache at %s", serviceURL.Redacted())
+		store, err := newS3Store(ctx, serviceURL)
+		if err != nil {
+			return nil, err
+		}
+		m.cacheClient = newStoreService(store)
+	default:
+		if managerConfig.Token == "" {
+			return defaultCacheManager{m.localCache}, nil
+		}
+		bklog.G(ctx).Debugf("using cache service at %s", managerConfig.ServiceURL)
 
-	serviceClient, err := newClient(managerConfig.ServiceURL, managerConfig.Token)
-	if err != nil {
-		return nil, err
+		serviceClient, err := newClient(managerConfig.ServiceURL, managerConfig.Token)
+		if err != nil {
+			return nil, err
+		}
+		m.cacheClient = serviceClient
 	}
-	m.cacheClient = serviceClient
 	m.layerProvider = &layerProvider{
 		httpClient:  m.httpClient,
 		cacheClient: m.cacheClient,
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+const s3PresignExpiry = 1 * time.Hour
+
+// s3Store is a blobStore in a bucket of an S3-compatible object store.
+// Layers and cache mounts are transferred directly through presigned URLs.
+type s3Store struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+	prefix  string
+}
+
+var _ blobStore = s3Store{}
+
+// newS3Store configures a store from a URL like
+//
+//	s3://bucket/prefix?region=us-east-1&endpoint_url=http://minio:9000&use_path_style=true
+//
+// Credentials are read the same way as the AWS CLI does, e.g. from
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func newS3Store(ctx context.Context, u *url.URL) (s3Store, error) {
+	bucket := u.Host
+	if bucket == "" {
+		return s3Store{}, fmt.Errorf("missing bucket in cache URL %s", u.Redacted())
+	}
+	query := u.Query()
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region := query.Get("region"); region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return s3Store{}, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	var usePathStyle bool
+	if v := query.Get("use_path_style"); v != "" {
+		usePathStyle, err = strconv.ParseBool(v)
+		if err != nil {
+			return s3Store{}, fmt.Errorf("invalid use_path_style %q: %w", v, err)
+		}
+	}
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		if endpoint := query.Get("endpoint_url"); endpoint != "" {
+			options.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+		}
+		options.UsePathStyle = usePathStyle
+	})
+
+	return s3Store{
+		client:  client,
+		presign: s3.NewPresignClient(client, s3.WithPresignExpires(s3PresignExpiry)),
+		bucket:  bucket,
+		prefix:  strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s s3Store) key(key string) *string {
+	return aws.String(path.Join(s.prefix, key))
+}
+
+func (s s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    s.key(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, fmt.Errorf("%s: %w", key, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (s s3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    s.key(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s s3Store) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    s.key(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s s3Store) DownloadURL(ctx context.Context, key string) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    s.key(key),
+	})
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s s3Store) UploadURL(ctx context.Context, key string) (string, map[string]string, error) {
+	req, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    s.key(key),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	headers := map[string]string{}
+	for k, vs := range req.SignedHeader {
+		if strings.EqualFold(k, "Host") || len(vs) == 0 {
+			// set by the HTTP client from the URL
+			continue
+		}
+		headers[k] = vs[0]
+	}
+	return req.URL, headers, nil
+}
+
+func isS3NotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "NoSuchKey", "NotFound":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 serves the path-style object API of a single bucket from memory,
+// without checking signatures.
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		http.Error(w, "unknown bucket", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = data
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message><Key>%s</Key></Error>`, key)
+			}
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	default:
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) object(key string) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.objects[key]
+}
+
+func testS3Store(t *testing.T) (s3Store, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{bucket: "bucket", objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	// don't pick up the configuration of the host
+	noConfig := filepath.Join(t.TempDir(), "none")
+	t.Setenv("AWS_CONFIG_FILE", noConfig)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", noConfig)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	u, err := url.Parse("s3://bucket/prefix/?region=us-east-1&use_path_style=true&endpoint_url=" + url.QueryEscape(srv.URL))
+	require.NoError(t, err)
+	store, err := newS3Store(context.Background(), u)
+	require.NoError(t, err)
+	return store, fake
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	store, fake := testS3Store(t)
+
+	_, err := store.Get(ctx, "missing")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	exists, err := store.Exists(ctx, "missing")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, store.Put(ctx, "some/key", []byte("hello")))
+	require.Equal(t, []byte("hello"), fake.object("prefix/some/key"))
+
+	data, err := store.Get(ctx, "some/key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data)
+	exists, err = store.Exists(ctx, "some/key")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	require.NoError(t, store.Put(ctx, "some/key", []byte("replaced")))
+	data, err = store.Get(ctx, "some/key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("replaced"), data)
+}
+
+func TestS3StorePresignedURLs(t *testing.T) {
+	ctx := context.Background()
+	store, fake := testS3Store(t)
+
+	uploadURL, headers, err := store.UploadURL(ctx, "layer")
+	require.NoError(t, err)
+	require.Contains(t, uploadURL, "/bucket/prefix/layer?")
+	require.Contains(t, uploadURL, "X-Amz-Signature=")
+	for k := range headers {
+		require.NotEqual(t, "host", strings.ToLower(k))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader([]byte("layer data")))
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []byte("layer data"), fake.object("prefix/layer"))
+
+	downloadURL, err := store.DownloadURL(ctx, "layer")
+	require.NoError(t, err)
+	require.Contains(t, downloadURL, "/bucket/prefix/layer?")
+	require.Contains(t, downloadURL, "X-Amz-Signature=")
+
+	resp, err = http.Get(downloadURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, []byte("layer data"), data)
+}
+
+func TestIsS3NotFound(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		notFound bool
+	}{
+		{&smithy.GenericAPIError{Code: "NoSuchKey"}, true},
+		{&smithy.GenericAPIError{Code: "NotFound"}, true},
+		{fmt.Errorf("get: %w", &smithy.GenericAPIError{Code: "NoSuchKey"}), true},
+		{&smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{errors.New("NoSuchKey"), false},
+	} {
+		require.Equal(t, tc.notFound, isS3NotFound(tc.err), "%v", tc.err)
+	}
+}
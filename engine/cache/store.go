@@ -0,0 +1,389 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	remotecache "github.com/moby/buildkit/cache/remotecache/v1"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+/*
+A self-hosted cache keeps everything the hosted cache service would otherwise
+keep in a blobStore, and implements the Service API in the engine itself:
+  - cacheconfig.json is the cache config served on import. On export it's
+    merged with the local cache metadata, so that engines sharing the store
+    don't overwrite each other's records.
+  - engines/<engine id>.json records which of an engine's cache refs have
+    already been exported, and as which layers, so they aren't exported again.
+  - mounts.json describes the latest tarball of each synced cache mount.
+  - blobs/<algorithm>/<encoded digest> are the layer and cache mount blobs.
+
+Writes to the shared metadata are read-merge-write without any locking, so
+concurrent exports from several engines may lose records. That only costs
+cache hits, not correctness.
+*/
+
+// blobStore is where a self-hosted cache keeps its metadata and blobs.
+type blobStore interface {
+	// Get returns the content of key, or an error wrapping os.ErrNotExist.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put replaces the content of key.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Exists returns whether key has been written.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// DownloadURL returns a URL that the manager can GET the content of key
+	// from.
+	DownloadURL(ctx context.Context, key string) (string, error)
+
+	// UploadURL returns a URL and headers that the manager can PUT the
+	// content of key to.
+	UploadURL(ctx context.Context, key string) (string, map[string]string, error)
+}
+
+const (
+	storeConfigKey = "cacheconfig.json"
+	storeMountsKey = "mounts.json"
+
+	storeImportPeriod  = 5 * time.Minute
+	storeExportPeriod  = 5 * time.Minute
+	storeExportTimeout = 10 * time.Minute
+)
+
+func storeBlobKey(dgst digest.Digest) string {
+	return "blobs/" + dgst.Algorithm().String() + "/" + dgst.Encoded()
+}
+
+func storeEngineKey(engineID string) string {
+	return "engines/" + engineID + ".json"
+}
+
+type storeService struct {
+	store blobStore
+
+	mu       sync.Mutex
+	engineID string
+	// exported maps the IDs of the cache refs this engine has exported to
+	// their layers
+	exported map[string][]ocispecs.Descriptor
+	// pending maps the record digests handed out by UpdateCacheRecords to
+	// their cache ref IDs
+	pending map[digest.Digest]string
+	// the cache metadata sent by the last UpdateCacheRecords call
+	cacheKeys []CacheKey
+	links     []Link
+}
+
+var _ Service = &storeService{}
+
+func newStoreService(store blobStore) *storeService {
+	return &storeService{
+		store:    store,
+		exported: map[string][]ocispecs.Descriptor{},
+		pending:  map[digest.Digest]string{},
+	}
+}
+
+func (s *storeService) GetConfig(ctx context.Context, req GetConfigRequest) (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engineID = req.EngineID
+	if err := s.getJSON(ctx, storeEngineKey(s.engineID), &s.exported); err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		ImportPeriod:  storeImportPeriod,
+		ExportPeriod:  storeExportPeriod,
+		ExportTimeout: storeExportTimeout,
+	}, nil
+}
+
+func (s *storeService) UpdateCacheRecords(ctx context.Context, req UpdateCacheRecordsRequest) (*UpdateCacheRecordsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cacheKeys = req.CacheKeys
+	s.links = req.Links
+
+	resp := &UpdateCacheRecordsResponse{}
+	s.pending = map[digest.Digest]string{}
+	for _, cacheKey := range req.CacheKeys {
+		for _, res := range cacheKey.Results {
+			if _, ok := s.exported[res.ID]; ok {
+				continue
+			}
+			recordDigest := digest.FromString(res.ID)
+			if _, ok := s.pending[recordDigest]; ok {
+				continue
+			}
+			s.pending[recordDigest] = res.ID
+			resp.ExportRecords = append(resp.ExportRecords, ExportRecord{
+				Digest:     recordDigest,
+				CacheRefID: res.ID,
+			})
+		}
+	}
+
+	if len(resp.ExportRecords) == 0 {
+		// the manager won't call UpdateCacheLayers, but there may still be new
+		// links to results that were exported before
+		if err := s.writeConfig(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *storeService) UpdateCacheLayers(ctx context.Context, req UpdateCacheLayersRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range req.UpdatedRecords {
+		refID, ok := s.pending[record.RecordDigest]
+		if !ok {
+			return fmt.Errorf("unknown cache record %s", record.RecordDigest)
+		}
+		s.exported[refID] = record.Layers
+	}
+	if err := s.putJSON(ctx, storeEngineKey(s.engineID), s.exported); err != nil {
+		return err
+	}
+	return s.writeConfig(ctx)
+}
+
+func (s *storeService) ImportCache(ctx context.Context) (*remotecache.CacheConfig, error) {
+	config := &remotecache.CacheConfig{}
+	if err := s.getJSON(ctx, storeConfigKey, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s *storeService) GetLayerDownloadURL(ctx context.Context, req GetLayerDownloadURLRequest) (*GetLayerDownloadURLResponse, error) {
+	u, err := s.store.DownloadURL(ctx, storeBlobKey(req.Digest))
+	if err != nil {
+		return nil, err
+	}
+	return &GetLayerDownloadURLResponse{URL: u}, nil
+}
+
+func (s *storeService) GetLayerUploadURL(ctx context.Context, req GetLayerUploadURLRequest) (*GetLayerUploadURLResponse, error) {
+	u, headers, err := s.store.UploadURL(ctx, storeBlobKey(req.Digest))
+	if err != nil {
+		return nil, err
+	}
+	return &GetLayerUploadURLResponse{URL: u, Headers: headers}, nil
+}
+
+func (s *storeService) GetCacheMountConfig(ctx context.Context, req GetCacheMountConfigRequest) (*GetCacheMountConfigResponse, error) {
+	mounts := map[string]SyncedCacheMountConfig{}
+	if err := s.getJSON(ctx, storeMountsKey, &mounts); err != nil {
+		return nil, err
+	}
+
+	resp := &GetCacheMountConfigResponse{}
+	for name, mount := range mounts {
+		mount.Name = name
+		// the blob is only there if its upload succeeded; if not, the mount is
+		// synced from scratch like a new one
+		blobKey := storeBlobKey(mount.Digest)
+		exists, err := s.store.Exists(ctx, blobKey)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			mount.URL, err = s.store.DownloadURL(ctx, blobKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp.SyncedCacheMounts = append(resp.SyncedCacheMounts, mount)
+	}
+	sort.Slice(resp.SyncedCacheMounts, func(i, j int) bool {
+		return resp.SyncedCacheMounts[i].Name < resp.SyncedCacheMounts[j].Name
+	})
+	return resp, nil
+}
+
+func (s *storeService) GetCacheMountUploadURL(ctx context.Context, req GetCacheMountUploadURLRequest) (*GetCacheMountUploadURLResponse, error) {
+	blobKey := storeBlobKey(req.Digest)
+	u, headers, err := s.store.UploadURL(ctx, blobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// cache mounts are uploaded concurrently
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mounts := map[string]SyncedCacheMountConfig{}
+	if err := s.getJSON(ctx, storeMountsKey, &mounts); err != nil {
+		return nil, err
+	}
+	mounts[req.CacheName] = SyncedCacheMountConfig{
+		Digest:    req.Digest,
+		Size:      req.Size,
+		MediaType: ocispecs.MediaTypeImageLayerZstd,
+	}
+	if err := s.putJSON(ctx, storeMountsKey, mounts); err != nil {
+		return nil, err
+	}
+
+	return &GetCacheMountUploadURLResponse{URL: u, Headers: headers}, nil
+}
+
+// writeConfig merges the cache metadata of the last UpdateCacheRecords call
+// into the stored cache config. Only results whose layers have been exported
+// are included.
+func (s *storeService) writeConfig(ctx context.Context) error {
+	chains := remotecache.NewCacheChains()
+
+	stored := remotecache.CacheConfig{}
+	if err := s.getJSON(ctx, storeConfigKey, &stored); err != nil {
+		return err
+	}
+	if err := remotecache.ParseConfig(stored, storedDescriptors(stored), chains); err != nil {
+		return fmt.Errorf("parse stored cache config: %w", err)
+	}
+
+	// non-root keys get the digest of the links to them; root keys are the
+	// digest themselves
+	keyDigests := map[string]digest.Digest{}
+	for _, link := range s.links {
+		keyDigests[link.ID] = link.Digest
+	}
+	records := map[string]solver.CacheExporterRecord{}
+	for _, cacheKey := range s.cacheKeys {
+		dgst, ok := keyDigests[cacheKey.ID]
+		if !ok {
+			dgst = digest.Digest(cacheKey.ID)
+		}
+		rec := chains.Add(dgst)
+		records[cacheKey.ID] = rec
+
+		// a record only holds a single result, so pick the newest exported one
+		var latest *Result
+		for i, res := range cacheKey.Results {
+			if _, ok := s.exported[res.ID]; !ok {
+				continue
+			}
+			if latest == nil || res.CreatedAt.After(latest.CreatedAt) {
+				latest = &cacheKey.Results[i]
+			}
+		}
+		if latest != nil && len(s.exported[latest.ID]) > 0 {
+			rec.AddResult("", 0, latest.CreatedAt, &solver.Remote{
+				Descriptors: s.exported[latest.ID],
+			})
+		}
+	}
+	for _, link := range s.links {
+		rec, ok := records[link.ID]
+		if !ok {
+			continue
+		}
+		src, ok := records[link.LinkedID]
+		if !ok {
+			continue
+		}
+		rec.LinkFrom(src, link.Input, link.Selector.String())
+	}
+
+	config, descs, err := chains.Marshal(ctx)
+	if err != nil {
+		return fmt.Errorf("marshal cache config: %w", err)
+	}
+	for i, layer := range config.Layers {
+		pair, ok := descs[layer.Blob]
+		if !ok {
+			return fmt.Errorf("missing descriptor for layer %s", layer.Blob)
+		}
+		config.Layers[i].Annotations = layerAnnotations(pair.Descriptor)
+	}
+
+	bklog.G(ctx).Debugf("writing cache config with %d records and %d layers", len(config.Records), len(config.Layers))
+	return s.putJSON(ctx, storeConfigKey, config)
+}
+
+// storedDescriptors returns the descriptors of the layers of a stored cache
+// config, so it can be parsed back into cache chains.
+func storedDescriptors(config remotecache.CacheConfig) remotecache.DescriptorProvider {
+	descs := remotecache.DescriptorProvider{}
+	for _, layer := range config.Layers {
+		if layer.Annotations == nil {
+			continue
+		}
+		annotations := map[string]string{
+			"containerd.io/uncompressed": layer.Annotations.DiffID.String(),
+		}
+		if !layer.Annotations.CreatedAt.IsZero() {
+			if createdAt, err := layer.Annotations.CreatedAt.MarshalText(); err == nil {
+				annotations["buildkit/createdat"] = string(createdAt)
+			}
+		}
+		descs[layer.Blob] = remotecache.DescriptorProviderPair{
+			Descriptor: ocispecs.Descriptor{
+				MediaType:   layer.Annotations.MediaType,
+				Digest:      layer.Blob,
+				Size:        layer.Annotations.Size,
+				Annotations: annotations,
+			},
+		}
+	}
+	return descs
+}
+
+// layerAnnotations is the inverse of storedDescriptors.
+func layerAnnotations(desc ocispecs.Descriptor) *remotecache.LayerAnnotations {
+	annotations := &remotecache.LayerAnnotations{
+		MediaType: desc.MediaType,
+		DiffID:    digest.Digest(desc.Annotations["containerd.io/uncompressed"]),
+		Size:      desc.Size,
+	}
+	if createdAt, ok := desc.Annotations["buildkit/createdat"]; ok {
+		if err := annotations.CreatedAt.UnmarshalText([]byte(createdAt)); err != nil {
+			annotations.CreatedAt = time.Time{}
+		}
+	}
+	return annotations
+}
+
+// getJSON decodes the content of key into v, leaving v untouched if key
+// doesn't exist yet.
+func (s *storeService) getJSON(ctx context.Context, key string, v any) error {
+	dt, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("get %s: %w", key, err)
+	}
+	if err := json.Unmarshal(dt, v); err != nil {
+		return fmt.Errorf("decode %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *storeService) putJSON(ctx context.Context, key string, v any) error {
+	dt, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, key, dt); err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	remotecache "github.com/moby/buildkit/cache/remotecache/v1"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func testLayer(content string) ocispecs.Descriptor {
+	return ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerZstd,
+		Digest:    digest.FromString(content),
+		Size:      int64(len(content)),
+		Annotations: map[string]string{
+			"containerd.io/uncompressed": digest.FromString("uncompressed " + content).String(),
+		},
+	}
+}
+
+// exportRecords runs an export the way the manager does.
+func exportRecords(ctx context.Context, t *testing.T, svc Service, keys []CacheKey, links []Link, layers map[string][]ocispecs.Descriptor) []ExportRecord {
+	resp, err := svc.UpdateCacheRecords(ctx, UpdateCacheRecordsRequest{
+		CacheKeys: keys,
+		Links:     links,
+	})
+	require.NoError(t, err)
+	if len(resp.ExportRecords) == 0 {
+		return nil
+	}
+	var updated []RecordLayers
+	for _, record := range resp.ExportRecords {
+		updated = append(updated, RecordLayers{
+			RecordDigest: record.Digest,
+			Layers:       layers[record.CacheRefID],
+		})
+	}
+	require.NoError(t, svc.UpdateCacheLayers(ctx, UpdateCacheLayersRequest{
+		UpdatedRecords: updated,
+	}))
+	return resp.ExportRecords
+}
+
+func TestStoreServiceExportImport(t *testing.T) {
+	ctx := context.Background()
+	store, err := newLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	rootID := digest.FromString("root@0").String()
+	childID := "child"
+	keys := []CacheKey{
+		{ID: rootID, Results: []Result{{ID: "ref1", CreatedAt: time.Now()}}},
+		{ID: childID, Results: []Result{{ID: "ref2", CreatedAt: time.Now()}}},
+	}
+	links := []Link{{
+		ID:       childID,
+		LinkedID: rootID,
+		Digest:   digest.FromString("child@0"),
+	}}
+	layers := map[string][]ocispecs.Descriptor{
+		"ref1": {testLayer("a")},
+		"ref2": {testLayer("a"), testLayer("b")},
+	}
+
+	svc := newStoreService(store)
+	_, err = svc.GetConfig(ctx, GetConfigRequest{EngineID: "engine1"})
+	require.NoError(t, err)
+
+	config, err := svc.ImportCache(ctx)
+	require.NoError(t, err)
+	require.Empty(t, config.Records)
+
+	exported := exportRecords(ctx, t, svc, keys, links, layers)
+	require.Len(t, exported, 2)
+
+	config, err = svc.ImportCache(ctx)
+	require.NoError(t, err)
+	require.Len(t, config.Records, 2)
+	require.Len(t, config.Layers, 2)
+	for _, layer := range config.Layers {
+		require.NotNil(t, layer.Annotations)
+		require.NotEmpty(t, layer.Annotations.DiffID)
+		require.Equal(t, ocispecs.MediaTypeImageLayerZstd, layer.Annotations.MediaType)
+	}
+	require.NoError(t, remotecache.ParseConfig(*config, storedDescriptors(*config), remotecache.NewCacheChains()))
+
+	// nothing is exported twice, even after a restart
+	svc = newStoreService(store)
+	_, err = svc.GetConfig(ctx, GetConfigRequest{EngineID: "engine1"})
+	require.NoError(t, err)
+	require.Empty(t, exportRecords(ctx, t, svc, keys, links, layers))
+
+	// another engine's export is merged with the stored records
+	otherID := digest.FromString("other@0").String()
+	other := newStoreService(store)
+	_, err = other.GetConfig(ctx, GetConfigRequest{EngineID: "engine2"})
+	require.NoError(t, err)
+	exported = exportRecords(ctx, t, other,
+		[]CacheKey{{ID: otherID, Results: []Result{{ID: "ref3", CreatedAt: time.Now()}}}},
+		nil,
+		map[string][]ocispecs.Descriptor{"ref3": {testLayer("c")}},
+	)
+	require.Len(t, exported, 1)
+
+	config, err = svc.ImportCache(ctx)
+	require.NoError(t, err)
+	require.Len(t, config.Records, 3)
+	require.Len(t, config.Layers, 3)
+}
+
+func TestStoreServiceCacheMounts(t *testing.T) {
+	ctx := context.Background()
+	store, err := newLocalStore(t.TempDir())
+	require.NoError(t, err)
+	svc := newStoreService(store)
+	httpClient := &http.Client{Transport: fileTransport{}}
+
+	content := "cache mount tarball"
+	dgst := digest.FromString(content)
+	uploadResp, err := svc.GetCacheMountUploadURL(ctx, GetCacheMountUploadURLRequest{
+		CacheName: "go-build",
+		Digest:    dgst,
+		Size:      int64(len(content)),
+	})
+	require.NoError(t, err)
+
+	// not uploaded yet, so nothing to download
+	configResp, err := svc.GetCacheMountConfig(ctx, GetCacheMountConfigRequest{})
+	require.NoError(t, err)
+	require.Len(t, configResp.SyncedCacheMounts, 1)
+	require.Equal(t, "go-build", configResp.SyncedCacheMounts[0].Name)
+	require.Empty(t, configResp.SyncedCacheMounts[0].URL)
+
+	req, err := http.NewRequest(http.MethodPut, uploadResp.URL, strings.NewReader(content))
+	require.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	configResp, err = svc.GetCacheMountConfig(ctx, GetCacheMountConfigRequest{})
+	require.NoError(t, err)
+	mount := configResp.SyncedCacheMounts[0]
+	require.Equal(t, dgst, mount.Digest)
+	require.Equal(t, int64(len(content)), mount.Size)
+	require.NotEmpty(t, mount.URL)
+
+	readerAt := &urlReaderAt{
+		ctx:        ctx,
+		httpClient: httpClient,
+		url:        mount.URL,
+		desc:       ocispecs.Descriptor{Digest: dgst, Size: mount.Size},
+	}
+	defer readerAt.Close()
+	buf := make([]byte, 5)
+	n, err := readerAt.ReadAt(buf, 6)
+	require.NoError(t, err)
+	require.Equal(t, "mount", string(buf[:n]))
+	rest, err := io.ReadAll(io.NewSectionReader(readerAt, 11, mount.Size-11))
+	require.NoError(t, err)
+	require.Equal(t, " tarball", string(rest))
+}
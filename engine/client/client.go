@@ -21,6 +21,7 @@ import (
 	"dagger.io/dagger"
 	"github.com/Khan/genqlient/graphql"
 	"github.com/cenkalti/backoff/v4"
+	continuityfs "github.com/containerd/continuity/fs"
 
 	"github.com/docker/cli/cli/config"
 	"github.com/google/uuid"
@@ -39,8 +40,10 @@ import (
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/session"
 	"github.com/dagger/dagger/telemetry"
@@ -69,10 +72,18 @@ type Params struct {
 	EngineNameCallback func(string)
 	CloudURLCallback   func(string)
 
+	// LocalImportCallback is called with the options of every directory the
+	// engine loads from this client's host, e.g. through Host.directory.
+	LocalImportCallback func(engine.LocalImportOpts)
+
 	// If this client is for a module function, this digest will be set in the
 	// grpc context metadata for any api requests back to the engine. It's used by the API
 	// server to determine which schema to serve and other module context metadata.
 	ModuleCallerDigest digest.Digest
+
+	// DagqlLimits bounds the queries made in the session, within the engine's
+	// own limits. It's narrowed further by any limits set in the environment.
+	DagqlLimits dagql.Limits
 }
 
 type Client struct {
@@ -189,6 +200,12 @@ func Connect(ctx context.Context, params Params) (_ *Client, _ context.Context,
 		return nil, nil, fmt.Errorf("cache config from env: %w", err)
 	}
 
+	envLimits, err := engine.DagqlLimitsFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("dagql limits from env: %w", err)
+	}
+	c.DagqlLimits = c.DagqlLimits.Narrow(envLimits)
+
 	remote, err := url.Parse(c.RunnerHost)
 	if err != nil {
 		return nil, nil, fmt.Errorf("parse runner host: %w", err)
@@ -254,8 +271,9 @@ func Connect(ctx context.Context, params Params) (_ *Client, _ context.Context,
 
 	// filesync
 	if !c.DisableHostRW {
-		bkSession.Allow(AnyDirSource{})
+		bkSession.Allow(AnyDirSource{onImport: c.LocalImportCallback})
 		bkSession.Allow(AnyDirTarget{})
+		bkSession.Allow(ExportDryRunTarget{})
 	}
 
 	// sockets
@@ -284,6 +302,7 @@ func Connect(ctx context.Context, params Params) (_ *Client, _ context.Context,
 				UpstreamCacheImportConfig: c.upstreamCacheImportOptions,
 				Labels:                    c.labels,
 				ModuleCallerDigest:        c.ModuleCallerDigest,
+				DagqlLimits:               c.DagqlLimits,
 			}.AppendToMD(meta))
 		})
 	})
@@ -665,7 +684,9 @@ func (c *Client) Dagger() *dagger.Client {
 }
 
 // Local dir imports
-type AnyDirSource struct{}
+type AnyDirSource struct {
+	onImport func(engine.LocalImportOpts)
+}
 
 func (s AnyDirSource) Register(server *grpc.Server) {
 	filesync.RegisterFileSyncServer(server, s)
@@ -684,6 +705,9 @@ func (s AnyDirSource) DiffCopy(stream filesync.FileSync_DiffCopyServer) error {
 	if opts.ReadSingleFileOnly {
 		// just stream the file bytes to the caller
 		fileContents, err := os.ReadFile(opts.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			return status.Errorf(codes.NotFound, "read file: %s", err)
+		}
 		if err != nil {
 			return fmt.Errorf("read file: %w", err)
 		}
@@ -695,6 +719,9 @@ func (s AnyDirSource) DiffCopy(stream filesync.FileSync_DiffCopyServer) error {
 	}
 
 	// otherwise, do the whole directory sync back to the caller
+	if s.onImport != nil {
+		s.onImport(*opts)
+	}
 	fs, err := fsutil.NewFS(opts.Path)
 	if err != nil {
 		return err
@@ -729,18 +756,21 @@ func (AnyDirTarget) DiffCopy(stream filesync.FileSend_DiffCopyServer) (rerr erro
 	}
 
 	if !opts.IsFileStream {
+		if opts.Wipe {
+			if err := checkWipe(opts.Path); err != nil {
+				return err
+			}
+		}
+
 		// we're writing a full directory tree, normal fsutil.Receive is good
 		if err := os.MkdirAll(opts.Path, 0o700); err != nil {
 			return fmt.Errorf("failed to create synctarget dest dir %s: %w", opts.Path, err)
 		}
 
 		err := fsutil.Receive(stream.Context(), stream, opts.Path, fsutil.ReceiveOpt{
-			Merge: true,
-			Filter: func(path string, stat *fstypes.Stat) bool {
-				stat.Uid = uint32(os.Getuid())
-				stat.Gid = uint32(os.Getgid())
-				return true
-			},
+			// without merging, files that weren't sent are removed
+			Merge:  !opts.Wipe,
+			Filter: ownedByCurrentUser,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to receive fs changes: %w", err)
@@ -817,6 +847,119 @@ func (AnyDirTarget) DiffCopy(stream filesync.FileSend_DiffCopyServer) (rerr erro
 	}
 }
 
+// checkWipe refuses to wipe the current working directory or any of its
+// parents, or the root of a git repository, where removing everything that
+// isn't exported would remove the work tree or the repository itself.
+func checkWipe(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(path, cwd); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to wipe %s: it contains the current working directory", path)
+	}
+	if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+		return fmt.Errorf("refusing to wipe %s: it is the root of a git repository", path)
+	}
+	return nil
+}
+
+func ownedByCurrentUser(path string, stat *fstypes.Stat) bool {
+	stat.Uid = uint32(os.Getuid())
+	stat.Gid = uint32(os.Getgid())
+	return true
+}
+
+// Local dir export dry runs
+type ExportDryRunTarget struct{}
+
+func (t ExportDryRunTarget) Register(server *grpc.Server) {
+	session.RegisterExportDryRunServer(server, t)
+}
+
+// DiffCopy receives a directory tree into a temporary directory and replies
+// with the changes that exporting it to the requested path would make, as
+// described in buildkit.Client.LocalDirExportDryRun. Nothing is written to
+// the path itself.
+func (ExportDryRunTarget) DiffCopy(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+
+	opts, err := engine.LocalExportOptsFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("get local export opts: %w", err)
+	}
+	if opts.Wipe {
+		if err := checkWipe(opts.Path); err != nil {
+			return err
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dagger-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = fsutil.Receive(ctx, stream, tmpDir, fsutil.ReceiveOpt{
+		Merge:  true,
+		Filter: ownedByCurrentUser,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to receive fs changes: %w", err)
+	}
+
+	changes, err := exportChanges(ctx, opts.Path, tmpDir, opts.Wipe)
+	if err != nil {
+		return err
+	}
+
+	dt, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(&filesync.BytesMessage{Data: dt})
+}
+
+// exportChanges returns the changes that exporting srcPath to destPath would
+// make to destPath.
+func exportChanges(ctx context.Context, destPath, srcPath string, wipe bool) ([]string, error) {
+	if _, err := os.Lstat(destPath); errors.Is(err, os.ErrNotExist) {
+		// everything is added
+		destPath = ""
+	}
+
+	changes := []string{}
+	err := continuityfs.Changes(ctx, destPath, srcPath, func(kind continuityfs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		p = strings.TrimPrefix(p, "/")
+		switch kind {
+		case continuityfs.ChangeKindAdd:
+			changes = append(changes, "A "+p)
+		case continuityfs.ChangeKindModify:
+			// directories are only modified by changes to their contents
+			if !fi.IsDir() {
+				changes = append(changes, "M "+p)
+			}
+		case continuityfs.ChangeKindDelete:
+			// without wiping, files that weren't sent are left alone
+			if wipe {
+				changes = append(changes, "D "+p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s: %w", destPath, err)
+	}
+	return changes, nil
+}
+
 type progRockAttachable struct {
 	writer progrock.Writer
 }
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/dagger/dagger/dagql"
+)
+
+const (
+	DagqlMaxDepthEnvName  = "_EXPERIMENTAL_DAGGER_DAGQL_MAX_DEPTH"
+	DagqlMaxCostEnvName   = "_EXPERIMENTAL_DAGGER_DAGQL_MAX_COST"
+	DagqlMaxFanOutEnvName = "_EXPERIMENTAL_DAGGER_DAGQL_MAX_FAN_OUT"
+)
+
+// DagqlLimitsFromEnv configures the limits of the queries made in a session.
+//
+// Set in the engine's environment, they apply to every session. Set in a
+// client's environment, they apply to the sessions it starts, within the
+// engine's limits.
+//
+// There are no limits by default, since long pipelines are legitimately
+// expressed as deeply nested queries. Zero means no limit; negative values
+// are rejected.
+func DagqlLimitsFromEnv() (dagql.Limits, error) {
+	var limits dagql.Limits
+	for name, limit := range map[string]*int{
+		DagqlMaxDepthEnvName:  &limits.MaxDepth,
+		DagqlMaxCostEnvName:   &limits.MaxCost,
+		DagqlMaxFanOutEnvName: &limits.MaxFanOut,
+	} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		var err error
+		*limit, err = strconv.Atoi(v)
+		if err != nil {
+			return limits, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if *limit < 0 {
+			return limits, fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// ExecMetricsMountPath is where the engine mounts, in each exec, the
+	// directory to which the shim writes the resources used by the exec.
+	ExecMetricsMountPath = "/.dagger_metrics_mount"
+
+	// ExecMetricsFile is the name of the file in ExecMetricsMountPath to which
+	// the shim writes the latest JSON encoded ExecMetrics sample.
+	ExecMetricsFile = "metrics"
+
+	// ExecMetricsStream is the buildkit log stream of an exec's vertex to
+	// which the engine writes each new ExecMetrics sample.
+	ExecMetricsStream = 3
+
+	// ExecMetricsMeta is the name of the progrock vertex meta holding the
+	// latest ExecMetrics sample of an exec.
+	ExecMetricsMeta = "exec-metrics"
+)
+
+// ExecMetrics are the resources consumed so far by an exec, as sampled from
+// the cgroup of its container.
+type ExecMetrics struct {
+	// CPUTime is the total CPU time spent, in user and system mode.
+	CPUTime time.Duration `json:"cpuTime"`
+
+	// MemoryPeak is the highest memory usage seen, in bytes.
+	MemoryPeak uint64 `json:"memoryPeak"`
+
+	// IOReadBytes and IOWriteBytes are the bytes read from and written to
+	// block devices.
+	IOReadBytes  uint64 `json:"ioReadBytes"`
+	IOWriteBytes uint64 `json:"ioWriteBytes"`
+
+	// NetRxBytes and NetTxBytes are the bytes received and sent over network
+	// interfaces other than loopback.
+	NetRxBytes uint64 `json:"netRxBytes"`
+	NetTxBytes uint64 `json:"netTxBytes"`
+}
+
+// String summarizes the metrics on a single line.
+func (m ExecMetrics) String() string {
+	return fmt.Sprintf("cpu %s  mem %s  io %s/%s  net %s/%s",
+		m.CPUTime.Round(time.Millisecond),
+		humanBytes(m.MemoryPeak),
+		humanBytes(m.IOReadBytes),
+		humanBytes(m.IOWriteBytes),
+		humanBytes(m.NetRxBytes),
+		humanBytes(m.NetTxBytes),
+	)
+}
+
+// Proto encodes the metrics as the payload of a progrock vertex meta.
+func (m ExecMetrics) Proto() (*anypb.Any, error) {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	st, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+	return anypb.New(st)
+}
+
+// ExecMetricsFromProto decodes metrics encoded with ExecMetrics.Proto.
+func ExecMetricsFromProto(data *anypb.Any) (ExecMetrics, error) {
+	var st structpb.Struct
+	if err := data.UnmarshalTo(&st); err != nil {
+		return ExecMetrics{}, err
+	}
+	// round-trip through encoding/json, which formats the float64 numbers of
+	// the struct without exponents
+	payload, err := json.Marshal(st.AsMap())
+	if err != nil {
+		return ExecMetrics{}, err
+	}
+	var m ExecMetrics
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return ExecMetrics{}, err
+	}
+	return m, nil
+}
+
+func humanBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), strings.ToUpper("kmgtpe")[exp])
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/dagql"
 	controlapi "github.com/moby/buildkit/api/services/control"
 	"github.com/opencontainers/go-digest"
 	"google.golang.org/grpc/metadata"
@@ -68,6 +69,10 @@ type ClientMetadata struct {
 
 	// Import configuration for Buildkit's remote cache
 	UpstreamCacheImportConfig []*controlapi.CacheOptionsEntry
+
+	// (Optional) Limits of the queries made in the session, applied within
+	// the engine's own limits when the server is initialized.
+	DagqlLimits dagql.Limits `json:"dagql_limits"`
 }
 
 // ClientIDs returns the ClientID followed by ParentClientIDs.
@@ -169,6 +174,7 @@ type LocalExportOpts struct {
 	FileOriginalName   string      `json:"file_original_name"`
 	AllowParentDirPath bool        `json:"allow_parent_dir_path"`
 	FileMode           os.FileMode `json:"file_mode"`
+	Wipe               bool        `json:"wipe"`
 }
 
 func (o LocalExportOpts) ToGRPCMD() metadata.MD {
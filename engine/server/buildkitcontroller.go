@@ -13,6 +13,7 @@ import (
 	"github.com/dagger/dagger/auth"
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/cache"
@@ -78,6 +79,9 @@ type BuildkitControllerOpts struct {
 	UpstreamCacheExporters map[string]remotecache.ResolveCacheExporterFunc
 	UpstreamCacheImporters map[string]remotecache.ResolveCacheImporterFunc
 	DNSConfig              *oci.DNSConfig
+	DagqlCacheOpts         dagql.CacheOpts
+	DagqlCache             *dagql.DiskCache
+	DagqlLimits            dagql.Limits
 }
 
 func NewBuildkitController(opts BuildkitControllerOpts) (*BuildkitController, error) {
@@ -267,7 +271,7 @@ func (e *BuildkitController) Session(stream controlapi.Control_SessionServer) (r
 		labels = append(labels, pipeline.EngineLabel(e.EngineName))
 		labels = append(labels, pipeline.LoadServerLabels(engine.Version, runtime.GOOS, runtime.GOARCH, e.cacheManager.ID() != cache.LocalCacheID)...)
 
-		srv, err = NewDaggerServer(ctx, bkClient, e.worker, caller, opts.ServerID, secretStore, authProvider, labels)
+		srv, err = NewDaggerServer(ctx, bkClient, e.worker, caller, opts.ServerID, secretStore, authProvider, labels, e.DagqlCacheOpts, e.DagqlCache, e.DagqlLimits.Narrow(opts.DagqlLimits))
 		if err != nil {
 			e.perServerMu.Unlock(opts.ServerID)
 			return fmt.Errorf("new Dagger server: %w", err)
@@ -509,6 +513,12 @@ func (e *BuildkitController) Close() error {
 	for _, s := range servers {
 		s.Close()
 	}
+
+	if e.DagqlCache != nil {
+		if cacheErr := e.DagqlCache.Close(); cacheErr != nil && err == nil {
+			err = cacheErr
+		}
+	}
 	return err
 }
 
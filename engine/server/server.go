@@ -16,6 +16,7 @@ import (
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/core/pipeline"
 	"github.com/dagger/dagger/core/schema"
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	bkclient "github.com/moby/buildkit/client"
@@ -50,6 +51,9 @@ func NewDaggerServer(
 	secretStore *core.SecretStore,
 	authProvider *auth.RegistryAuthProvider,
 	rootLabels []pipeline.Label,
+	dagqlCacheOpts dagql.CacheOpts,
+	dagqlCache *dagql.DiskCache,
+	dagqlLimits dagql.Limits,
 ) (*DaggerServer, error) {
 	srv := &DaggerServer{
 		serverID: serverID,
@@ -114,6 +118,9 @@ func NewDaggerServer(
 		LeaseManager:   srv.worker.LeaseManager(),
 		Secrets:        secretStore,
 		Auth:           authProvider,
+		CacheOpts:      dagqlCacheOpts,
+		DiskCache:      dagqlCache,
+		Limits:         dagqlLimits,
 	})
 	if err != nil {
 		return nil, err
@@ -125,7 +132,14 @@ func NewDaggerServer(
 func (srv *DaggerServer) LogMetrics(l *logrus.Entry) *logrus.Entry {
 	srv.clientMu.RLock()
 	defer srv.clientMu.RUnlock()
-	return l.WithField(fmt.Sprintf("server-%s-client-count", srv.serverID), srv.connectedClients)
+	l = l.WithField(fmt.Sprintf("server-%s-client-count", srv.serverID), srv.connectedClients)
+
+	stats := srv.schema.CacheStats()
+	return l.WithFields(logrus.Fields{
+		fmt.Sprintf("server-%s-dagql-cache-entries", srv.serverID):   stats.Entries,
+		fmt.Sprintf("server-%s-dagql-cache-hit-rate", srv.serverID):  fmt.Sprintf("%.2f", stats.HitRate()),
+		fmt.Sprintf("server-%s-dagql-cache-evictions", srv.serverID): stats.Evictions,
+	})
 }
 
 func (srv *DaggerServer) Close() {
@@ -137,6 +151,12 @@ func (srv *DaggerServer) Close() {
 	srv.recorder.Complete()
 	// close the recorder so the UI exits
 	srv.recorder.Close()
+
+	if srv.schema != nil {
+		if err := srv.schema.Close(); err != nil {
+			bklog.G(context.Background()).WithError(err).Error("failed to close schema")
+		}
+	}
 }
 
 func (srv *DaggerServer) Wait(ctx context.Context) error {
@@ -0,0 +1,71 @@
+package session
+
+import (
+	context "context"
+
+	"google.golang.org/grpc"
+)
+
+// The ExportDryRun service compares a directory tree streamed by the engine
+// with a path on the client's host and replies with the changes that
+// exporting the tree there would make.
+//
+// The stream carries the same messages as filesync.FileSend's DiffCopy, so
+// fsutil.Send and fsutil.Receive work on it unchanged, followed by a single
+// filesync.BytesMessage from the client with the changes as a JSON array.
+//
+// It's a separate service rather than a mode of FileSend so that a client
+// which doesn't know about dry runs fails the call instead of writing the
+// tree to its host.
+const (
+	exportDryRunServiceName = "dagger.session.ExportDryRun"
+
+	// ExportDryRunDiffCopyMethod is the full name of the DiffCopy method, for
+	// checking whether a session supports it.
+	ExportDryRunDiffCopyMethod = "/" + exportDryRunServiceName + "/DiffCopy"
+)
+
+// ExportDryRunServer is the server API for the ExportDryRun service.
+type ExportDryRunServer interface {
+	DiffCopy(grpc.ServerStream) error
+}
+
+func RegisterExportDryRunServer(s *grpc.Server, srv ExportDryRunServer) {
+	s.RegisterService(&exportDryRunServiceDesc, srv)
+}
+
+// ExportDryRunClient is the client API for the ExportDryRun service.
+type ExportDryRunClient interface {
+	DiffCopy(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStream, error)
+}
+
+func NewExportDryRunClient(cc grpc.ClientConnInterface) ExportDryRunClient {
+	return &exportDryRunClient{cc}
+}
+
+type exportDryRunClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func (c *exportDryRunClient) DiffCopy(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.cc.NewStream(ctx, &exportDryRunServiceDesc.Streams[0], ExportDryRunDiffCopyMethod, opts...)
+}
+
+func exportDryRunDiffCopyHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ExportDryRunServer).DiffCopy(stream)
+}
+
+var exportDryRunServiceDesc = grpc.ServiceDesc{
+	ServiceName: exportDryRunServiceName,
+	HandlerType: (*ExportDryRunServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DiffCopy",
+			Handler:       exportDryRunDiffCopyHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "exportdryrun.go",
+}
@@ -138,7 +138,7 @@ func getGitSSHCommand(knownHosts string) string {
 func argsNoDepth(args []string) []string {
 	out := make([]string, 0, len(args))
 	for _, a := range args {
-		if a != "--depth=1" {
+		if !strings.HasPrefix(a, "--depth=") {
 			out = append(out, a)
 		}
 	}
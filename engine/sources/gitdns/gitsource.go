@@ -33,6 +33,7 @@ import (
 	"github.com/moby/buildkit/util/sshutil"
 	"github.com/moby/buildkit/util/urlutil"
 	"github.com/moby/locker"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -201,6 +202,9 @@ type gitSourceHandler struct {
 	*gitSource
 	src       srcgit.GitIdentifier
 	clientIDs []string
+	checkout  CheckoutOpts
+	listRefs  bool
+	refs      []byte
 	cacheKey  string
 	sm        *session.Manager
 	auth      []string
@@ -210,6 +214,18 @@ func (gs *gitSourceHandler) shaToCacheKey(sha string) string {
 	key := sha
 	if gs.src.KeepGitDir {
 		key += ".git"
+		if gs.checkout.Depth > 0 {
+			key += ".depth=" + strconv.Itoa(gs.checkout.Depth)
+		}
+	}
+	if len(gs.checkout.SparsePaths) > 0 {
+		key += ".sparse=" + digest.FromString(strings.Join(gs.checkout.SparsePaths, "\x00")).Encoded()
+	}
+	if gs.checkout.SkipSubmodules {
+		key += ".nosubmodules"
+	}
+	if gs.checkout.LFS {
+		key += ".lfs"
 	}
 	if gs.src.Subdir != "" {
 		key += ":" + gs.src.Subdir
@@ -222,8 +238,30 @@ func (gs *gitSourceHandler) shaToCacheKey(sha string) string {
 type DaggerGitURLHack struct {
 	Remote    string   `json:"remote"`
 	ClientIDs []string `json:"client_ids"`
+
+	CheckoutOpts
+
+	// ListRefs makes the source produce a single RefsFilename file listing the
+	// remote's branches and tags instead of a checkout.
+	ListRefs bool `json:"list_refs,omitempty"`
+}
+
+// CheckoutOpts limit what is fetched and checked out for a ref.
+type CheckoutOpts struct {
+	// Depth limits the fetched history to the given number of commits.
+	Depth int `json:"depth,omitempty"`
+	// SparsePaths limits the checkout to the given paths.
+	SparsePaths []string `json:"sparse_paths,omitempty"`
+	// SkipSubmodules skips initializing submodules.
+	SkipSubmodules bool `json:"skip_submodules,omitempty"`
+	// LFS replaces Git LFS pointer files with their contents.
+	LFS bool `json:"lfs,omitempty"`
 }
 
+// RefsFilename is the name of the file written when listing refs, in the
+// format of git ls-remote.
+const RefsFilename = "refs"
+
 func (gs *gitSource) Resolve(ctx context.Context, id source.Identifier, sm *session.Manager, _ solver.Vertex) (source.SourceInstance, error) {
 	gitIdentifier, ok := id.(*srcgit.GitIdentifier)
 	if !ok {
@@ -244,6 +282,8 @@ func (gs *gitSource) Resolve(ctx context.Context, id source.Identifier, sm *sess
 	return &gitSourceHandler{
 		src:       *gitIdentifier,
 		clientIDs: clientIDs,
+		checkout:  hack.CheckoutOpts,
+		listRefs:  hack.ListRefs,
 		gitSource: gs,
 		sm:        sm,
 	}, nil
@@ -377,6 +417,18 @@ func (gs *gitSourceHandler) dnsConfig() *oci.DNSConfig {
 
 func (gs *gitSourceHandler) CacheKey(ctx context.Context, g session.Group, index int) (string, string, solver.CacheOpts, bool, error) {
 	remote := gs.src.Remote
+
+	if gs.listRefs {
+		refs, err := gs.lsRemote(ctx, g)
+		if err != nil {
+			return "", "", nil, false, err
+		}
+		cacheKey := "refs:" + digest.FromBytes(refs).String()
+		gs.refs = refs
+		gs.cacheKey = cacheKey
+		return cacheKey, cacheKey, nil, true, nil
+	}
+
 	gs.locker.Lock(remote)
 	defer gs.locker.Unlock(remote)
 
@@ -475,6 +527,10 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		return gs.cache.Get(ctx, sis[0].ID(), nil)
 	}
 
+	if gs.listRefs {
+		return gs.snapshotRefs(ctx, g, snapshotKey)
+	}
+
 	gs.locker.Lock(gs.src.Remote)
 	defer gs.locker.Unlock(gs.src.Remote)
 	gitDir, unmountGitDir, err := gs.mountRemote(ctx, gs.src.Remote, gs.auth, g)
@@ -532,14 +588,20 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		os.RemoveAll(filepath.Join(gitDir, "shallow.lock"))
 
 		args := []string{"fetch"}
-		if !isCommitSHA(ref) { // TODO: find a branch from ls-remote?
-			args = append(args, "--depth=1", "--no-tags")
-		} else {
+		switch {
+		case !isCommitSHA(ref): // TODO: find a branch from ls-remote?
+			args = append(args, gs.depthArg(), "--no-tags")
+		case gs.checkout.Depth > 0:
+			args = append(args, gs.depthArg())
+		default:
 			if _, err := os.Lstat(filepath.Join(gitDir, "shallow")); err == nil {
 				args = append(args, "--unshallow")
 			}
 		}
 		args = append(args, "origin")
+		if isCommitSHA(ref) && gs.checkout.Depth > 0 {
+			args = append(args, ref)
+		}
 		if !isCommitSHA(ref) {
 			args = append(args, "--force", ref+":tags/"+ref)
 			// local refs are needed so they would be advertised on next fetches. Force is used
@@ -623,14 +685,29 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		default:
 			pullref += ":" + pullref
 		}
-		_, err = checkoutGit.run(ctx, "fetch", "-u", "--depth=1", "origin", pullref)
+		_, err = checkoutGit.run(ctx, "fetch", "-u", gs.depthArg(), "origin", pullref)
 		if err != nil {
 			return nil, err
 		}
+		if len(gs.checkout.SparsePaths) > 0 {
+			// configured by hand rather than with sparse-checkout, which doesn't
+			// support unborn branches on older versions of git
+			if _, err := checkoutGit.run(ctx, "config", "core.sparseCheckout", "true"); err != nil {
+				return nil, err
+			}
+			if err := writeSparseCheckout(checkoutDirGit, gs.checkout.SparsePaths); err != nil {
+				return nil, errors.Wrapf(err, "failed to set sparse checkout paths")
+			}
+		}
 		_, err = checkoutGit.run(ctx, "checkout", "FETCH_HEAD")
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
+		if gs.checkout.LFS {
+			if err := gs.checkoutLFS(ctx, git, checkoutGit, gitDir, ref); err != nil {
+				return nil, err
+			}
+		}
 		_, err = checkoutGit.run(ctx, "remote", "set-url", "origin", urlutil.RedactCredentials(gs.src.Remote))
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to set remote origin to %s", urlutil.RedactCredentials(gs.src.Remote))
@@ -651,10 +728,19 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 				return nil, errors.Wrapf(err, "failed to create temporary checkout dir")
 			}
 		}
-		_, err = git.withinDir(gitDir, cd).run(ctx, "checkout", ref, "--", ".")
+		paths := []string{"."}
+		if len(gs.checkout.SparsePaths) > 0 {
+			paths = gs.checkout.SparsePaths
+		}
+		_, err = git.withinDir(gitDir, cd).run(ctx, append([]string{"checkout", ref, "--"}, paths...)...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
+		if gs.checkout.LFS {
+			if err := gs.checkoutLFS(ctx, git, git.withinDir(gitDir, cd), gitDir, ref); err != nil {
+				return nil, err
+			}
+		}
 		if subdir != "." {
 			d, err := os.Open(filepath.Join(cd, subdir))
 			if err != nil {
@@ -684,9 +770,11 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		}
 	}
 
-	_, err = git.withinDir(gitDir, checkoutDir).run(ctx, "submodule", "update", "--init", "--recursive", "--depth=1")
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+	if !gs.checkout.SkipSubmodules {
+		_, err = git.withinDir(gitDir, checkoutDir).run(ctx, "submodule", "update", "--init", "--recursive", "--depth=1")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+		}
 	}
 
 	if idmap := mount.IdentityMapping(); idmap != nil {
@@ -721,6 +809,142 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 	return snap, nil
 }
 
+// checkoutLFS fetches the LFS objects for ref into the shared repository at
+// remoteDir, where they're kept across checkouts, and replaces the LFS pointer
+// files in the work tree of checkoutGit with their contents.
+func (gs *gitSourceHandler) checkoutLFS(ctx context.Context, remoteGit, checkoutGit *gitCLI, remoteDir, ref string) error {
+	args := []string{"lfs", "fetch"}
+	if len(gs.checkout.SparsePaths) > 0 {
+		args = append(args, "--include", strings.Join(gs.checkout.SparsePaths, ","))
+	}
+	args = append(args, "origin", ref)
+	if _, err := remoteGit.run(ctx, args...); err != nil {
+		return errors.Wrapf(err, "failed to fetch LFS objects for %s", urlutil.RedactCredentials(gs.src.Remote))
+	}
+
+	lfsStorage := "lfs.storage=" + filepath.Join(remoteDir, "lfs")
+	if _, err := checkoutGit.run(ctx, "-c", lfsStorage, "lfs", "checkout"); err != nil {
+		return errors.Wrapf(err, "failed to checkout LFS objects for %s", urlutil.RedactCredentials(gs.src.Remote))
+	}
+	return nil
+}
+
+// writeSparseCheckout writes the patterns matching paths, relative to the root
+// of the repository, to its sparse-checkout file.
+func writeSparseCheckout(gitDir string, paths []string) error {
+	var patterns strings.Builder
+	for _, p := range paths {
+		patterns.WriteString(path.Join("/", p) + "\n")
+	}
+	if err := os.MkdirAll(filepath.Join(gitDir, "info"), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gitDir, "info", "sparse-checkout"), []byte(patterns.String()), 0644)
+}
+
+func (gs *gitSourceHandler) depthArg() string {
+	depth := 1
+	if gs.checkout.Depth > 0 {
+		depth = gs.checkout.Depth
+	}
+	return "--depth=" + strconv.Itoa(depth)
+}
+
+// lsRemote lists the branches and tags of the remote.
+func (gs *gitSourceHandler) lsRemote(ctx context.Context, g session.Group) ([]byte, error) {
+	gs.getAuthToken(ctx, g)
+
+	var err error
+	var sock string
+	if gs.src.MountSSHSock != "" {
+		var unmountSock func() error
+		sock, unmountSock, err = gs.mountSSHAuthSock(ctx, gs.src.MountSSHSock, g)
+		if err != nil {
+			return nil, err
+		}
+		defer unmountSock()
+	}
+
+	var knownHosts string
+	if gs.src.KnownSSHHosts != "" {
+		var unmountKnownHosts func() error
+		knownHosts, unmountKnownHosts, err = gs.mountKnownHosts()
+		if err != nil {
+			return nil, err
+		}
+		defer unmountKnownHosts()
+	}
+
+	git, cleanup, err := newGitCLI("", "", sock, knownHosts, gs.auth, gs.dnsConfig())
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	buf, err := git.run(ctx, "ls-remote", "--heads", "--tags", gs.src.Remote)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list refs of remote %s", urlutil.RedactCredentials(gs.src.Remote))
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotRefs writes the remote's refs to a new snapshot.
+func (gs *gitSourceHandler) snapshotRefs(ctx context.Context, g session.Group, snapshotKey string) (_ cache.ImmutableRef, retErr error) {
+	// the refs listed when computing the cache key, so they match it
+	refs := gs.refs
+
+	newRef, err := gs.cache.New(ctx, nil, g, cache.WithDescription(fmt.Sprintf("git refs for %s", urlutil.RedactCredentials(gs.src.Remote))))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil && newRef != nil {
+			newRef.Release(context.TODO())
+		}
+	}()
+
+	mount, err := newRef.Mount(ctx, false, g)
+	if err != nil {
+		return nil, err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if lm != nil {
+			lm.Unmount()
+		}
+	}()
+
+	if err := os.WriteFile(filepath.Join(dir, RefsFilename), refs, 0644); err != nil {
+		return nil, err
+	}
+	if idmap := mount.IdentityMapping(); idmap != nil {
+		u := idmap.RootPair()
+		if err := os.Lchown(filepath.Join(dir, RefsFilename), u.UID, u.GID); err != nil {
+			return nil, err
+		}
+	}
+
+	lm.Unmount()
+	lm = nil
+
+	snap, err := newRef.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newRef = nil
+
+	md := cacheRefMetadata{snap}
+	if err := md.setGitSnapshot(snapshotKey); err != nil {
+		snap.Release(context.TODO())
+		return nil, err
+	}
+	return snap, nil
+}
+
 func isCommitSHA(str string) bool {
 	return validHex.MatchString(str)
 }
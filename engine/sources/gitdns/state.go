@@ -16,6 +16,16 @@ const AttrNetConfig = "gitdns.netconfig"
 // Git is a helper mimicking the llb.Git function, but with the ability to
 // set additional attributes.
 func State(url, ref string, clientIDs []string, opts ...llb.GitOption) llb.State {
+	return StateWithHack(DaggerGitURLHack{
+		Remote:    url,
+		ClientIDs: clientIDs,
+	}, ref, opts...)
+}
+
+// StateWithHack is like State, but also allows setting checkout options or
+// listing the remote's refs.
+func StateWithHack(hack DaggerGitURLHack, ref string, opts ...llb.GitOption) llb.State {
+	url := hack.Remote
 	remote, err := gitutil.ParseURL(url)
 	if errors.Is(err, gitutil.ErrUnknownProtocol) {
 		url = "https://" + url
@@ -41,14 +51,12 @@ func State(url, ref string, clientIDs []string, opts ...llb.GitOption) llb.State
 	}
 
 	// TODO(vito): replace when custom sources are supported
-	hack, err := buildkit.EncodeIDHack(DaggerGitURLHack{
-		Remote:    url,
-		ClientIDs: clientIDs,
-	})
+	hack.Remote = url
+	encoded, err := buildkit.EncodeIDHack(hack)
 	if err != nil {
 		panic(err)
 	}
-	url = "git://" + hack
+	url = "git://" + encoded
 
 	gi := &llb.GitInfo{
 		AuthHeaderSecret: "GIT_AUTH_HEADER",
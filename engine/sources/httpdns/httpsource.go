@@ -22,6 +22,7 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/pb"
@@ -107,11 +108,14 @@ func (hs *httpSource) Identifier(scheme, ref string, attrs map[string]string, pl
 
 type httpSourceHandler struct {
 	*httpSource
-	src       srchttp.HTTPIdentifier
-	clientIDs []string
-	refID     string
-	cacheKey  digest.Digest
-	sm        *session.Manager
+	src              srchttp.HTTPIdentifier
+	clientIDs        []string
+	headers          []HTTPHeader
+	authHeaderSecret string
+	authHeader       string
+	refID            string
+	cacheKey         digest.Digest
+	sm               *session.Manager
 }
 
 // TODO(vito): this can be cleaned up if/when
@@ -119,6 +123,17 @@ type httpSourceHandler struct {
 type DaggerHTTPURLHack struct {
 	URL       string   `json:"url"`
 	ClientIDs []string `json:"client_ids"`
+
+	// Headers are sent with every request for the URL.
+	Headers []HTTPHeader `json:"headers,omitempty"`
+	// AuthHeaderSecret is the name of a secret whose value is sent as the
+	// Authorization header.
+	AuthHeaderSecret string `json:"auth_header_secret,omitempty"`
+}
+
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 func (hs *httpSource) Resolve(ctx context.Context, id source.Identifier, sm *session.Manager, _ solver.Vertex) (source.SourceInstance, error) {
@@ -139,10 +154,12 @@ func (hs *httpSource) Resolve(ctx context.Context, id source.Identifier, sm *ses
 	}
 
 	return &httpSourceHandler{
-		src:        *httpIdentifier,
-		clientIDs:  clientIDs,
-		httpSource: hs,
-		sm:         sm,
+		src:              *httpIdentifier,
+		clientIDs:        clientIDs,
+		headers:          hack.Headers,
+		authHeaderSecret: hack.AuthHeaderSecret,
+		httpSource:       hs,
+		sm:               sm,
 	}, nil
 }
 
@@ -158,6 +175,38 @@ func (hs *httpSourceHandler) client(g session.Group) *http.Client {
 	return &http.Client{Transport: newTransport(hs.transport, hs.sm, g, &dns)}
 }
 
+// newRequest creates a GET request for the URL with the configured headers.
+func (hs *httpSourceHandler) newRequest(ctx context.Context, g session.Group) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", hs.src.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hs.headers {
+		req.Header.Add(h.Name, h.Value)
+	}
+	if hs.authHeaderSecret != "" {
+		if err := hs.getAuthHeader(ctx, g); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", hs.authHeader)
+	}
+	return req, nil
+}
+
+func (hs *httpSourceHandler) getAuthHeader(ctx context.Context, g session.Group) error {
+	if hs.authHeader != "" {
+		return nil
+	}
+	return hs.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+		dt, err := secrets.GetSecret(ctx, caller, hs.authHeaderSecret)
+		if err != nil {
+			return errors.Wrap(err, "failed to get auth header secret")
+		}
+		hs.authHeader = string(dt)
+		return nil
+	})
+}
+
 // urlHash is internal hash the etag is stored by that doesn't leak outside
 // this package.
 func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
@@ -213,11 +262,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", "", nil, false, errors.Wrapf(err, "failed to search metadata for %s", uh)
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return "", "", nil, false, err
 	}
-	req = req.WithContext(ctx)
 	m := map[string]cacheRefMetadata{}
 
 	// If we request a single ETag in 'If-None-Match', some servers omit the
@@ -293,6 +341,7 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", "", nil, false, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		resp.Body.Close()
 		return "", "", nil, false, errors.Errorf("invalid response status %d", resp.StatusCode)
 	}
 	if resp.StatusCode == http.StatusNotModified {
@@ -464,11 +513,10 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 		}
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
 	client := hs.client(g)
 
@@ -479,6 +527,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.Errorf("invalid response status %d", resp.StatusCode)
+	}
 
 	ref, dgst, err := hs.save(ctx, resp, g)
 	if err != nil {
@@ -486,6 +537,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	}
 	if dgst != hs.cacheKey {
 		ref.Release(context.TODO())
+		if hs.src.Checksum != "" {
+			return nil, errors.Errorf("checksum mismatch for %s: expected %s, got %s", hs.src.URL, hs.src.Checksum, dgst)
+		}
 		return nil, errors.Errorf("digest mismatch %s: %s", dgst, hs.cacheKey)
 	}
 
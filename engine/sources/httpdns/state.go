@@ -13,10 +13,17 @@ const AttrNetConfig = "httpdns.netconfig"
 // State is a helper mimicking the llb.HTTP function, but with the ability to
 // set additional attributes.
 func State(url string, clientIDs []string, opts ...llb.HTTPOption) llb.State {
-	hack, err := buildkit.EncodeIDHack(DaggerHTTPURLHack{
+	return StateWithHack(DaggerHTTPURLHack{
 		URL:       url,
 		ClientIDs: clientIDs,
-	})
+	}, opts...)
+}
+
+// StateWithHack is like State, but also allows setting request headers and
+// an auth header secret.
+func StateWithHack(hack DaggerHTTPURLHack, opts ...llb.HTTPOption) llb.State {
+	url := hack.URL
+	encoded, err := buildkit.EncodeIDHack(hack)
 	if err != nil {
 		panic(err)
 	}
@@ -25,7 +32,7 @@ func State(url string, clientIDs []string, opts ...llb.HTTPOption) llb.State {
 
 	// has to start with https:// for buildkit to recognize the scheme and
 	// associate it to the source
-	url = fmt.Sprintf("%s://%s", srctypes.HTTPSScheme, hack)
+	url = fmt.Sprintf("%s://%s", srctypes.HTTPSScheme, encoded)
 
 	return llb.HTTP(url, opts...)
 }
@@ -90,6 +90,12 @@ func (m Details) View() string {
 		return strings.Repeat("\n", max(0, m.height-1))
 	}
 	headerView := m.headerView()
+	if item, ok := m.item.(*Item); ok && item.Metrics() != nil {
+		metrics := trunc(item.Metrics().String(), m.width)
+		headerView = lipgloss.JoinVertical(lipgloss.Left,
+			headerView,
+			metricsStyle.Render(metrics))
+	}
 
 	m.item.SetHeight(m.height - lipgloss.Height(headerView))
 
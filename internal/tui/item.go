@@ -13,6 +13,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dagger/dagger/engine"
 	"github.com/tonistiigi/units"
 	"github.com/vito/progrock"
 	"github.com/vito/progrock/ui"
@@ -51,6 +52,7 @@ type Item struct {
 	spinner    spinner.Model
 	width      int
 	isInfinite bool
+	metrics    *engine.ExecMetrics
 }
 
 func (i *Item) ID() string            { return i.id }
@@ -63,6 +65,10 @@ func (i *Item) Completed() *time.Time { return i.completed }
 func (i *Item) Cached() bool          { return i.cached }
 func (i *Item) Infinite() bool        { return i.isInfinite }
 
+// Metrics returns the latest resource metrics sampled for the item's exec, if
+// any.
+func (i *Item) Metrics() *engine.ExecMetrics { return i.metrics }
+
 func (i *Item) Error() *string {
 	return i.error
 }
@@ -122,6 +128,17 @@ func (i *Item) UpdateLog(log *progrock.VertexLog) {
 	i.logsModel.Write(log.Data)
 }
 
+func (i *Item) UpdateMeta(meta *progrock.VertexMeta) {
+	if meta.Name != engine.ExecMetricsMeta {
+		return
+	}
+	metrics, err := engine.ExecMetricsFromProto(meta.Data)
+	if err != nil {
+		return
+	}
+	i.metrics = &metrics
+}
+
 func (i *Item) UpdateStatus(task *progrock.VertexTask) {
 	var current = -1
 	for i, s := range i.tasks {
@@ -335,6 +335,14 @@ func (m Model) processUpdate(msg *progrock.StatusUpdate) (tea.Model, tea.Cmd) {
 		item.UpdateLog(l)
 	}
 
+	for _, meta := range msg.Metas {
+		item := m.itemsByID[meta.Vertex]
+		if item == nil {
+			continue
+		}
+		item.UpdateMeta(meta)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
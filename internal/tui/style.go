@@ -120,5 +120,9 @@ var (
 			Padding(0, 1).
 			Foreground(colorForeground)
 
+	metricsStyle = lipgloss.NewStyle().
+			Inline(true).
+			Foreground(colorFaint)
+
 	errorStyle = lipgloss.NewStyle().Inline(true).Foreground(colorFailed)
 )
@@ -47,9 +47,15 @@ func Blob(digest string, size int, mediaType string, uncompressed string) *dagge
 }
 
 // Constructs a cache volume for a given cache key.
-func CacheVolume(key string) *dagger.CacheVolume {
+func CacheVolume(key string, opts ...dagger.CacheVolumeOpts) *dagger.CacheVolume {
 	client := initClient()
-	return client.CacheVolume(key)
+	return client.CacheVolume(key, opts...)
+}
+
+// Lists the cache volumes with contents in the engine's local cache.
+func CacheVolumes(ctx context.Context) ([]dagger.CacheVolume, error) {
+	client := initClient()
+	return client.CacheVolumes(ctx)
 }
 
 // Checks if the current Dagger Engine is compatible with an SDK's required version.
@@ -152,6 +158,18 @@ func LoadDirectoryFromID(id dagger.DirectoryID) *dagger.Directory {
 	return client.LoadDirectoryFromID(id)
 }
 
+// Load a EnumTypeDef from its ID.
+func LoadEnumTypeDefFromID(id dagger.EnumTypeDefID) *dagger.EnumTypeDef {
+	client := initClient()
+	return client.LoadEnumTypeDefFromID(id)
+}
+
+// Load a EnumValueTypeDef from its ID.
+func LoadEnumValueTypeDefFromID(id dagger.EnumValueTypeDefID) *dagger.EnumValueTypeDef {
+	client := initClient()
+	return client.LoadEnumValueTypeDefFromID(id)
+}
+
 // Load a EnvVariable from its ID.
 func LoadEnvVariableFromID(id dagger.EnvVariableID) *dagger.EnvVariable {
 	client := initClient()
@@ -170,6 +188,12 @@ func LoadFileFromID(id dagger.FileID) *dagger.File {
 	return client.LoadFileFromID(id)
 }
 
+// Load a FileInfo from its ID.
+func LoadFileInfoFromID(id dagger.FileInfoID) *dagger.FileInfo {
+	client := initClient()
+	return client.LoadFileInfoFromID(id)
+}
+
 // Load a FunctionArg from its ID.
 func LoadFunctionArgFromID(id dagger.FunctionArgID) *dagger.FunctionArg {
 	client := initClient()
@@ -266,6 +290,12 @@ func LoadPortFromID(id dagger.PortID) *dagger.Port {
 	return client.LoadPortFromID(id)
 }
 
+// Load a ScalarTypeDef from its ID.
+func LoadScalarTypeDefFromID(id dagger.ScalarTypeDefID) *dagger.ScalarTypeDef {
+	client := initClient()
+	return client.LoadScalarTypeDefFromID(id)
+}
+
 // Load a Secret from its ID.
 func LoadSecretFromID(id dagger.SecretID) *dagger.Secret {
 	client := initClient()
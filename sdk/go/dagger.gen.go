@@ -134,19 +134,51 @@ func getCustomError(err error) error {
 		if stderr, ok := ext["stderr"].(string); ok {
 			e.Stderr = stderr
 		}
+		if reason, ok := ext["reason"].(string); ok {
+			e.Reason = ExecErrorReason(reason)
+		}
 		return e
 	}
 
 	return nil
 }
 
+// ExecErrorReason is why an exec operation was stopped, if it didn't simply
+// exit with a non-zero code.
+type ExecErrorReason string
+
+const (
+	// ExecErrorReasonTimeout means the command ran past its timeout and was
+	// killed.
+	ExecErrorReasonTimeout ExecErrorReason = "TIMEOUT"
+
+	// ExecErrorReasonOOMKilled means the command ran past its memory limit and
+	// was killed.
+	ExecErrorReasonOOMKilled ExecErrorReason = "OOM_KILLED"
+)
+
 // ExecError is an API error from an exec operation.
 type ExecError struct {
 	original error
 	Cmd      []string
 	ExitCode int
-	Stdout   string
-	Stderr   string
+	// Reason is set if the command was stopped rather than exiting on its
+	// own, e.g. because it timed out.
+	Reason ExecErrorReason
+	Stdout string
+	Stderr string
+}
+
+// TimedOut returns whether the command was killed for running past its
+// timeout.
+func (e *ExecError) TimedOut() bool {
+	return e.Reason == ExecErrorReasonTimeout
+}
+
+// OOMKilled returns whether the command was killed for running past its
+// memory limit.
+func (e *ExecError) OOMKilled() bool {
+	return e.Reason == ExecErrorReasonOOMKilled
 }
 
 func (e *ExecError) Error() string {
@@ -177,6 +209,12 @@ type ContainerID string
 // The `DirectoryID` scalar type represents an identifier for an object of type Directory.
 type DirectoryID string
 
+// The `EnumTypeDefID` scalar type represents an identifier for an object of type EnumTypeDef.
+type EnumTypeDefID string
+
+// The `EnumValueTypeDefID` scalar type represents an identifier for an object of type EnumValueTypeDef.
+type EnumValueTypeDefID string
+
 // The `EnvVariableID` scalar type represents an identifier for an object of type EnvVariable.
 type EnvVariableID string
 
@@ -186,6 +224,9 @@ type FieldTypeDefID string
 // The `FileID` scalar type represents an identifier for an object of type File.
 type FileID string
 
+// The `FileInfoID` scalar type represents an identifier for an object of type FileInfo.
+type FileInfoID string
+
 // The `FunctionArgID` scalar type represents an identifier for an object of type FunctionArg.
 type FunctionArgID string
 
@@ -242,6 +283,9 @@ type Platform string
 // The `PortID` scalar type represents an identifier for an object of type Port.
 type PortID string
 
+// The `ScalarTypeDefID` scalar type represents an identifier for an object of type ScalarTypeDef.
+type ScalarTypeDefID string
+
 // The `SecretID` scalar type represents an identifier for an object of type Secret.
 type SecretID string
 
@@ -271,6 +315,15 @@ type BuildArg struct {
 	Value string `json:"value"`
 }
 
+// Key value object that represents an HTTP request header.
+type HTTPHeader struct {
+	// The header name.
+	Name string `json:"name"`
+
+	// The header value.
+	Value string `json:"value"`
+}
+
 // Key value object that represents a pipeline label.
 type PipelineLabel struct {
 	// Label name.
@@ -297,7 +350,10 @@ type CacheVolume struct {
 	q *querybuilder.Selection
 	c graphql.Client
 
-	id *CacheVolumeID
+	id    *CacheVolumeID
+	key   *string
+	prune *Void
+	size  *int
 }
 
 // A unique identifier for this CacheVolume.
@@ -340,12 +396,54 @@ func (r *CacheVolume) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// The key the cache volume was constructed with.
+func (r *CacheVolume) Key(ctx context.Context) (string, error) {
+	if r.key != nil {
+		return *r.key, nil
+	}
+	q := r.q.Select("key")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// Clears the contents of the cache volume from the engine's local cache.
+//
+// Contents currently mounted by a running container are kept.
+func (r *CacheVolume) Prune(ctx context.Context) (Void, error) {
+	if r.prune != nil {
+		return *r.prune, nil
+	}
+	q := r.q.Select("prune")
+
+	var response Void
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// The size of the cache volume's contents in the engine's local cache, in bytes.
+func (r *CacheVolume) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.q.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // An OCI-compatible container, also known as a Docker container.
 type Container struct {
 	q *querybuilder.Selection
 	c graphql.Client
 
 	envVariable *string
+	exitCode    *int
 	export      *bool
 	id          *ContainerID
 	imageRef    *string
@@ -367,11 +465,49 @@ func (r *Container) With(f WithContainerFunc) *Container {
 	return f(r)
 }
 
+// ContainerAsServiceOpts contains options for Container.AsService
+type ContainerAsServiceOpts struct {
+	// Maximum time in seconds the service may run for before being killed.
+	//
+	// If 0, the service may run indefinitely.
+	Timeout int
+	// Maximum amount of memory in bytes the service may use before being killed.
+	//
+	// If 0, memory is not limited.
+	MemoryLimit int
+	// Relative CPU weight of the service, compared to other containers (e.g., 1024).
+	//
+	// If 0, the default weight is used.
+	CPUShares int
+	// Maximum number of processes the service may run.
+	//
+	// If 0, the number of processes is not limited.
+	PidsLimit int
+}
+
 // Turn the container into a Service.
 //
 // Be sure to set any exposed ports before this conversion.
-func (r *Container) AsService() *Service {
+func (r *Container) AsService(opts ...ContainerAsServiceOpts) *Service {
 	q := r.q.Select("asService")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `cpuShares` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUShares) {
+			q = q.Arg("cpuShares", opts[i].CPUShares)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+	}
 
 	return &Service{
 		q: q,
@@ -546,6 +682,21 @@ func (r *Container) EnvVariables(ctx context.Context) ([]EnvVariable, error) {
 	return convert(response), nil
 }
 
+// The exit code of the last executed command.
+//
+// Will execute default command if none is set, or error if there's no default.
+func (r *Container) ExitCode(ctx context.Context) (int, error) {
+	if r.exitCode != nil {
+		return *r.exitCode, nil
+	}
+	q := r.q.Select("exitCode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // EXPERIMENTAL API! Subject to change/removal at any time.
 //
 // Configures all available GPUs on the host to be accessible to this container.
@@ -1118,6 +1269,26 @@ type ContainerWithExecOpts struct {
 	ExperimentalPrivilegedNesting bool
 	// Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
 	InsecureRootCapabilities bool
+	// Exit codes this command is allowed to exit with without error.
+	//
+	// With FAILURE or ANY, a failing command's filesystem and output stay available to the rest of the pipeline, and its status can be read with exitCode.
+	Expect ReturnType
+	// Maximum time in seconds the command may run for before being killed.
+	//
+	// If 0, the command may run indefinitely.
+	Timeout int
+	// Maximum amount of memory in bytes the container may use before the command is killed.
+	//
+	// If 0, memory is not limited.
+	MemoryLimit int
+	// Relative CPU weight of the container, compared to other containers (e.g., 1024).
+	//
+	// If 0, the default weight is used.
+	CPUShares int
+	// Maximum number of processes the container may run.
+	//
+	// If 0, the number of processes is not limited.
+	PidsLimit int
 }
 
 // Retrieves this container after executing the specified command inside it.
@@ -1148,6 +1319,26 @@ func (r *Container) WithExec(args []string, opts ...ContainerWithExecOpts) *Cont
 		if !querybuilder.IsZeroValue(opts[i].InsecureRootCapabilities) {
 			q = q.Arg("insecureRootCapabilities", opts[i].InsecureRootCapabilities)
 		}
+		// `expect` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Expect) {
+			q = q.Arg("expect", opts[i].Expect)
+		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `cpuShares` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUShares) {
+			q = q.Arg("cpuShares", opts[i].CPUShares)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
 	}
 	q = q.Arg("args", args)
 
@@ -1716,6 +1907,7 @@ type Directory struct {
 	q *querybuilder.Selection
 	c graphql.Client
 
+	digest *string
 	export *bool
 	id     *DirectoryID
 	sync   *DirectoryID
@@ -1729,6 +1921,48 @@ func (r *Directory) With(f WithDirectoryFunc) *Directory {
 	return f(r)
 }
 
+// DirectoryAsArchiveOpts contains options for Directory.AsArchive
+type DirectoryAsArchiveOpts struct {
+	// The format of the archive.
+	Format ArchiveFormat
+	// The compression of the archive.
+	//
+	// Only supported for tar archives.
+	Compression ArchiveCompression
+	// Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+	Exclude []string
+	// Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+	Include []string
+}
+
+// Packs the contents of this directory into an archive.
+func (r *Directory) AsArchive(opts ...DirectoryAsArchiveOpts) *File {
+	q := r.q.Select("asArchive")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `format` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Format) {
+			q = q.Arg("format", opts[i].Format)
+		}
+		// `compression` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Compression) {
+			q = q.Arg("compression", opts[i].Compression)
+		}
+		// `exclude` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Exclude) {
+			q = q.Arg("exclude", opts[i].Exclude)
+		}
+		// `include` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Include) {
+			q = q.Arg("include", opts[i].Include)
+		}
+	}
+
+	return &File{
+		q: q,
+		c: r.c,
+	}
+}
+
 // DirectoryAsModuleOpts contains options for Directory.AsModule
 type DirectoryAsModuleOpts struct {
 	// An optional subpath of the directory which contains the module's source code.
@@ -1767,6 +2001,33 @@ func (r *Directory) Diff(other *Directory) *Directory {
 	}
 }
 
+// DirectoryDigestOpts contains options for Directory.Digest
+type DirectoryDigestOpts struct {
+	// If true, permissions, ownership and modification times are not included in the digest.
+	ExcludeMetadata bool
+}
+
+// Returns a sha256 digest of the contents of this directory.
+//
+// The digest covers the path, type and contents of every entry, so directories with identical contents have the same digest no matter how they were built.
+func (r *Directory) Digest(ctx context.Context, opts ...DirectoryDigestOpts) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.q.Select("digest")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `excludeMetadata` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExcludeMetadata) {
+			q = q.Arg("excludeMetadata", opts[i].ExcludeMetadata)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // Retrieves a directory at the given path.
 func (r *Directory) Directory(path string) *Directory {
 	q := r.q.Select("directory")
@@ -1848,12 +2109,74 @@ func (r *Directory) Entries(ctx context.Context, opts ...DirectoryEntriesOpts) (
 	return response, q.Execute(ctx, r.c)
 }
 
+// DirectoryEntriesWithInfoOpts contains options for Directory.EntriesWithInfo
+type DirectoryEntriesWithInfoOpts struct {
+	// Location of the directory to look at (e.g., "/src").
+	Path string
+}
+
+// Returns a list of files and directories at the given path, along with their type, permissions, ownership and link target.
+//
+// Symlinks are not followed.
+func (r *Directory) EntriesWithInfo(ctx context.Context, opts ...DirectoryEntriesWithInfoOpts) ([]FileInfo, error) {
+	q := r.q.Select("entriesWithInfo")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `path` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Path) {
+			q = q.Arg("path", opts[i].Path)
+		}
+	}
+
+	q = q.Select("id")
+
+	type entriesWithInfo struct {
+		Id FileInfoID
+	}
+
+	convert := func(fields []entriesWithInfo) []FileInfo {
+		out := []FileInfo{}
+
+		for i := range fields {
+			val := FileInfo{id: &fields[i].Id}
+			val.q = querybuilder.Query().Select("loadFileInfoFromID").Arg("id", fields[i].Id)
+			val.c = r.c
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []entriesWithInfo
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// DirectoryExportOpts contains options for Directory.Export
+type DirectoryExportOpts struct {
+	// Remove files under the path that aren't in the directory, so that the path mirrors it.
+	//
+	// Wiping the current working directory or any of its parents, or the root of a git repository, is refused. Check the changes with exportDryRun first.
+	Wipe bool
+}
+
 // Writes the contents of the directory to a path on the host.
-func (r *Directory) Export(ctx context.Context, path string) (bool, error) {
+func (r *Directory) Export(ctx context.Context, path string, opts ...DirectoryExportOpts) (bool, error) {
 	if r.export != nil {
 		return *r.export, nil
 	}
 	q := r.q.Select("export")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `wipe` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Wipe) {
+			q = q.Arg("wipe", opts[i].Wipe)
+		}
+	}
 	q = q.Arg("path", path)
 
 	var response bool
@@ -1862,6 +2185,31 @@ func (r *Directory) Export(ctx context.Context, path string) (bool, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
+// DirectoryExportDryRunOpts contains options for Directory.ExportDryRun
+type DirectoryExportDryRunOpts struct {
+	// Remove files under the path that aren't in the directory, so that the path mirrors it.
+	Wipe bool
+}
+
+// Returns the changes that exporting the directory to a path on the host would make, without making them.
+//
+// Each change is a path relative to the exported path, prefixed with "A " if it would be added, "M " if it would be modified or "D " if it would be removed.
+func (r *Directory) ExportDryRun(ctx context.Context, path string, opts ...DirectoryExportDryRunOpts) ([]string, error) {
+	q := r.q.Select("exportDryRun")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `wipe` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Wipe) {
+			q = q.Arg("wipe", opts[i].Wipe)
+		}
+	}
+	q = q.Arg("path", path)
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // Retrieves a file at the given path.
 func (r *Directory) File(path string) *File {
 	q := r.q.Select("file")
@@ -1953,6 +2301,19 @@ func (r *Directory) Pipeline(name string, opts ...DirectoryPipelineOpts) *Direct
 	}
 }
 
+// Retrieves information about the file, directory or symlink at the given path.
+//
+// Symlinks are not followed.
+func (r *Directory) Stat(path string) *FileInfo {
+	q := r.q.Select("stat")
+	q = q.Arg("path", path)
+
+	return &FileInfo{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Force evaluation in the engine.
 func (r *Directory) Sync(ctx context.Context) (*Directory, error) {
 	q := r.q.Select("sync")
@@ -2063,6 +2424,18 @@ func (r *Directory) WithNewFile(path string, contents string, opts ...DirectoryW
 	}
 }
 
+// Retrieves this directory plus a symlink created at the given path.
+func (r *Directory) WithSymlink(target string, linkName string) *Directory {
+	q := r.q.Select("withSymlink")
+	q = q.Arg("target", target)
+	q = q.Arg("linkName", linkName)
+
+	return &Directory{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Retrieves this directory with all file/dir timestamps set to the given time.
 func (r *Directory) WithTimestamps(timestamp int) *Directory {
 	q := r.q.Select("withTimestamps")
@@ -2096,41 +2469,54 @@ func (r *Directory) WithoutFile(path string) *Directory {
 	}
 }
 
-// An environment variable name and value.
-type EnvVariable struct {
+// A definition of a custom enum defined in a Module.
+type EnumTypeDef struct {
 	q *querybuilder.Selection
 	c graphql.Client
 
-	id    *EnvVariableID
-	name  *string
-	value *string
+	description      *string
+	id               *EnumTypeDefID
+	name             *string
+	sourceModuleName *string
 }
 
-// A unique identifier for this EnvVariable.
-func (r *EnvVariable) ID(ctx context.Context) (EnvVariableID, error) {
+func (r *EnumTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.q.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// A unique identifier for this EnumTypeDef.
+func (r *EnumTypeDef) ID(ctx context.Context) (EnumTypeDefID, error) {
 	if r.id != nil {
 		return *r.id, nil
 	}
 	q := r.q.Select("id")
 
-	var response EnvVariableID
+	var response EnumTypeDefID
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx, r.c)
 }
 
 // XXX_GraphQLType is an internal function. It returns the native GraphQL type name
-func (r *EnvVariable) XXX_GraphQLType() string {
-	return "EnvVariable"
+func (r *EnumTypeDef) XXX_GraphQLType() string {
+	return "EnumTypeDef"
 }
 
 // XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
-func (r *EnvVariable) XXX_GraphQLIDType() string {
-	return "EnvVariableID"
+func (r *EnumTypeDef) XXX_GraphQLIDType() string {
+	return "EnumTypeDefID"
 }
 
 // XXX_GraphQLID is an internal function. It returns the underlying type ID
-func (r *EnvVariable) XXX_GraphQLID(ctx context.Context) (string, error) {
+func (r *EnumTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
 	id, err := r.ID(ctx)
 	if err != nil {
 		return "", err
@@ -2138,7 +2524,7 @@ func (r *EnvVariable) XXX_GraphQLID(ctx context.Context) (string, error) {
 	return string(id), nil
 }
 
-func (r *EnvVariable) MarshalJSON() ([]byte, error) {
+func (r *EnumTypeDef) MarshalJSON() ([]byte, error) {
 	id, err := r.ID(context.Background())
 	if err != nil {
 		return nil, err
@@ -2146,7 +2532,7 @@ func (r *EnvVariable) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
-func (r *EnvVariable) Name(ctx context.Context) (string, error) {
+func (r *EnumTypeDef) Name(ctx context.Context) (string, error) {
 	if r.name != nil {
 		return *r.name, nil
 	}
@@ -2158,11 +2544,11 @@ func (r *EnvVariable) Name(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
-func (r *EnvVariable) Value(ctx context.Context) (string, error) {
-	if r.value != nil {
-		return *r.value, nil
+func (r *EnumTypeDef) SourceModuleName(ctx context.Context) (string, error) {
+	if r.sourceModuleName != nil {
+		return *r.sourceModuleName, nil
 	}
-	q := r.q.Select("value")
+	q := r.q.Select("sourceModuleName")
 
 	var response string
 
@@ -2170,19 +2556,52 @@ func (r *EnvVariable) Value(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
-// A definition of a field on a custom object defined in a Module.
+func (r *EnumTypeDef) Values(ctx context.Context) ([]EnumValueTypeDef, error) {
+	q := r.q.Select("values")
+
+	q = q.Select("id")
+
+	type values struct {
+		Id EnumValueTypeDefID
+	}
+
+	convert := func(fields []values) []EnumValueTypeDef {
+		out := []EnumValueTypeDef{}
+
+		for i := range fields {
+			val := EnumValueTypeDef{id: &fields[i].Id}
+			val.q = querybuilder.Query().Select("loadEnumValueTypeDefFromID").Arg("id", fields[i].Id)
+			val.c = r.c
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []values
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// A definition of a value in a custom enum defined in a Module.
 //
-// A field on an object has a static value, as opposed to a function on an object whose value is computed by invoking code (and can accept arguments).
-type FieldTypeDef struct {
+// The name of the value is what is sent over the wire, so it must be a valid GraphQL enum value.
+type EnumValueTypeDef struct {
 	q *querybuilder.Selection
 	c graphql.Client
 
 	description *string
-	id          *FieldTypeDefID
+	id          *EnumValueTypeDefID
 	name        *string
 }
 
-func (r *FieldTypeDef) Description(ctx context.Context) (string, error) {
+func (r *EnumValueTypeDef) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
 		return *r.description, nil
 	}
@@ -2194,8 +2613,158 @@ func (r *FieldTypeDef) Description(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
-// A unique identifier for this FieldTypeDef.
-func (r *FieldTypeDef) ID(ctx context.Context) (FieldTypeDefID, error) {
+// A unique identifier for this EnumValueTypeDef.
+func (r *EnumValueTypeDef) ID(ctx context.Context) (EnumValueTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.q.Select("id")
+
+	var response EnumValueTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EnumValueTypeDef) XXX_GraphQLType() string {
+	return "EnumValueTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EnumValueTypeDef) XXX_GraphQLIDType() string {
+	return "EnumValueTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EnumValueTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EnumValueTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+func (r *EnumValueTypeDef) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.q.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// An environment variable name and value.
+type EnvVariable struct {
+	q *querybuilder.Selection
+	c graphql.Client
+
+	id    *EnvVariableID
+	name  *string
+	value *string
+}
+
+// A unique identifier for this EnvVariable.
+func (r *EnvVariable) ID(ctx context.Context) (EnvVariableID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.q.Select("id")
+
+	var response EnvVariableID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *EnvVariable) XXX_GraphQLType() string {
+	return "EnvVariable"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *EnvVariable) XXX_GraphQLIDType() string {
+	return "EnvVariableID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *EnvVariable) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *EnvVariable) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+func (r *EnvVariable) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.q.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *EnvVariable) Value(ctx context.Context) (string, error) {
+	if r.value != nil {
+		return *r.value, nil
+	}
+	q := r.q.Select("value")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// A definition of a field on a custom object defined in a Module.
+//
+// A field on an object has a static value, as opposed to a function on an object whose value is computed by invoking code (and can accept arguments).
+type FieldTypeDef struct {
+	q *querybuilder.Selection
+	c graphql.Client
+
+	description *string
+	id          *FieldTypeDefID
+	name        *string
+}
+
+func (r *FieldTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.q.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// A unique identifier for this FieldTypeDef.
+func (r *FieldTypeDef) ID(ctx context.Context) (FieldTypeDefID, error) {
 	if r.id != nil {
 		return *r.id, nil
 	}
@@ -2261,6 +2830,7 @@ type File struct {
 	c graphql.Client
 
 	contents *string
+	digest   *string
 	export   *bool
 	id       *FileID
 	name     *string
@@ -2289,6 +2859,33 @@ func (r *File) Contents(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
+// FileDigestOpts contains options for File.Digest
+type FileDigestOpts struct {
+	// If true, permissions, ownership and modification times are not included in the digest.
+	ExcludeMetadata bool
+}
+
+// Returns a sha256 digest of the file.
+//
+// With excludeMetadata set, this is the digest of the file's contents, i.e. the same as sha256sum.
+func (r *File) Digest(ctx context.Context, opts ...FileDigestOpts) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.q.Select("digest")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `excludeMetadata` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExcludeMetadata) {
+			q = q.Arg("excludeMetadata", opts[i].ExcludeMetadata)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // FileExportOpts contains options for File.Export
 type FileExportOpts struct {
 	// If allowParentDirPath is true, the path argument can be a directory path, in which case the file will be created in that directory.
@@ -2315,6 +2912,44 @@ func (r *File) Export(ctx context.Context, path string, opts ...FileExportOpts)
 	return response, q.Execute(ctx, r.c)
 }
 
+// FileExtractOpts contains options for File.Extract
+type FileExtractOpts struct {
+	// The format of the archive.
+	//
+	// If not set, it is detected from the archive's contents. The compression of tar archives is always detected.
+	Format ArchiveFormat
+	// Exclude entries that match the given pattern (e.g., ["node_modules/", ".git*"]).
+	Exclude []string
+	// Include only entries that match the given pattern (e.g., ["app/", "package.*"]).
+	Include []string
+}
+
+// Extracts this file as an archive into a new directory.
+//
+// Supports tar archives, optionally compressed with gzip or zstd, and zip archives.
+func (r *File) Extract(opts ...FileExtractOpts) *Directory {
+	q := r.q.Select("extract")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `format` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Format) {
+			q = q.Arg("format", opts[i].Format)
+		}
+		// `exclude` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Exclude) {
+			q = q.Arg("exclude", opts[i].Exclude)
+		}
+		// `include` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Include) {
+			q = q.Arg("include", opts[i].Include)
+		}
+	}
+
+	return &Directory{
+		q: q,
+		c: r.c,
+	}
+}
+
 // A unique identifier for this File.
 func (r *File) ID(ctx context.Context) (FileID, error) {
 	if r.id != nil {
@@ -2381,6 +3016,16 @@ func (r *File) Size(ctx context.Context) (int, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
+// Retrieves information about the file, such as its type, permissions and ownership.
+func (r *File) Stat() *FileInfo {
+	q := r.q.Select("stat")
+
+	return &FileInfo{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Force evaluation in the engine.
 func (r *File) Sync(ctx context.Context) (*File, error) {
 	q := r.q.Select("sync")
@@ -2399,6 +3044,145 @@ func (r *File) WithTimestamps(timestamp int) *File {
 	}
 }
 
+// Information about a file, directory or symlink.
+type FileInfo struct {
+	q *querybuilder.Selection
+	c graphql.Client
+
+	fileType    *FileType
+	gid         *int
+	id          *FileInfoID
+	linkTarget  *string
+	name        *string
+	permissions *int
+	size        *int
+	uid         *int
+}
+
+func (r *FileInfo) FileType(ctx context.Context) (FileType, error) {
+	if r.fileType != nil {
+		return *r.fileType, nil
+	}
+	q := r.q.Select("fileType")
+
+	var response FileType
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *FileInfo) Gid(ctx context.Context) (int, error) {
+	if r.gid != nil {
+		return *r.gid, nil
+	}
+	q := r.q.Select("gid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// A unique identifier for this FileInfo.
+func (r *FileInfo) ID(ctx context.Context) (FileInfoID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.q.Select("id")
+
+	var response FileInfoID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *FileInfo) XXX_GraphQLType() string {
+	return "FileInfo"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *FileInfo) XXX_GraphQLIDType() string {
+	return "FileInfoID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *FileInfo) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *FileInfo) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+func (r *FileInfo) LinkTarget(ctx context.Context) (string, error) {
+	if r.linkTarget != nil {
+		return *r.linkTarget, nil
+	}
+	q := r.q.Select("linkTarget")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *FileInfo) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.q.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *FileInfo) Permissions(ctx context.Context) (int, error) {
+	if r.permissions != nil {
+		return *r.permissions, nil
+	}
+	q := r.q.Select("permissions")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *FileInfo) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.q.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *FileInfo) UID(ctx context.Context) (int, error) {
+	if r.uid != nil {
+		return *r.uid, nil
+	}
+	q := r.q.Select("uid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // Function represents a resolver provided by a Module.
 //
 // A function always evaluates against a parent object and is given a set of named arguments.
@@ -3047,6 +3831,16 @@ type GitRefTreeOpts struct {
 	SSHKnownHosts string
 	// DEPRECATED: This option should be passed to `git` instead.
 	SSHAuthSocket *Socket
+	// Number of commits of history to fetch.
+	//
+	// Only the tip of the ref is fetched by default, unless it's a commit.
+	Depth int
+	// Only check out these paths (e.g., ["docs", "go.mod"]).
+	SparsePaths []string
+	// Skip checking out submodules.
+	SkipSubmodules bool
+	// Replace Git LFS pointer files with their contents.
+	LFS bool
 }
 
 // The filesystem tree at this ref.
@@ -3061,6 +3855,22 @@ func (r *GitRef) Tree(opts ...GitRefTreeOpts) *Directory {
 		if !querybuilder.IsZeroValue(opts[i].SSHAuthSocket) {
 			q = q.Arg("sshAuthSocket", opts[i].SSHAuthSocket)
 		}
+		// `depth` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Depth) {
+			q = q.Arg("depth", opts[i].Depth)
+		}
+		// `sparsePaths` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SparsePaths) {
+			q = q.Arg("sparsePaths", opts[i].SparsePaths)
+		}
+		// `skipSubmodules` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SkipSubmodules) {
+			q = q.Arg("skipSubmodules", opts[i].SkipSubmodules)
+		}
+		// `lfs` optional argument
+		if !querybuilder.IsZeroValue(opts[i].LFS) {
+			q = q.Arg("lfs", opts[i].LFS)
+		}
 	}
 
 	return &Directory{
@@ -3088,6 +3898,16 @@ func (r *GitRepository) Branch(name string) *GitRef {
 	}
 }
 
+// Returns the names of the repository's branches.
+func (r *GitRepository) Branches(ctx context.Context) ([]string, error) {
+	q := r.q.Select("branches")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // Returns details of a commit.
 func (r *GitRepository) Commit(id string) *GitRef {
 	q := r.q.Select("commit")
@@ -3150,6 +3970,28 @@ func (r *GitRepository) Tag(name string) *GitRef {
 	}
 }
 
+// GitRepositoryTagsOpts contains options for GitRepository.Tags
+type GitRepositoryTagsOpts struct {
+	// Glob pattern to filter tags by (e.g., "v1.*").
+	Pattern string
+}
+
+// Returns the names of the repository's tags.
+func (r *GitRepository) Tags(ctx context.Context, opts ...GitRepositoryTagsOpts) ([]string, error) {
+	q := r.q.Select("tags")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `pattern` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Pattern) {
+			q = q.Arg("pattern", opts[i].Pattern)
+		}
+	}
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // Information about the host environment.
 type Host struct {
 	q *querybuilder.Selection
@@ -3164,6 +4006,14 @@ type HostDirectoryOpts struct {
 	Exclude []string
 	// Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
 	Include []string
+	// Also exclude artifacts that match the patterns in the .daggerignore file at the root of the directory, which uses the same syntax as .gitignore.
+	//
+	// Only the file at the root is read; .daggerignore files in subdirectories are not honored.
+	Daggerignore bool
+	// Also exclude artifacts that match the patterns in the .gitignore file at the root of the directory.
+	//
+	// Only the file at the root is read; .gitignore files in subdirectories are not honored.
+	Gitignore bool
 }
 
 // Accesses a directory on the host.
@@ -3178,6 +4028,14 @@ func (r *Host) Directory(path string, opts ...HostDirectoryOpts) *Directory {
 		if !querybuilder.IsZeroValue(opts[i].Include) {
 			q = q.Arg("include", opts[i].Include)
 		}
+		// `daggerignore` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Daggerignore) {
+			q = q.Arg("daggerignore", opts[i].Daggerignore)
+		}
+		// `gitignore` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Gitignore) {
+			q = q.Arg("gitignore", opts[i].Gitignore)
+		}
 	}
 	q = q.Arg("path", path)
 
@@ -3747,6 +4605,39 @@ func (r *Module) Description(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx, r.c)
 }
 
+func (r *Module) Enums(ctx context.Context) ([]TypeDef, error) {
+	q := r.q.Select("enums")
+
+	q = q.Select("id")
+
+	type enums struct {
+		Id TypeDefID
+	}
+
+	convert := func(fields []enums) []TypeDef {
+		out := []TypeDef{}
+
+		for i := range fields {
+			val := TypeDef{id: &fields[i].Id}
+			val.q = querybuilder.Query().Select("loadTypeDefFromID").Arg("id", fields[i].Id)
+			val.c = r.c
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []enums
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 func (r *Module) GeneratedCode() *GeneratedCode {
 	q := r.q.Select("generatedCode")
 
@@ -3884,6 +4775,39 @@ func (r *Module) Objects(ctx context.Context) ([]TypeDef, error) {
 	return convert(response), nil
 }
 
+func (r *Module) Scalars(ctx context.Context) ([]TypeDef, error) {
+	q := r.q.Select("scalars")
+
+	q = q.Select("id")
+
+	type scalars struct {
+		Id TypeDefID
+	}
+
+	convert := func(fields []scalars) []TypeDef {
+		out := []TypeDef{}
+
+		for i := range fields {
+			val := TypeDef{id: &fields[i].Id}
+			val.q = querybuilder.Query().Select("loadTypeDefFromID").Arg("id", fields[i].Id)
+			val.c = r.c
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []scalars
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 func (r *Module) SDK(ctx context.Context) (string, error) {
 	if r.sdk != nil {
 		return *r.sdk, nil
@@ -3943,6 +4867,18 @@ func (r *Module) WithDescription(description string) *Module {
 	}
 }
 
+// This module plus the given Enum type and associated values
+func (r *Module) WithEnum(enum *TypeDef) *Module {
+	assertNotNil("enum", enum)
+	q := r.q.Select("withEnum")
+	q = q.Arg("enum", enum)
+
+	return &Module{
+		q: q,
+		c: r.c,
+	}
+}
+
 // This module plus the given Interface type and associated functions
 func (r *Module) WithInterface(iface *TypeDef) *Module {
 	assertNotNil("iface", iface)
@@ -3967,6 +4903,18 @@ func (r *Module) WithObject(object *TypeDef) *Module {
 	}
 }
 
+// This module plus the given Scalar type
+func (r *Module) WithScalar(scalar *TypeDef) *Module {
+	assertNotNil("scalar", scalar)
+	q := r.q.Select("withScalar")
+	q = q.Arg("scalar", scalar)
+
+	return &Module{
+		q: q,
+		c: r.c,
+	}
+}
+
 // ModuleWithSourceOpts contains options for Module.WithSource
 type ModuleWithSourceOpts struct {
 	// An optional subpath of the directory which contains the module's source code.
@@ -4394,9 +5342,33 @@ func (r *Client) Blob(digest string, size int, mediaType string, uncompressed st
 	}
 }
 
+// CacheVolumeOpts contains options for Client.CacheVolume
+type CacheVolumeOpts struct {
+	// Maximum size of the cache volume's contents, in bytes. If the contents exceed it when an exec mounts the volume, they are wiped entirely rather than trimmed.
+	//
+	// The size is only checked when the volume is mounted, so an exec may grow the contents past it.
+	//
+	// Set to 0 for no limit.
+	MaxSize int
+	// Number of seconds the cache volume's contents are kept once they stop being used. Older contents are wiped entirely when an exec next mounts the volume.
+	//
+	// Set to 0 for no limit.
+	TTL int
+}
+
 // Constructs a cache volume for a given cache key.
-func (r *Client) CacheVolume(key string) *CacheVolume {
+func (r *Client) CacheVolume(key string, opts ...CacheVolumeOpts) *CacheVolume {
 	q := r.q.Select("cacheVolume")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `maxSize` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MaxSize) {
+			q = q.Arg("maxSize", opts[i].MaxSize)
+		}
+		// `ttl` optional argument
+		if !querybuilder.IsZeroValue(opts[i].TTL) {
+			q = q.Arg("ttl", opts[i].TTL)
+		}
+	}
 	q = q.Arg("key", key)
 
 	return &CacheVolume{
@@ -4405,6 +5377,40 @@ func (r *Client) CacheVolume(key string) *CacheVolume {
 	}
 }
 
+// Lists the cache volumes with contents in the engine's local cache.
+func (r *Client) CacheVolumes(ctx context.Context) ([]CacheVolume, error) {
+	q := r.q.Select("cacheVolumes")
+
+	q = q.Select("id")
+
+	type cacheVolumes struct {
+		Id CacheVolumeID
+	}
+
+	convert := func(fields []cacheVolumes) []CacheVolume {
+		out := []CacheVolume{}
+
+		for i := range fields {
+			val := CacheVolume{id: &fields[i].Id}
+			val.q = querybuilder.Query().Select("loadCacheVolumeFromID").Arg("id", fields[i].Id)
+			val.c = r.c
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []cacheVolumes
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 // Checks if the current Dagger Engine is compatible with an SDK's required version.
 func (r *Client) CheckVersionCompatibility(ctx context.Context, version string) (bool, error) {
 	q := r.q.Select("checkVersionCompatibility")
@@ -4580,6 +5586,8 @@ type GitOpts struct {
 	SSHKnownHosts string
 	// Set SSH auth socket
 	SSHAuthSocket *Socket
+	// A secret containing a token used to authenticate to an HTTPS remote.
+	HTTPAuthToken *Secret
 }
 
 // Queries a Git repository.
@@ -4602,6 +5610,10 @@ func (r *Client) Git(url string, opts ...GitOpts) *GitRepository {
 		if !querybuilder.IsZeroValue(opts[i].SSHAuthSocket) {
 			q = q.Arg("sshAuthSocket", opts[i].SSHAuthSocket)
 		}
+		// `httpAuthToken` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HTTPAuthToken) {
+			q = q.Arg("httpAuthToken", opts[i].HTTPAuthToken)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -4625,6 +5637,18 @@ func (r *Client) Host() *Host {
 type HTTPOpts struct {
 	// A service which must be started before the URL is fetched.
 	ExperimentalServiceHost *Service
+	// Expected digest of the content (e.g., "sha256:...").
+	//
+	// The download fails if the content doesn't match, and a matching download is never fetched again.
+	Checksum string
+	// Headers to send with the request.
+	Headers []HTTPHeader
+	// A secret whose value is sent as the Authorization header.
+	AuthHeader *Secret
+	// Name of the downloaded file.
+	Name string
+	// Permission given to the downloaded file (e.g., 0600).
+	Permissions int
 }
 
 // Returns a file containing an http remote url content.
@@ -4635,6 +5659,26 @@ func (r *Client) HTTP(url string, opts ...HTTPOpts) *File {
 		if !querybuilder.IsZeroValue(opts[i].ExperimentalServiceHost) {
 			q = q.Arg("experimentalServiceHost", opts[i].ExperimentalServiceHost)
 		}
+		// `checksum` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Checksum) {
+			q = q.Arg("checksum", opts[i].Checksum)
+		}
+		// `headers` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Headers) {
+			q = q.Arg("headers", opts[i].Headers)
+		}
+		// `authHeader` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AuthHeader) {
+			q = q.Arg("authHeader", opts[i].AuthHeader)
+		}
+		// `name` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Name) {
+			q = q.Arg("name", opts[i].Name)
+		}
+		// `permissions` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Permissions) {
+			q = q.Arg("permissions", opts[i].Permissions)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -4677,6 +5721,28 @@ func (r *Client) LoadDirectoryFromID(id DirectoryID) *Directory {
 	}
 }
 
+// Load a EnumTypeDef from its ID.
+func (r *Client) LoadEnumTypeDefFromID(id EnumTypeDefID) *EnumTypeDef {
+	q := r.q.Select("loadEnumTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &EnumTypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
+// Load a EnumValueTypeDef from its ID.
+func (r *Client) LoadEnumValueTypeDefFromID(id EnumValueTypeDefID) *EnumValueTypeDef {
+	q := r.q.Select("loadEnumValueTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &EnumValueTypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Load a EnvVariable from its ID.
 func (r *Client) LoadEnvVariableFromID(id EnvVariableID) *EnvVariable {
 	q := r.q.Select("loadEnvVariableFromID")
@@ -4710,6 +5776,17 @@ func (r *Client) LoadFileFromID(id FileID) *File {
 	}
 }
 
+// Load a FileInfo from its ID.
+func (r *Client) LoadFileInfoFromID(id FileInfoID) *FileInfo {
+	q := r.q.Select("loadFileInfoFromID")
+	q = q.Arg("id", id)
+
+	return &FileInfo{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Load a FunctionArg from its ID.
 func (r *Client) LoadFunctionArgFromID(id FunctionArgID) *FunctionArg {
 	q := r.q.Select("loadFunctionArgFromID")
@@ -4886,6 +5963,17 @@ func (r *Client) LoadPortFromID(id PortID) *Port {
 	}
 }
 
+// Load a ScalarTypeDef from its ID.
+func (r *Client) LoadScalarTypeDefFromID(id ScalarTypeDefID) *ScalarTypeDef {
+	q := r.q.Select("loadScalarTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &ScalarTypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
 // Load a Secret from its ID.
 func (r *Client) LoadSecretFromID(id SecretID) *Secret {
 	q := r.q.Select("loadSecretFromID")
@@ -5051,6 +6139,93 @@ func (r *Client) TypeDef() *TypeDef {
 	}
 }
 
+// A definition of a custom scalar defined in a Module.
+type ScalarTypeDef struct {
+	q *querybuilder.Selection
+	c graphql.Client
+
+	description      *string
+	id               *ScalarTypeDefID
+	name             *string
+	sourceModuleName *string
+}
+
+func (r *ScalarTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.q.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// A unique identifier for this ScalarTypeDef.
+func (r *ScalarTypeDef) ID(ctx context.Context) (ScalarTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.q.Select("id")
+
+	var response ScalarTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *ScalarTypeDef) XXX_GraphQLType() string {
+	return "ScalarTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *ScalarTypeDef) XXX_GraphQLIDType() string {
+	return "ScalarTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *ScalarTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *ScalarTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+func (r *ScalarTypeDef) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.q.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
+func (r *ScalarTypeDef) SourceModuleName(ctx context.Context) (string, error) {
+	if r.sourceModuleName != nil {
+		return *r.sourceModuleName, nil
+	}
+	q := r.q.Select("sourceModuleName")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx, r.c)
+}
+
 // A reference to a secret value, which can be handled more safely than the value itself.
 type Secret struct {
 	q *querybuilder.Selection
@@ -5422,6 +6597,15 @@ func (r *TypeDef) With(f WithTypeDefFunc) *TypeDef {
 	return f(r)
 }
 
+func (r *TypeDef) AsEnum() *EnumTypeDef {
+	q := r.q.Select("asEnum")
+
+	return &EnumTypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
 func (r *TypeDef) AsInput() *InputTypeDef {
 	q := r.q.Select("asInput")
 
@@ -5458,6 +6642,15 @@ func (r *TypeDef) AsObject() *ObjectTypeDef {
 	}
 }
 
+func (r *TypeDef) AsScalar() *ScalarTypeDef {
+	q := r.q.Select("asScalar")
+
+	return &ScalarTypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
 // A unique identifier for this TypeDef.
 func (r *TypeDef) ID(ctx context.Context) (TypeDefID, error) {
 	if r.id != nil {
@@ -5534,6 +6727,54 @@ func (r *TypeDef) WithConstructor(function *Function) *TypeDef {
 	}
 }
 
+// TypeDefWithEnumOpts contains options for TypeDef.WithEnum
+type TypeDefWithEnumOpts struct {
+	// A doc string for the enum, if any
+	Description string
+}
+
+// Returns a TypeDef of kind Enum with the provided name.
+//
+// Note that an enum's values may be omitted if the intent is only to refer to an enum. This is how functions are able to return their own, or any other circular reference.
+func (r *TypeDef) WithEnum(name string, opts ...TypeDefWithEnumOpts) *TypeDef {
+	q := r.q.Select("withEnum")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+	}
+	q = q.Arg("name", name)
+
+	return &TypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
+// TypeDefWithEnumValueOpts contains options for TypeDef.WithEnumValue
+type TypeDefWithEnumValueOpts struct {
+	// A doc string for the value, if any
+	Description string
+}
+
+// Adds a static value for an Enum TypeDef, failing if the type is not an enum.
+func (r *TypeDef) WithEnumValue(value string, opts ...TypeDefWithEnumValueOpts) *TypeDef {
+	q := r.q.Select("withEnumValue")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+	}
+	q = q.Arg("value", value)
+
+	return &TypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
 // TypeDefWithFieldOpts contains options for TypeDef.WithField
 type TypeDefWithFieldOpts struct {
 	// A doc string for the field, if any
@@ -5651,6 +6892,58 @@ func (r *TypeDef) WithOptional(optional bool) *TypeDef {
 	}
 }
 
+// TypeDefWithScalarOpts contains options for TypeDef.WithScalar
+type TypeDefWithScalarOpts struct {
+	// A doc string for the scalar, if any
+	Description string
+}
+
+// Returns a TypeDef of kind Scalar with the provided name.
+//
+// Values of the scalar are passed between the caller and the module as-is, without any validation by the engine.
+func (r *TypeDef) WithScalar(name string, opts ...TypeDefWithScalarOpts) *TypeDef {
+	q := r.q.Select("withScalar")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+	}
+	q = q.Arg("name", name)
+
+	return &TypeDef{
+		q: q,
+		c: r.c,
+	}
+}
+
+type ArchiveCompression string
+
+func (ArchiveCompression) IsEnum() {}
+
+const (
+	// Gzip compression (.tar.gz).
+	Gz ArchiveCompression = "GZ"
+
+	// No compression.
+	None ArchiveCompression = "NONE"
+
+	// Zstandard compression (.tar.zst).
+	Zst ArchiveCompression = "ZST"
+)
+
+type ArchiveFormat string
+
+func (ArchiveFormat) IsEnum() {}
+
+const (
+	// A tar archive, optionally compressed.
+	Tar ArchiveFormat = "TAR"
+
+	// A zip archive.
+	Zip ArchiveFormat = "ZIP"
+)
+
 type CacheSharingMode string
 
 func (CacheSharingMode) IsEnum() {}
@@ -5666,6 +6959,24 @@ const (
 	Shared CacheSharingMode = "SHARED"
 )
 
+type FileType string
+
+func (FileType) IsEnum() {}
+
+const (
+	// A directory.
+	DirectoryType FileType = "DIRECTORY_TYPE"
+
+	// A regular file.
+	RegularType FileType = "REGULAR_TYPE"
+
+	// A symbolic link.
+	SymlinkType FileType = "SYMLINK_TYPE"
+
+	// Any other kind of entry, e.g. a device or a named pipe.
+	UnknownType FileType = "UNKNOWN_TYPE"
+)
+
 type ImageLayerCompression string
 
 func (ImageLayerCompression) IsEnum() {}
@@ -5700,6 +7011,21 @@ const (
 	Udp NetworkProtocol = "UDP"
 )
 
+type ReturnType string
+
+func (ReturnType) IsEnum() {}
+
+const (
+	// Any execution (exit codes 0-127)
+	Any ReturnType = "ANY"
+
+	// A failed execution (exit codes 1-127)
+	Failure ReturnType = "FAILURE"
+
+	// A successful execution (exit code 0)
+	Success ReturnType = "SUCCESS"
+)
+
 type TypeDefKind string
 
 func (TypeDefKind) IsEnum() {}
@@ -5708,6 +7034,14 @@ const (
 	// A boolean value.
 	BooleanKind TypeDefKind = "BOOLEAN_KIND"
 
+	// A GraphQL enum type and its values.
+	//
+	// Always paired with an EnumTypeDef.
+	EnumKind TypeDefKind = "ENUM_KIND"
+
+	// A float value.
+	FloatKind TypeDefKind = "FLOAT_KIND"
+
 	// A graphql input type, used only when representing the core API via TypeDefs.
 	InputKind TypeDefKind = "INPUT_KIND"
 
@@ -5729,6 +7063,11 @@ const (
 	// Always paired with an ObjectTypeDef.
 	ObjectKind TypeDefKind = "OBJECT_KIND"
 
+	// A scalar value of any basic kind.
+	//
+	// Always paired with a ScalarTypeDef.
+	ScalarKind TypeDefKind = "SCALAR_KIND"
+
 	// A string value.
 	StringKind TypeDefKind = "STRING_KIND"
 
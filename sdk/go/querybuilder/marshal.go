@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	gqlgen "github.com/99designs/gqlgen/graphql"
@@ -55,6 +56,8 @@ func marshalValue(ctx context.Context, v reflect.Value) (string, error) {
 		return fmt.Sprintf("%t", v.Bool()), nil
 	case reflect.Int:
 		return fmt.Sprintf("%d", v.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, t.Bits()), nil
 	case reflect.String:
 		if t.Implements(enumT) {
 			// enums render as their literal value
@@ -134,6 +137,12 @@ func marshalValue(ctx context.Context, v reflect.Value) (string, error) {
 }
 
 func marshalCustom(ctx context.Context, v reflect.Value) (string, error) {
+	// the engine may return a short reference along with the ID, which is
+	// passed instead of the whole ID to keep queries small
+	var ref string
+	if !idRefsDisabled(ctx) {
+		ctx = withIDRef(ctx, &ref)
+	}
 	result := v.MethodByName(GraphQLMarshallerID).Call([]reflect.Value{
 		reflect.ValueOf(ctx),
 	})
@@ -145,6 +154,9 @@ func marshalCustom(ctx context.Context, v reflect.Value) (string, error) {
 		return "", err.(error)
 	}
 
+	if ref != "" {
+		return fmt.Sprintf("%q", ref), nil
+	}
 	return fmt.Sprintf("%q", result[0].String()), nil
 }
 
@@ -3,9 +3,12 @@ package querybuilder
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/Khan/genqlient/graphql"
 	"github.com/stretchr/testify/require"
 )
 
@@ -42,6 +45,14 @@ func TestMarshalGQL(t *testing.T) {
 			v:      42,
 			expect: "42",
 		},
+		{
+			v:      0.75,
+			expect: "0.75",
+		},
+		{
+			v:      float32(1.5),
+			expect: "1.5",
+		},
 		{
 			v:      true,
 			expect: "true",
@@ -153,6 +164,78 @@ func TestCustomMarshaller(t *testing.T) {
 	}
 }
 
+// fakeClient responds to every query with the given data and extensions.
+type fakeClient struct {
+	data       any
+	extensions map[string]any
+}
+
+func (c fakeClient) MakeRequest(_ context.Context, _ *graphql.Request, resp *graphql.Response) error {
+	payload, err := json.Marshal(c.data)
+	if err != nil {
+		return err
+	}
+	resp.Extensions = c.extensions
+	return json.Unmarshal(payload, resp.Data)
+}
+
+// queriedMarshaller queries its ID like generated objects do.
+type queriedMarshaller struct {
+	c graphql.Client
+}
+
+// nolint
+func (m *queriedMarshaller) XXX_GraphQLType() string { return "idTest" }
+
+// nolint
+func (m *queriedMarshaller) XXX_GraphQLIDType() string { return "idTypeTest" }
+
+// nolint
+func (m *queriedMarshaller) XXX_GraphQLID(ctx context.Context) (string, error) {
+	var id string
+	err := Query().Select("idTest").Select("id").Bind(&id).Execute(ctx, m.c)
+	return id, err
+}
+
+// nolint
+func (m *queriedMarshaller) MarshalJSON() ([]byte, error) {
+	return nil, nil
+}
+
+func TestCustomMarshallerRef(t *testing.T) {
+	data := map[string]any{"idTest": map[string]any{"id": "full ID"}}
+
+	t.Run("returned by the engine", func(t *testing.T) {
+		enc, err := MarshalGQL(context.TODO(), &queriedMarshaller{fakeClient{
+			data: data,
+			extensions: map[string]any{
+				"idRefs": map[string]any{"idTest.id": "ni:///sha-256;ref"},
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, `"ni:///sha-256;ref"`, enc)
+	})
+
+	t.Run("not returned by the engine", func(t *testing.T) {
+		enc, err := MarshalGQL(context.TODO(), &queriedMarshaller{fakeClient{
+			data: data,
+		}})
+		require.NoError(t, err)
+		require.Equal(t, `"full ID"`, enc)
+	})
+
+	t.Run("returned for another field", func(t *testing.T) {
+		enc, err := MarshalGQL(context.TODO(), &queriedMarshaller{fakeClient{
+			data: data,
+			extensions: map[string]any{
+				"idRefs": map[string]any{"other.id": "ni:///sha-256;ref"},
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, `"full ID"`, enc)
+	})
+}
+
 func TestIsZeroValue(t *testing.T) {
 	// emptyPtr covers the case of nil reflect.Pointer:
 	var emptyPtr *string
@@ -188,3 +271,47 @@ func TestIsZeroValue(t *testing.T) {
 		require.False(t, IsZeroValue(i), fmt.Sprintf("%v", i))
 	}
 }
+
+// forgetfulClient answers ID queries with a reference, but rejects queries
+// passing it, as if the engine had evicted the ID it refers to.
+type forgetfulClient struct {
+	queries []string
+}
+
+func (c *forgetfulClient) MakeRequest(_ context.Context, req *graphql.Request, resp *graphql.Response) error {
+	c.queries = append(c.queries, req.Query)
+	var data any
+	switch {
+	case strings.Contains(req.Query, "ni:///"):
+		return errors.New(`unknown ID reference "ni:///sha-256;ref"; it may have expired, pass the full ID instead`)
+	case strings.Contains(req.Query, "idTest"):
+		data = map[string]any{"idTest": map[string]any{"id": "full ID"}}
+		resp.Extensions = map[string]any{
+			"idRefs": map[string]any{"idTest.id": "ni:///sha-256;ref"},
+		}
+	default:
+		data = map[string]any{"use": "ok"}
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, resp.Data)
+}
+
+func TestExecuteExpiredRef(t *testing.T) {
+	c := &forgetfulClient{}
+	var out string
+	err := Query().
+		Select("use").
+		Arg("thing", &queriedMarshaller{c}).
+		Bind(&out).
+		Execute(context.TODO(), c)
+	require.NoError(t, err)
+	require.Equal(t, "ok", out)
+
+	// the argument's ID is queried again when retrying with the full ID
+	require.Len(t, c.queries, 4)
+	require.Contains(t, c.queries[1], `"ni:///sha-256;ref"`)
+	require.Contains(t, c.queries[3], `"full ID"`)
+}
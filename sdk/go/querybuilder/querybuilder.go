@@ -70,7 +70,8 @@ func (s *Selection) marshalArguments(ctx context.Context) error {
 		for _, arg := range sel.args {
 			arg := arg
 			eg.Go(func() error {
-				return arg.marshal(gctx)
+				_, err := arg.marshal(gctx)
+				return err
 			})
 		}
 	}
@@ -105,9 +106,13 @@ func (s *Selection) Build(ctx context.Context) (string, error) {
 				if i > 0 {
 					b.WriteString(", ")
 				}
+				marshalled, err := arg.marshal(ctx)
+				if err != nil {
+					return "", err
+				}
 				b.WriteString(name)
 				b.WriteRune(':')
-				b.WriteString(arg.marshalled)
+				b.WriteString(marshalled)
 				i++
 			}
 			b.WriteRune(')')
@@ -148,23 +153,85 @@ func (s *Selection) unpack(data interface{}) error {
 }
 
 func (s *Selection) Execute(ctx context.Context, c graphql.Client) error {
-	query, err := s.Build(ctx)
+	response, resp, err := s.execute(ctx, c)
+	if err != nil && !idRefsDisabled(ctx) && strings.Contains(err.Error(), unknownIDRefError) {
+		// the engine forgot an ID passed by reference, e.g. because it evicted
+		// it from the session's store; pass the full IDs instead
+		response, resp, err = s.execute(withoutIDRefs(ctx), c)
+	}
 	if err != nil {
 		return err
 	}
 
+	if ref, ok := ctx.Value(idRefKey{}).(*string); ok && !idRefsDisabled(ctx) {
+		*ref = s.idRef(resp.Extensions)
+	}
+
+	return s.unpack(response)
+}
+
+func (s *Selection) execute(ctx context.Context, c graphql.Client) (any, *graphql.Response, error) {
+	query, err := s.Build(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var response any
+	resp := &graphql.Response{Data: &response}
 	err = c.MakeRequest(ctx,
 		&graphql.Request{
 			Query: query,
 		},
-		&graphql.Response{Data: &response},
+		resp,
 	)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	return response, resp, nil
+}
 
-	return s.unpack(response)
+// unknownIDRefError is part of the error returned by the engine for a
+// reference to an ID it doesn't hold.
+const unknownIDRefError = "unknown ID reference"
+
+type noIDRefsKey struct{}
+
+// withoutIDRefs returns a context in which queries are built with full IDs
+// rather than references to them.
+func withoutIDRefs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noIDRefsKey{}, true)
+}
+
+func idRefsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noIDRefsKey{}).(bool)
+	return disabled
+}
+
+type idRefKey struct{}
+
+// withIDRef returns a context in which executing an ID query records the
+// reference the engine returned for the ID in ref, if any.
+func withIDRef(ctx context.Context, ref *string) context.Context {
+	return context.WithValue(ctx, idRefKey{}, ref)
+}
+
+// idRef returns the reference the engine returned for the result of the
+// selection, keyed by its path in the response.
+func (s *Selection) idRef(extensions map[string]any) string {
+	refs, _ := extensions["idRefs"].(map[string]any)
+	if refs == nil {
+		return ""
+	}
+	keys := []string{}
+	for _, sel := range s.path() {
+		k := sel.name
+		if sel.alias != "" {
+			k = sel.alias
+		}
+		keys = append(keys, k)
+	}
+	ref, _ := refs[strings.Join(keys, ".")].(string)
+	return ref
 }
 
 type argument struct {
@@ -173,11 +240,22 @@ type argument struct {
 	marshalled    string
 	marshalledErr error
 	once          sync.Once
+
+	// the value marshalled without references to IDs
+	fullMarshalled    string
+	fullMarshalledErr error
+	fullOnce          sync.Once
 }
 
-func (a *argument) marshal(ctx context.Context) error {
+func (a *argument) marshal(ctx context.Context) (string, error) {
+	if idRefsDisabled(ctx) {
+		a.fullOnce.Do(func() {
+			a.fullMarshalled, a.fullMarshalledErr = MarshalGQL(ctx, a.value)
+		})
+		return a.fullMarshalled, a.fullMarshalledErr
+	}
 	a.once.Do(func() {
 		a.marshalled, a.marshalledErr = MarshalGQL(ctx, a.value)
 	})
-	return a.marshalledErr
+	return a.marshalled, a.marshalledErr
 }
@@ -0,0 +1,159 @@
+package dagger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Subscription follows a stream of values produced by a pipeline, such as the
+// output of a command as it runs.
+//
+// The stream is sent by the engine as server-sent events, one for each value.
+type Subscription struct {
+	field string
+	body  io.ReadCloser
+	r     *bufio.Reader
+	done  bool
+}
+
+// ContainerStdout follows the output of the last command executed by the
+// container as it's written.
+//
+// If the command is cached, its whole output is sent at once.
+func (c *Client) ContainerStdout(ctx context.Context, ctr *Container) (*Subscription, error) {
+	id, err := ctr.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.subscribe(ctx,
+		`subscription($container: ContainerID!) { containerStdout(container: $container) }`,
+		map[string]any{"container": id},
+		"containerStdout")
+}
+
+// ServiceLogs follows the combined stdout and stderr of the service as they're
+// written, starting the service if it isn't running.
+//
+// The stream ends when the service exits.
+func (c *Client) ServiceLogs(ctx context.Context, svc *Service) (*Subscription, error) {
+	id, err := svc.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.subscribe(ctx,
+		`subscription($service: ServiceID!) { serviceLogs(service: $service) }`,
+		map[string]any{"service": id},
+		"serviceLogs")
+}
+
+func (c *Client) subscribe(ctx context.Context, query string, vars map[string]any, field string) (*Subscription, error) {
+	payload, err := json.Marshal(Request{
+		Query:     query,
+		Variables: vars,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+c.conn.Host()+"/query", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.conn.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("subscribe: %s: %s", resp.Status, body)
+	}
+	return &Subscription{
+		field: field,
+		body:  resp.Body,
+		r:     bufio.NewReader(resp.Body),
+	}, nil
+}
+
+// Next blocks until the next value is available, returning io.EOF once the
+// stream has ended.
+func (sub *Subscription) Next() (string, error) {
+	for !sub.done {
+		event, data, err := sub.readEvent()
+		if err != nil {
+			if err == io.EOF {
+				// the stream always ends with a "complete" event
+				err = io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		switch event {
+		case "next":
+			var res struct {
+				Data   map[string]*string `json:"data"`
+				Errors gqlerror.List      `json:"errors"`
+			}
+			if err := json.Unmarshal(data, &res); err != nil {
+				return "", err
+			}
+			if len(res.Errors) > 0 {
+				sub.done = true
+				if e := getCustomError(res.Errors[0]); e != nil {
+					return "", e
+				}
+				return "", res.Errors
+			}
+			if val := res.Data[sub.field]; val != nil {
+				return *val, nil
+			}
+		case "complete":
+			sub.done = true
+		}
+	}
+	return "", io.EOF
+}
+
+// Close stops following the stream.
+func (sub *Subscription) Close() error {
+	sub.done = true
+	return sub.body.Close()
+}
+
+// readEvent reads the next event, skipping comments.
+func (sub *Subscription) readEvent() (event string, data []byte, err error) {
+	var dataLines [][]byte
+	for {
+		line, err := sub.r.ReadString('\n')
+		if err != nil {
+			return "", nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			if event == "" && dataLines == nil {
+				// only comments so far
+				continue
+			}
+			return event, bytes.Join(dataLines, []byte("\n")), nil
+		}
+		name, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch name {
+		case "":
+			// comment
+		case "event":
+			event = value
+		case "data":
+			dataLines = append(dataLines, []byte(value))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package dagger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sseConn serves requests with a canned stream of server-sent events.
+type sseConn struct {
+	events string
+}
+
+func (conn sseConn) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept") != "text/event-stream" {
+		return nil, errors.New("not a subscription")
+	}
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "text/event-stream")
+	io.WriteString(w, conn.events)
+	return w.Result(), nil
+}
+
+func (sseConn) Host() string { return "dagger" }
+func (sseConn) Close() error { return nil }
+
+func TestSubscription(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &Client{conn: sseConn{events: strings.Join([]string{
+		":\n\n",
+		"event: next\ndata: {\"data\":{\"serviceLogs\":\"hello, \"}}\n\n",
+		"event: next\ndata: {\"data\":{\"serviceLogs\":\"world!\\n\"}}\n\n",
+		"event: complete\n\n",
+	}, "")}}
+
+	sub, err := c.subscribe(ctx, `subscription { serviceLogs }`, nil, "serviceLogs")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	var out string
+	for {
+		chunk, err := sub.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		out += chunk
+	}
+	require.Equal(t, "hello, world!\n", out)
+}
+
+func TestSubscriptionError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &Client{conn: sseConn{events: strings.Join([]string{
+		":\n\n",
+		"event: next\ndata: {\"data\":null,\"errors\":[{\"message\":\"no such service\"}]}\n\n",
+		"event: complete\n\n",
+	}, "")}}
+
+	sub, err := c.subscribe(ctx, `subscription { serviceLogs }`, nil, "serviceLogs")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	_, err = sub.Next()
+	require.ErrorContains(t, err, "no such service")
+
+	_, err = sub.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestSubscriptionTruncated(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &Client{conn: sseConn{events: "event: next\ndata: {\"data\":{\"serviceLogs\":\"hi\"}}\n\n"}}
+
+	sub, err := c.subscribe(ctx, `subscription { serviceLogs }`, nil, "serviceLogs")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	chunk, err := sub.Next()
+	require.NoError(t, err)
+	require.Equal(t, "hi", chunk)
+
+	_, err = sub.Next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
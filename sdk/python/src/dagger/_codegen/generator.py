@@ -214,7 +214,7 @@ def generate(schema: GraphQLSchema) -> Iterator[str]:
     )
 
     # Split into two iterators to update ctx.remaining.
-    types_n, types_g = itertools.tee(get_grouped_types(handlers, schema.type_map))
+    types_n, types_g = itertools.tee(get_grouped_types(handlers, schema))
 
     # Track types that haven't been defined yet, to format as a forward reference.
     ctx.remaining.update(name for _, name, _ in types_n)
@@ -298,13 +298,17 @@ def get_lists_of_object_types(type_map: TypeMap):
                 yield get_named_type(f.type)
 
 
-def get_grouped_types(handlers: tuple[Handler, ...], type_map: TypeMap):
+def get_grouped_types(handlers: tuple[Handler, ...], schema: GraphQLSchema):
     """Group types by handler and sorted by their name."""
+    type_map = schema.type_map
 
     def _filtered():
         for n, t in type_map.items():
             if n.startswith("_") or is_builtin_scalar_type(t):
                 continue
+            # Subscriptions are streamed by the hand-written client instead.
+            if t is schema.subscription_type:
+                continue
             for i, handler in enumerate(handlers):
                 if handler.predicate(t):
                     yield i, n
@@ -152,6 +152,9 @@ class ExecError(QueryError):
         The error message.
     exit_code:
         The exit code of the command.
+    reason:
+        Why the command was stopped if it didn't exit on its own, e.g.
+        ``"TIMEOUT"`` or ``"OOM_KILLED"``.
     stdout:
         The stdout of the command.
     stderr:
@@ -163,6 +166,7 @@ class ExecError(QueryError):
     command: list[str]
     message: str
     exit_code: int
+    reason: str | None
     stdout: str
     stderr: str
 
@@ -174,6 +178,7 @@ class ExecError(QueryError):
         self.command = ext["cmd"]
         self.message = error.message
         self.exit_code = ext["exitCode"]
+        self.reason = ext.get("reason")
         self.stdout = ext["stdout"]
         self.stderr = ext["stderr"]
 
@@ -2,6 +2,7 @@ import functools
 import logging
 import typing
 from collections import deque
+from collections.abc import AsyncIterator
 from dataclasses import MISSING, dataclass, field, replace
 from typing import (
     Any,
@@ -134,9 +135,30 @@ class Context:
     async def execute(self, return_type: type[T] | None = None) -> T | None:
         await self.resolve_ids()
         query = await self.query()
+        result = await self._execute(query)
+        return self.get_value(result, return_type) if return_type else None
 
+    async def execute_id(self) -> str:
+        """Execute an ID query, returning the short reference to the ID if
+        the engine returned one, which is passed in its stead."""
+        await self.resolve_ids()
+        query = await self.query()
+        result = await self._execute(query, get_execution_result=True)
+        refs = (result.extensions or {}).get("idRefs", {})
+        path = ".".join(f.name for f in self.selections)
+        return refs.get(path) or self.get_value(result.data, str)
+
+    async def _execute(
+        self,
+        query: graphql.DocumentNode,
+        *,
+        get_execution_result: bool = False,
+    ) -> Any:
         try:
-            result = await self.conn.session.execute(query)
+            return await self.conn.session.execute(
+                query,
+                get_execution_result=get_execution_result,
+            )
         except httpx.TimeoutException as e:
             msg = (
                 "Request timed out. Try setting a higher timeout value in "
@@ -164,8 +186,6 @@ class Context:
                 raise error from e
             raise
 
-        return self.get_value(result, return_type) if return_type else None
-
     @overload
     def get_value(self, value: None, return_type: Any) -> None:
         ...
@@ -191,16 +211,20 @@ class Context:
         return self.converter.structure(value, return_type)
 
     async def resolve_ids(self) -> None:
-        """Replace Type object instances with their ID implicitly."""
+        """Replace Type object instances with their ID implicitly.
+
+        The short reference to the ID is used instead when the engine
+        returns one.
+        """
 
         # mutating to avoid re-fetching on forked pipeline
         async def _resolve_id(pos: int, k: str, v: IDType):
             sel = self.selections[pos]
-            sel.args[k] = await v.id()
+            sel.args[k] = await v._select("id", []).execute_id()  # noqa: SLF001
 
         async def _resolve_seq_id(pos: int, idx: int, k: str, v: IDType):
             sel = self.selections[pos]
-            sel.args[k][idx] = await v.id()
+            sel.args[k][idx] = await v._select("id", []).execute_id()  # noqa: SLF001
 
         # resolve all ids concurrently
         async with anyio.create_task_group() as tg:
@@ -265,3 +289,46 @@ class Root(Type):
         # Since SharedConnection is a singleton, we could make Context optional
         # in every Type like here, but let's keep it only for the root object for now.
         super().__init__(ctx or Context(SharedConnection()))
+
+    def container_stdout(self, container: Type) -> AsyncIterator[str]:
+        """Follow the output of the last command executed by a container.
+
+        Yields chunks of the output as they're written. If the command is
+        cached, its whole output is yielded at once.
+        """
+        return self._subscribe("containerStdout", "container", container)
+
+    def service_logs(self, service: Type) -> AsyncIterator[str]:
+        """Follow the combined stdout and stderr of a service.
+
+        Yields chunks of the output as they're written, starting the service
+        if it isn't running. Ends when the service exits.
+        """
+        return self._subscribe("serviceLogs", "service", service)
+
+    async def _subscribe(
+        self,
+        field_name: str,
+        arg_name: str,
+        obj: Type,
+    ) -> AsyncIterator[str]:
+        id_type = f"{obj._graphql_name()}ID"  # noqa: SLF001
+        query = f"subscription($id: {id_type}!) {{ {field_name}({arg_name}: $id) }}"
+        id_ = await obj.id()  # type: ignore[attr-defined]
+
+        try:
+            async for result in self._ctx.conn.session.subscribe(query, {"id": id_}):
+                if errors := result.get("errors"):
+                    exc = TransportQueryError(str(errors[0]), errors=errors)
+                    if error := _query_error_from_transport(exc, graphql.parse(query)):
+                        raise error from exc
+                    raise exc
+                yield result["data"][field_name]
+
+        except httpx.RequestError as e:
+            msg = f"Failed to make request: {e}"
+            raise TransportError(msg) from e
+
+        except httpx.HTTPStatusError as e:
+            msg = f"Unexpected response from engine: {e}"
+            raise TransportError(msg) from e
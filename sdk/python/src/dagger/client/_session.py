@@ -1,6 +1,8 @@
 import contextlib
+import json
 import logging
 import os
+from collections.abc import AsyncIterator
 from dataclasses import dataclass, field
 from typing import Any
 
@@ -62,6 +64,8 @@ class ClientSession(ResourceManager):
         if cfg is None:
             cfg = ConnectConfig()
 
+        self.conn = conn
+
         transport = HTTPXAsyncTransport(
             conn.url,
             timeout=cfg.timeout,
@@ -121,14 +125,70 @@ class ClientSession(ResourceManager):
             raise ClientConnectionError(msg)
         return client.schema
 
-    async def execute(self, query: graphql.DocumentNode) -> Any:
-        return await (await self.get_session()).execute(query)
+    async def execute(
+        self,
+        query: graphql.DocumentNode,
+        *,
+        get_execution_result: bool = False,
+    ) -> Any:
+        return await (await self.get_session()).execute(
+            query,
+            get_execution_result=get_execution_result,
+        )
+
+    async def subscribe(
+        self,
+        query: str,
+        variables: dict[str, Any],
+    ) -> AsyncIterator[dict[str, Any]]:
+        """Follow a subscription, yielding each response as it's received.
+
+        The engine sends the responses as server-sent events. Unlike queries,
+        there's no timeout waiting for them, since a stream can stay idle for
+        as long as the pipeline producing it.
+        """
+        async with httpx.AsyncClient(
+            auth=(self.conn.session_token, ""),
+            timeout=None,
+        ) as client, client.stream(
+            "POST",
+            self.conn.url,
+            json={"query": query, "variables": variables},
+            headers={"Accept": "text/event-stream"},
+        ) as response:
+            response.raise_for_status()
+            async for event, data in _sse_events(response.aiter_lines()):
+                if event == "complete":
+                    return
+                if event == "next":
+                    yield json.loads(data)
+
+        msg = "Subscription ended before completing"
+        raise httpx.RemoteProtocolError(msg)
 
     async def close(self) -> None:
         logger.debug("Closing client session to GraphQL server")
         await super().close()
 
 
+async def _sse_events(lines: AsyncIterator[str]) -> AsyncIterator[tuple[str, str]]:
+    """Parse lines of server-sent events into their type and data."""
+    event, data = "", []
+    async for line in lines:
+        if not line:
+            if event or data:
+                yield event, "\n".join(data)
+            event, data = "", []
+            continue
+        name, _, value = line.partition(":")
+        value = value.removeprefix(" ")
+        # Lines without a name are comments.
+        if name == "event":
+            event = value
+        elif name == "data":
+            data.append(value)
+
+
 @contextlib.asynccontextmanager
 async def retrying_client(client: GraphQLClient, retry: Retry):
     try:
@@ -30,6 +30,19 @@ async def test_container():
     assert version == "3.16.2\n"
 
 
+async def test_container_stdout_subscription():
+    ctr = (
+        dag.container()
+        .from_("alpine:3.16.2")
+        .with_env_variable("CACHEBUSTER", str(datetime.now()))
+        .with_exec(["sh", "-c", "for i in 1 2 3; do echo $i; sleep 0.1; done"])
+    )
+
+    chunks = [chunk async for chunk in dag.container_stdout(ctr)]
+
+    assert "".join(chunks) == "1\n2\n3\n"
+
+
 async def test_git_repository():
     repo = dag.git("https://github.com/dagger/dagger").tag("v0.3.0").tree()
     readme = await repo.file("README.md").contents()
@@ -45,8 +45,48 @@ class BaseClient {
   get queryTree() {
     return this._queryTree
   }
+
+  /**
+   * @hidden
+   */
+  get ctx() {
+    return this._ctx
+  }
+}
+
+/**
+ * The compression of a tar archive.
+ */
+export enum ArchiveCompression {
+  /**
+   * Gzip compression (.tar.gz).
+   */
+  Gz = "GZ",
+
+  /**
+   * No compression.
+   */
+  None = "NONE",
+
+  /**
+   * Zstandard compression (.tar.zst).
+   */
+  Zst = "ZST",
 }
+/**
+ * The format of an archive.
+ */
+export enum ArchiveFormat {
+  /**
+   * A tar archive, optionally compressed.
+   */
+  Tar = "TAR",
 
+  /**
+   * A zip archive.
+   */
+  Zip = "ZIP",
+}
 export type BuildArg = {
   /**
    * The build argument name.
@@ -83,6 +123,36 @@ export enum CacheSharingMode {
  */
 export type CacheVolumeID = string & { __CacheVolumeID: never }
 
+export type ContainerAsServiceOpts = {
+  /**
+   * Maximum time in seconds the service may run for before being killed.
+   *
+   * If 0, the service may run indefinitely.
+   */
+  timeout?: number
+
+  /**
+   * Maximum amount of memory in bytes the service may use before being killed.
+   *
+   * If 0, memory is not limited.
+   */
+  memoryLimit?: number
+
+  /**
+   * Relative CPU weight of the service, compared to other containers (e.g., 1024).
+   *
+   * If 0, the default weight is used.
+   */
+  cpuShares?: number
+
+  /**
+   * Maximum number of processes the service may run.
+   *
+   * If 0, the number of processes is not limited.
+   */
+  pidsLimit?: number
+}
+
 export type ContainerAsTarballOpts = {
   /**
    * Identifiers for other platform specific containers.
@@ -271,6 +341,41 @@ export type ContainerWithExecOpts = {
    * Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
    */
   insecureRootCapabilities?: boolean
+
+  /**
+   * Exit codes this command is allowed to exit with without error.
+   *
+   * With FAILURE or ANY, a failing command's filesystem and output stay available to the rest of the pipeline, and its status can be read with exitCode.
+   */
+  expect?: ReturnType
+
+  /**
+   * Maximum time in seconds the command may run for before being killed.
+   *
+   * If 0, the command may run indefinitely.
+   */
+  timeout?: number
+
+  /**
+   * Maximum amount of memory in bytes the container may use before the command is killed.
+   *
+   * If 0, memory is not limited.
+   */
+  memoryLimit?: number
+
+  /**
+   * Relative CPU weight of the container, compared to other containers (e.g., 1024).
+   *
+   * If 0, the default weight is used.
+   */
+  cpuShares?: number
+
+  /**
+   * Maximum number of processes the container may run.
+   *
+   * If 0, the number of processes is not limited.
+   */
+  pidsLimit?: number
 }
 
 export type ContainerWithExposedPortOpts = {
@@ -420,6 +525,30 @@ export type ContainerWithoutExposedPortOpts = {
  */
 export type ContainerID = string & { __ContainerID: never }
 
+export type DirectoryAsArchiveOpts = {
+  /**
+   * The format of the archive.
+   */
+  format?: ArchiveFormat
+
+  /**
+   * The compression of the archive.
+   *
+   * Only supported for tar archives.
+   */
+  compression?: ArchiveCompression
+
+  /**
+   * Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   */
+  exclude?: string[]
+
+  /**
+   * Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+   */
+  include?: string[]
+}
+
 export type DirectoryAsModuleOpts = {
   /**
    * An optional subpath of the directory which contains the module's source code.
@@ -431,6 +560,13 @@ export type DirectoryAsModuleOpts = {
   sourceSubpath?: string
 }
 
+export type DirectoryDigestOpts = {
+  /**
+   * If true, permissions, ownership and modification times are not included in the digest.
+   */
+  excludeMetadata?: boolean
+}
+
 export type DirectoryDockerBuildOpts = {
   /**
    * The platform to build.
@@ -467,6 +603,29 @@ export type DirectoryEntriesOpts = {
   path?: string
 }
 
+export type DirectoryEntriesWithInfoOpts = {
+  /**
+   * Location of the directory to look at (e.g., "/src").
+   */
+  path?: string
+}
+
+export type DirectoryExportOpts = {
+  /**
+   * Remove files under the path that aren't in the directory, so that the path mirrors it.
+   *
+   * Wiping the current working directory or any of its parents, or the root of a git repository, is refused. Check the changes with exportDryRun first.
+   */
+  wipe?: boolean
+}
+
+export type DirectoryExportDryRunOpts = {
+  /**
+   * Remove files under the path that aren't in the directory, so that the path mirrors it.
+   */
+  wipe?: boolean
+}
+
 export type DirectoryPipelineOpts = {
   /**
    * Description of the sub-pipeline.
@@ -517,6 +676,16 @@ export type DirectoryWithNewFileOpts = {
  */
 export type DirectoryID = string & { __DirectoryID: never }
 
+/**
+ * The `EnumTypeDefID` scalar type represents an identifier for an object of type EnumTypeDef.
+ */
+export type EnumTypeDefID = string & { __EnumTypeDefID: never }
+
+/**
+ * The `EnumValueTypeDefID` scalar type represents an identifier for an object of type EnumValueTypeDef.
+ */
+export type EnumValueTypeDefID = string & { __EnumValueTypeDefID: never }
+
 /**
  * The `EnvVariableID` scalar type represents an identifier for an object of type EnvVariable.
  */
@@ -527,6 +696,13 @@ export type EnvVariableID = string & { __EnvVariableID: never }
  */
 export type FieldTypeDefID = string & { __FieldTypeDefID: never }
 
+export type FileDigestOpts = {
+  /**
+   * If true, permissions, ownership and modification times are not included in the digest.
+   */
+  excludeMetadata?: boolean
+}
+
 export type FileExportOpts = {
   /**
    * If allowParentDirPath is true, the path argument can be a directory path, in which case the file will be created in that directory.
@@ -534,11 +710,59 @@ export type FileExportOpts = {
   allowParentDirPath?: boolean
 }
 
+export type FileExtractOpts = {
+  /**
+   * The format of the archive.
+   *
+   * If not set, it is detected from the archive's contents. The compression of tar archives is always detected.
+   */
+  format?: ArchiveFormat
+
+  /**
+   * Exclude entries that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   */
+  exclude?: string[]
+
+  /**
+   * Include only entries that match the given pattern (e.g., ["app/", "package.*"]).
+   */
+  include?: string[]
+}
+
 /**
  * The `FileID` scalar type represents an identifier for an object of type File.
  */
 export type FileID = string & { __FileID: never }
 
+/**
+ * The `FileInfoID` scalar type represents an identifier for an object of type FileInfo.
+ */
+export type FileInfoID = string & { __FileInfoID: never }
+
+/**
+ * The type of an entry in a directory.
+ */
+export enum FileType {
+  /**
+   * A directory.
+   */
+  DirectoryType = "DIRECTORY_TYPE",
+
+  /**
+   * A regular file.
+   */
+  RegularType = "REGULAR_TYPE",
+
+  /**
+   * A symbolic link.
+   */
+  SymlinkType = "SYMLINK_TYPE",
+
+  /**
+   * Any other kind of entry, e.g. a device or a named pipe.
+   */
+  UnknownType = "UNKNOWN_TYPE",
+}
 export type FunctionWithArgOpts = {
   /**
    * A doc string for the argument, if any
@@ -588,6 +812,28 @@ export type GitRefTreeOpts = {
    * DEPRECATED: This option should be passed to `git` instead.
    */
   sshAuthSocket?: Socket
+
+  /**
+   * Number of commits of history to fetch.
+   *
+   * Only the tip of the ref is fetched by default, unless it's a commit.
+   */
+  depth?: number
+
+  /**
+   * Only check out these paths (e.g., ["docs", "go.mod"]).
+   */
+  sparsePaths?: string[]
+
+  /**
+   * Skip checking out submodules.
+   */
+  skipSubmodules?: boolean
+
+  /**
+   * Replace Git LFS pointer files with their contents.
+   */
+  lfs?: boolean
 }
 
 /**
@@ -595,11 +841,30 @@ export type GitRefTreeOpts = {
  */
 export type GitRefID = string & { __GitRefID: never }
 
+export type GitRepositoryTagsOpts = {
+  /**
+   * Glob pattern to filter tags by (e.g., "v1.*").
+   */
+  pattern?: string
+}
+
 /**
  * The `GitRepositoryID` scalar type represents an identifier for an object of type GitRepository.
  */
 export type GitRepositoryID = string & { __GitRepositoryID: never }
 
+export type HTTPHeader = {
+  /**
+   * The header name.
+   */
+  name: string
+
+  /**
+   * The header value.
+   */
+  value: string
+}
+
 export type HostDirectoryOpts = {
   /**
    * Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
@@ -610,6 +875,20 @@ export type HostDirectoryOpts = {
    * Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
    */
   include?: string[]
+
+  /**
+   * Also exclude artifacts that match the patterns in the .daggerignore file at the root of the directory, which uses the same syntax as .gitignore.
+   *
+   * Only the file at the root is read; .daggerignore files in subdirectories are not honored.
+   */
+  daggerignore?: boolean
+
+  /**
+   * Also exclude artifacts that match the patterns in the .gitignore file at the root of the directory.
+   *
+   * Only the file at the root is read; .gitignore files in subdirectories are not honored.
+   */
+  gitignore?: boolean
 }
 
 export type HostServiceOpts = {
@@ -768,6 +1047,24 @@ export type PortForward = {
  */
 export type PortID = string & { __PortID: never }
 
+export type ClientCacheVolumeOpts = {
+  /**
+   * Maximum size of the cache volume's contents, in bytes. If the contents exceed it when an exec mounts the volume, they are wiped entirely rather than trimmed.
+   *
+   * The size is only checked when the volume is mounted, so an exec may grow the contents past it.
+   *
+   * Set to 0 for no limit.
+   */
+  maxSize?: number
+
+  /**
+   * Number of seconds the cache volume's contents are kept once they stop being used. Older contents are wiped entirely when an exec next mounts the volume.
+   *
+   * Set to 0 for no limit.
+   */
+  ttl?: number
+}
+
 export type ClientContainerOpts = {
   /**
    * DEPRECATED: Use `loadContainerFromID` instead.
@@ -807,6 +1104,11 @@ export type ClientGitOpts = {
    * Set SSH auth socket
    */
   sshAuthSocket?: Socket
+
+  /**
+   * A secret containing a token used to authenticate to an HTTPS remote.
+   */
+  httpAuthToken?: Secret
 }
 
 export type ClientHttpOpts = {
@@ -814,6 +1116,33 @@ export type ClientHttpOpts = {
    * A service which must be started before the URL is fetched.
    */
   experimentalServiceHost?: Service
+
+  /**
+   * Expected digest of the content (e.g., "sha256:...").
+   *
+   * The download fails if the content doesn't match, and a matching download is never fetched again.
+   */
+  checksum?: string
+
+  /**
+   * Headers to send with the request.
+   */
+  headers?: HTTPHeader[]
+
+  /**
+   * A secret whose value is sent as the Authorization header.
+   */
+  authHeader?: Secret
+
+  /**
+   * Name of the downloaded file.
+   */
+  name?: string
+
+  /**
+   * Permission given to the downloaded file (e.g., 0600).
+   */
+  permissions?: number
 }
 
 export type ClientModuleConfigOpts = {
@@ -832,6 +1161,30 @@ export type ClientPipelineOpts = {
   labels?: PipelineLabel[]
 }
 
+/**
+ * Expected return type of an execution
+ */
+export enum ReturnType {
+  /**
+   * Any execution (exit codes 0-127)
+   */
+  Any = "ANY",
+
+  /**
+   * A failed execution (exit codes 1-127)
+   */
+  Failure = "FAILURE",
+
+  /**
+   * A successful execution (exit code 0)
+   */
+  Success = "SUCCESS",
+}
+/**
+ * The `ScalarTypeDefID` scalar type represents an identifier for an object of type ScalarTypeDef.
+ */
+export type ScalarTypeDefID = string & { __ScalarTypeDefID: never }
+
 /**
  * The `SecretID` scalar type represents an identifier for an object of type Secret.
  */
@@ -869,6 +1222,20 @@ export type SocketID = string & { __SocketID: never }
  */
 export type TerminalID = string & { __TerminalID: never }
 
+export type TypeDefWithEnumOpts = {
+  /**
+   * A doc string for the enum, if any
+   */
+  description?: string
+}
+
+export type TypeDefWithEnumValueOpts = {
+  /**
+   * A doc string for the value, if any
+   */
+  description?: string
+}
+
 export type TypeDefWithFieldOpts = {
   /**
    * A doc string for the field, if any
@@ -884,6 +1251,13 @@ export type TypeDefWithObjectOpts = {
   description?: string
 }
 
+export type TypeDefWithScalarOpts = {
+  /**
+   * A doc string for the scalar, if any
+   */
+  description?: string
+}
+
 /**
  * The `TypeDefID` scalar type represents an identifier for an object of type TypeDef.
  */
@@ -898,6 +1272,18 @@ export enum TypeDefKind {
    */
   BooleanKind = "BOOLEAN_KIND",
 
+  /**
+   * A GraphQL enum type and its values.
+   *
+   * Always paired with an EnumTypeDef.
+   */
+  EnumKind = "ENUM_KIND",
+
+  /**
+   * A float value.
+   */
+  FloatKind = "FLOAT_KIND",
+
   /**
    * A graphql input type, used only when representing the core API via TypeDefs.
    */
@@ -929,6 +1315,13 @@ export enum TypeDefKind {
    */
   ObjectKind = "OBJECT_KIND",
 
+  /**
+   * A scalar value of any basic kind.
+   *
+   * Always paired with a ScalarTypeDef.
+   */
+  ScalarKind = "SCALAR_KIND",
+
   /**
    * A string value.
    */
@@ -961,17 +1354,26 @@ export type __TypeFieldsOpts = {
  */
 export class CacheVolume extends BaseClient {
   private readonly _id?: CacheVolumeID = undefined
+  private readonly _key?: string = undefined
+  private readonly _prune?: Void = undefined
+  private readonly _size?: number = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
-    _id?: CacheVolumeID
+    _id?: CacheVolumeID,
+    _key?: string,
+    _prune?: Void,
+    _size?: number
   ) {
     super(parent)
 
     this._id = _id
+    this._key = _key
+    this._prune = _prune
+    this._size = _size
   }
 
   /**
@@ -994,19 +1396,85 @@ export class CacheVolume extends BaseClient {
 
     return response
   }
-}
 
-/**
- * An OCI-compatible container, also known as a Docker container.
- */
-export class Container extends BaseClient {
-  private readonly _id?: ContainerID = undefined
-  private readonly _envVariable?: string = undefined
-  private readonly _export?: boolean = undefined
-  private readonly _imageRef?: string = undefined
-  private readonly _label?: string = undefined
-  private readonly _platform?: Platform = undefined
-  private readonly _publish?: string = undefined
+  /**
+   * The key the cache volume was constructed with.
+   */
+  key = async (): Promise<string> => {
+    if (this._key) {
+      return this._key
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "key",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Clears the contents of the cache volume from the engine's local cache.
+   *
+   * Contents currently mounted by a running container are kept.
+   */
+  prune = async (): Promise<Void> => {
+    if (this._prune) {
+      return this._prune
+    }
+
+    const response: Awaited<Void> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "prune",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * The size of the cache volume's contents in the engine's local cache, in bytes.
+   */
+  size = async (): Promise<number> => {
+    if (this._size) {
+      return this._size
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "size",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+}
+
+/**
+ * An OCI-compatible container, also known as a Docker container.
+ */
+export class Container extends BaseClient {
+  private readonly _id?: ContainerID = undefined
+  private readonly _envVariable?: string = undefined
+  private readonly _exitCode?: number = undefined
+  private readonly _export?: boolean = undefined
+  private readonly _imageRef?: string = undefined
+  private readonly _label?: string = undefined
+  private readonly _platform?: Platform = undefined
+  private readonly _publish?: string = undefined
   private readonly _stderr?: string = undefined
   private readonly _stdout?: string = undefined
   private readonly _sync?: ContainerID = undefined
@@ -1020,6 +1488,7 @@ export class Container extends BaseClient {
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: ContainerID,
     _envVariable?: string,
+    _exitCode?: number,
     _export?: boolean,
     _imageRef?: string,
     _label?: string,
@@ -1035,6 +1504,7 @@ export class Container extends BaseClient {
 
     this._id = _id
     this._envVariable = _envVariable
+    this._exitCode = _exitCode
     this._export = _export
     this._imageRef = _imageRef
     this._label = _label
@@ -1072,13 +1542,26 @@ export class Container extends BaseClient {
    * Turn the container into a Service.
    *
    * Be sure to set any exposed ports before this conversion.
+   * @param opts.timeout Maximum time in seconds the service may run for before being killed.
+   *
+   * If 0, the service may run indefinitely.
+   * @param opts.memoryLimit Maximum amount of memory in bytes the service may use before being killed.
+   *
+   * If 0, memory is not limited.
+   * @param opts.cpuShares Relative CPU weight of the service, compared to other containers (e.g., 1024).
+   *
+   * If 0, the default weight is used.
+   * @param opts.pidsLimit Maximum number of processes the service may run.
+   *
+   * If 0, the number of processes is not limited.
    */
-  asService = (): Service => {
+  asService = (opts?: ContainerAsServiceOpts): Service => {
     return new Service({
       queryTree: [
         ...this._queryTree,
         {
           operation: "asService",
+          args: { ...opts },
         },
       ],
       ctx: this._ctx,
@@ -1254,6 +1737,29 @@ export class Container extends BaseClient {
     )
   }
 
+  /**
+   * The exit code of the last executed command.
+   *
+   * Will execute default command if none is set, or error if there's no default.
+   */
+  exitCode = async (): Promise<number> => {
+    if (this._exitCode) {
+      return this._exitCode
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "exitCode",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
   /**
    * EXPERIMENTAL API! Subject to change/removal at any time.
    *
@@ -1859,14 +2365,33 @@ export class Container extends BaseClient {
    *
    * Do not use this option unless you trust the command being executed; the command being executed WILL BE GRANTED FULL ACCESS TO YOUR HOST FILESYSTEM.
    * @param opts.insecureRootCapabilities Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
+   * @param opts.expect Exit codes this command is allowed to exit with without error.
+   *
+   * With FAILURE or ANY, a failing command's filesystem and output stay available to the rest of the pipeline, and its status can be read with exitCode.
+   * @param opts.timeout Maximum time in seconds the command may run for before being killed.
+   *
+   * If 0, the command may run indefinitely.
+   * @param opts.memoryLimit Maximum amount of memory in bytes the container may use before the command is killed.
+   *
+   * If 0, memory is not limited.
+   * @param opts.cpuShares Relative CPU weight of the container, compared to other containers (e.g., 1024).
+   *
+   * If 0, the default weight is used.
+   * @param opts.pidsLimit Maximum number of processes the container may run.
+   *
+   * If 0, the number of processes is not limited.
    */
   withExec = (args: string[], opts?: ContainerWithExecOpts): Container => {
+    const metadata: Metadata = {
+      expect: { is_enum: true },
+    }
+
     return new Container({
       queryTree: [
         ...this._queryTree,
         {
           operation: "withExec",
-          args: { args, ...opts },
+          args: { args, ...opts, __metadata: metadata },
         },
       ],
       ctx: this._ctx,
@@ -2503,6 +3028,7 @@ export class Container extends BaseClient {
  */
 export class Directory extends BaseClient {
   private readonly _id?: DirectoryID = undefined
+  private readonly _digest?: string = undefined
   private readonly _export?: boolean = undefined
   private readonly _sync?: DirectoryID = undefined
 
@@ -2512,12 +3038,14 @@ export class Directory extends BaseClient {
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
     _id?: DirectoryID,
+    _digest?: string,
     _export?: boolean,
     _sync?: DirectoryID
   ) {
     super(parent)
 
     this._id = _id
+    this._digest = _digest
     this._export = _export
     this._sync = _sync
   }
@@ -2543,6 +3071,33 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Packs the contents of this directory into an archive.
+   * @param opts.format The format of the archive.
+   * @param opts.compression The compression of the archive.
+   *
+   * Only supported for tar archives.
+   * @param opts.exclude Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   * @param opts.include Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+   */
+  asArchive = (opts?: DirectoryAsArchiveOpts): File => {
+    const metadata: Metadata = {
+      format: { is_enum: true },
+      compression: { is_enum: true },
+    }
+
+    return new File({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "asArchive",
+          args: { ...opts, __metadata: metadata },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load the directory as a Dagger module
    * @param opts.sourceSubpath An optional subpath of the directory which contains the module's source code.
@@ -2581,6 +3136,31 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Returns a sha256 digest of the contents of this directory.
+   *
+   * The digest covers the path, type and contents of every entry, so directories with identical contents have the same digest no matter how they were built.
+   * @param opts.excludeMetadata If true, permissions, ownership and modification times are not included in the digest.
+   */
+  digest = async (opts?: DirectoryDigestOpts): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "digest",
+          args: { ...opts },
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
   /**
    * Retrieves a directory at the given path.
    * @param path Location of the directory to retrieve (e.g., "/src").
@@ -2640,11 +3220,61 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Returns a list of files and directories at the given path, along with their type, permissions, ownership and link target.
+   *
+   * Symlinks are not followed.
+   * @param opts.path Location of the directory to look at (e.g., "/src").
+   */
+  entriesWithInfo = async (
+    opts?: DirectoryEntriesWithInfoOpts
+  ): Promise<FileInfo[]> => {
+    type entriesWithInfo = {
+      id: FileInfoID
+    }
+
+    const response: Awaited<entriesWithInfo[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "entriesWithInfo",
+          args: { ...opts },
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response.map(
+      (r) =>
+        new FileInfo(
+          {
+            queryTree: [
+              {
+                operation: "loadFileInfoFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id
+        )
+    )
+  }
+
   /**
    * Writes the contents of the directory to a path on the host.
    * @param path Location of the copied directory (e.g., "logs/").
+   * @param opts.wipe Remove files under the path that aren't in the directory, so that the path mirrors it.
+   *
+   * Wiping the current working directory or any of its parents, or the root of a git repository, is refused. Check the changes with exportDryRun first.
    */
-  export = async (path: string): Promise<boolean> => {
+  export = async (
+    path: string,
+    opts?: DirectoryExportOpts
+  ): Promise<boolean> => {
     if (this._export) {
       return this._export
     }
@@ -2654,7 +3284,32 @@ export class Directory extends BaseClient {
         ...this._queryTree,
         {
           operation: "export",
-          args: { path },
+          args: { path, ...opts },
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Returns the changes that exporting the directory to a path on the host would make, without making them.
+   *
+   * Each change is a path relative to the exported path, prefixed with "A " if it would be added, "M " if it would be modified or "D " if it would be removed.
+   * @param path Location of the copied directory (e.g., "logs/").
+   * @param opts.wipe Remove files under the path that aren't in the directory, so that the path mirrors it.
+   */
+  exportDryRun = async (
+    path: string,
+    opts?: DirectoryExportDryRunOpts
+  ): Promise<string[]> => {
+    const response: Awaited<string[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "exportDryRun",
+          args: { path, ...opts },
         },
       ],
       await this._ctx.connection()
@@ -2718,6 +3373,25 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves information about the file, directory or symlink at the given path.
+   *
+   * Symlinks are not followed.
+   * @param path Location of the entry to inspect (e.g., "bin/app").
+   */
+  stat = (path: string): FileInfo => {
+    return new FileInfo({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "stat",
+          args: { path },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Force evaluation in the engine.
    */
@@ -2826,6 +3500,26 @@ export class Directory extends BaseClient {
     })
   }
 
+  /**
+   * Retrieves this directory plus a symlink created at the given path.
+   * @param target Location the symlink points to (e.g., "../lib/libfoo.so.1").
+   *
+   * The target is written as-is and doesn't need to exist.
+   * @param linkName Location of the created symlink (e.g., "lib/libfoo.so").
+   */
+  withSymlink = (target: string, linkName: string): Directory => {
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withSymlink",
+          args: { target, linkName },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves this directory with all file/dir timestamps set to the given time.
    * @param timestamp Timestamp to set dir/files in.
@@ -2890,38 +3584,41 @@ export class Directory extends BaseClient {
 }
 
 /**
- * An environment variable name and value.
+ * A definition of a custom enum defined in a Module.
  */
-export class EnvVariable extends BaseClient {
-  private readonly _id?: EnvVariableID = undefined
+export class EnumTypeDef extends BaseClient {
+  private readonly _id?: EnumTypeDefID = undefined
+  private readonly _description?: string = undefined
   private readonly _name?: string = undefined
-  private readonly _value?: string = undefined
+  private readonly _sourceModuleName?: string = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
-    _id?: EnvVariableID,
+    _id?: EnumTypeDefID,
+    _description?: string,
     _name?: string,
-    _value?: string
+    _sourceModuleName?: string
   ) {
     super(parent)
 
     this._id = _id
+    this._description = _description
     this._name = _name
-    this._value = _value
+    this._sourceModuleName = _sourceModuleName
   }
 
   /**
-   * A unique identifier for this EnvVariable.
+   * A unique identifier for this EnumTypeDef.
    */
-  id = async (): Promise<EnvVariableID> => {
+  id = async (): Promise<EnumTypeDefID> => {
     if (this._id) {
       return this._id
     }
 
-    const response: Awaited<EnvVariableID> = await computeQuery(
+    const response: Awaited<EnumTypeDefID> = await computeQuery(
       [
         ...this._queryTree,
         {
@@ -2933,16 +3630,16 @@ export class EnvVariable extends BaseClient {
 
     return response
   }
-  name = async (): Promise<string> => {
-    if (this._name) {
-      return this._name
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
     }
 
     const response: Awaited<string> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "name",
+          operation: "description",
         },
       ],
       await this._ctx.connection()
@@ -2950,16 +3647,16 @@ export class EnvVariable extends BaseClient {
 
     return response
   }
-  value = async (): Promise<string> => {
-    if (this._value) {
-      return this._value
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
     }
 
     const response: Awaited<string> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "value",
+          operation: "name",
         },
       ],
       await this._ctx.connection()
@@ -2967,10 +3664,223 @@ export class EnvVariable extends BaseClient {
 
     return response
   }
-}
+  sourceModuleName = async (): Promise<string> => {
+    if (this._sourceModuleName) {
+      return this._sourceModuleName
+    }
 
-/**
- * A definition of a field on a custom object defined in a Module.
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "sourceModuleName",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  values = async (): Promise<EnumValueTypeDef[]> => {
+    type values = {
+      id: EnumValueTypeDefID
+    }
+
+    const response: Awaited<values[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "values",
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response.map(
+      (r) =>
+        new EnumValueTypeDef(
+          {
+            queryTree: [
+              {
+                operation: "loadEnumValueTypeDefFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id
+        )
+    )
+  }
+}
+
+/**
+ * A definition of a value in a custom enum defined in a Module.
+ *
+ * The name of the value is what is sent over the wire, so it must be a valid GraphQL enum value.
+ */
+export class EnumValueTypeDef extends BaseClient {
+  private readonly _id?: EnumValueTypeDefID = undefined
+  private readonly _description?: string = undefined
+  private readonly _name?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: EnumValueTypeDefID,
+    _description?: string,
+    _name?: string
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._description = _description
+    this._name = _name
+  }
+
+  /**
+   * A unique identifier for this EnumValueTypeDef.
+   */
+  id = async (): Promise<EnumValueTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<EnumValueTypeDefID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "description",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+}
+
+/**
+ * An environment variable name and value.
+ */
+export class EnvVariable extends BaseClient {
+  private readonly _id?: EnvVariableID = undefined
+  private readonly _name?: string = undefined
+  private readonly _value?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: EnvVariableID,
+    _name?: string,
+    _value?: string
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._name = _name
+    this._value = _value
+  }
+
+  /**
+   * A unique identifier for this EnvVariable.
+   */
+  id = async (): Promise<EnvVariableID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<EnvVariableID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  value = async (): Promise<string> => {
+    if (this._value) {
+      return this._value
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "value",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+}
+
+/**
+ * A definition of a field on a custom object defined in a Module.
  *
  * A field on an object has a static value, as opposed to a function on an object whose value is computed by invoking code (and can accept arguments).
  */
@@ -3027,83 +3937,346 @@ export class FieldTypeDef extends BaseClient {
           operation: "description",
         },
       ],
-      await this._ctx.connection()
-    )
-
-    return response
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  typeDef = (): TypeDef => {
+    return new TypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "typeDef",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+}
+
+/**
+ * A file.
+ */
+export class File extends BaseClient {
+  private readonly _id?: FileID = undefined
+  private readonly _contents?: string = undefined
+  private readonly _digest?: string = undefined
+  private readonly _export?: boolean = undefined
+  private readonly _name?: string = undefined
+  private readonly _size?: number = undefined
+  private readonly _sync?: FileID = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: FileID,
+    _contents?: string,
+    _digest?: string,
+    _export?: boolean,
+    _name?: string,
+    _size?: number,
+    _sync?: FileID
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._contents = _contents
+    this._digest = _digest
+    this._export = _export
+    this._name = _name
+    this._size = _size
+    this._sync = _sync
+  }
+
+  /**
+   * A unique identifier for this File.
+   */
+  id = async (): Promise<FileID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<FileID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Retrieves the contents of the file.
+   */
+  contents = async (): Promise<string> => {
+    if (this._contents) {
+      return this._contents
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "contents",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Returns a sha256 digest of the file.
+   *
+   * With excludeMetadata set, this is the digest of the file's contents, i.e. the same as sha256sum.
+   * @param opts.excludeMetadata If true, permissions, ownership and modification times are not included in the digest.
+   */
+  digest = async (opts?: FileDigestOpts): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "digest",
+          args: { ...opts },
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Writes the file to a file path on the host.
+   * @param path Location of the written directory (e.g., "output.txt").
+   * @param opts.allowParentDirPath If allowParentDirPath is true, the path argument can be a directory path, in which case the file will be created in that directory.
+   */
+  export = async (path: string, opts?: FileExportOpts): Promise<boolean> => {
+    if (this._export) {
+      return this._export
+    }
+
+    const response: Awaited<boolean> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "export",
+          args: { path, ...opts },
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Extracts this file as an archive into a new directory.
+   *
+   * Supports tar archives, optionally compressed with gzip or zstd, and zip archives.
+   * @param opts.format The format of the archive.
+   *
+   * If not set, it is detected from the archive's contents. The compression of tar archives is always detected.
+   * @param opts.exclude Exclude entries that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   * @param opts.include Include only entries that match the given pattern (e.g., ["app/", "package.*"]).
+   */
+  extract = (opts?: FileExtractOpts): Directory => {
+    const metadata: Metadata = {
+      format: { is_enum: true },
+    }
+
+    return new Directory({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "extract",
+          args: { ...opts, __metadata: metadata },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Retrieves the name of the file.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Retrieves the size of the file, in bytes.
+   */
+  size = async (): Promise<number> => {
+    if (this._size) {
+      return this._size
+    }
+
+    const response: Awaited<number> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "size",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Retrieves information about the file, such as its type, permissions and ownership.
+   */
+  stat = (): FileInfo => {
+    return new FileInfo({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "stat",
+        },
+      ],
+      ctx: this._ctx,
+    })
   }
-  name = async (): Promise<string> => {
-    if (this._name) {
-      return this._name
-    }
 
-    const response: Awaited<string> = await computeQuery(
+  /**
+   * Force evaluation in the engine.
+   */
+  sync = async (): Promise<File> => {
+    await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "name",
+          operation: "sync",
         },
       ],
       await this._ctx.connection()
     )
 
-    return response
+    return this
   }
-  typeDef = (): TypeDef => {
-    return new TypeDef({
+
+  /**
+   * Retrieves this file with its created/modified timestamps set to the given time.
+   * @param timestamp Timestamp to set dir/files in.
+   *
+   * Formatted in seconds following Unix epoch (e.g., 1672531199).
+   */
+  withTimestamps = (timestamp: number): File => {
+    return new File({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "typeDef",
+          operation: "withTimestamps",
+          args: { timestamp },
         },
       ],
       ctx: this._ctx,
     })
   }
+
+  /**
+   * Call the provided function with current File.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: File) => File) => {
+    return arg(this)
+  }
 }
 
 /**
- * A file.
+ * Information about a file, directory or symlink.
  */
-export class File extends BaseClient {
-  private readonly _id?: FileID = undefined
-  private readonly _contents?: string = undefined
-  private readonly _export?: boolean = undefined
+export class FileInfo extends BaseClient {
+  private readonly _id?: FileInfoID = undefined
+  private readonly _fileType?: FileType = undefined
+  private readonly _gid?: number = undefined
+  private readonly _linkTarget?: string = undefined
   private readonly _name?: string = undefined
+  private readonly _permissions?: number = undefined
   private readonly _size?: number = undefined
-  private readonly _sync?: FileID = undefined
+  private readonly _uid?: number = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
   constructor(
     parent?: { queryTree?: QueryTree[]; ctx: Context },
-    _id?: FileID,
-    _contents?: string,
-    _export?: boolean,
+    _id?: FileInfoID,
+    _fileType?: FileType,
+    _gid?: number,
+    _linkTarget?: string,
     _name?: string,
+    _permissions?: number,
     _size?: number,
-    _sync?: FileID
+    _uid?: number
   ) {
     super(parent)
 
     this._id = _id
-    this._contents = _contents
-    this._export = _export
+    this._fileType = _fileType
+    this._gid = _gid
+    this._linkTarget = _linkTarget
     this._name = _name
+    this._permissions = _permissions
     this._size = _size
-    this._sync = _sync
+    this._uid = _uid
   }
 
   /**
-   * A unique identifier for this File.
+   * A unique identifier for this FileInfo.
    */
-  id = async (): Promise<FileID> => {
+  id = async (): Promise<FileInfoID> => {
     if (this._id) {
       return this._id
     }
 
-    const response: Awaited<FileID> = await computeQuery(
+    const response: Awaited<FileInfoID> = await computeQuery(
       [
         ...this._queryTree,
         {
@@ -3115,20 +4288,16 @@ export class File extends BaseClient {
 
     return response
   }
-
-  /**
-   * Retrieves the contents of the file.
-   */
-  contents = async (): Promise<string> => {
-    if (this._contents) {
-      return this._contents
+  fileType = async (): Promise<FileType> => {
+    if (this._fileType) {
+      return this._fileType
     }
 
-    const response: Awaited<string> = await computeQuery(
+    const response: Awaited<FileType> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "contents",
+          operation: "fileType",
         },
       ],
       await this._ctx.connection()
@@ -3136,23 +4305,16 @@ export class File extends BaseClient {
 
     return response
   }
-
-  /**
-   * Writes the file to a file path on the host.
-   * @param path Location of the written directory (e.g., "output.txt").
-   * @param opts.allowParentDirPath If allowParentDirPath is true, the path argument can be a directory path, in which case the file will be created in that directory.
-   */
-  export = async (path: string, opts?: FileExportOpts): Promise<boolean> => {
-    if (this._export) {
-      return this._export
+  gid = async (): Promise<number> => {
+    if (this._gid) {
+      return this._gid
     }
 
-    const response: Awaited<boolean> = await computeQuery(
+    const response: Awaited<number> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "export",
-          args: { path, ...opts },
+          operation: "gid",
         },
       ],
       await this._ctx.connection()
@@ -3160,10 +4322,23 @@ export class File extends BaseClient {
 
     return response
   }
+  linkTarget = async (): Promise<string> => {
+    if (this._linkTarget) {
+      return this._linkTarget
+    }
 
-  /**
-   * Retrieves the name of the file.
-   */
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "linkTarget",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
   name = async (): Promise<string> => {
     if (this._name) {
       return this._name
@@ -3181,20 +4356,16 @@ export class File extends BaseClient {
 
     return response
   }
-
-  /**
-   * Retrieves the size of the file, in bytes.
-   */
-  size = async (): Promise<number> => {
-    if (this._size) {
-      return this._size
+  permissions = async (): Promise<number> => {
+    if (this._permissions) {
+      return this._permissions
     }
 
     const response: Awaited<number> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "size",
+          operation: "permissions",
         },
       ],
       await this._ctx.connection()
@@ -3202,50 +4373,39 @@ export class File extends BaseClient {
 
     return response
   }
+  size = async (): Promise<number> => {
+    if (this._size) {
+      return this._size
+    }
 
-  /**
-   * Force evaluation in the engine.
-   */
-  sync = async (): Promise<File> => {
-    await computeQuery(
+    const response: Awaited<number> = await computeQuery(
       [
         ...this._queryTree,
         {
-          operation: "sync",
+          operation: "size",
         },
       ],
       await this._ctx.connection()
     )
 
-    return this
+    return response
   }
+  uid = async (): Promise<number> => {
+    if (this._uid) {
+      return this._uid
+    }
 
-  /**
-   * Retrieves this file with its created/modified timestamps set to the given time.
-   * @param timestamp Timestamp to set dir/files in.
-   *
-   * Formatted in seconds following Unix epoch (e.g., 1672531199).
-   */
-  withTimestamps = (timestamp: number): File => {
-    return new File({
-      queryTree: [
+    const response: Awaited<number> = await computeQuery(
+      [
         ...this._queryTree,
         {
-          operation: "withTimestamps",
-          args: { timestamp },
+          operation: "uid",
         },
       ],
-      ctx: this._ctx,
-    })
-  }
+      await this._ctx.connection()
+    )
 
-  /**
-   * Call the provided function with current File.
-   *
-   * This is useful for reusability and readability by not breaking the calling chain.
-   */
-  with = (arg: (param: File) => File) => {
-    return arg(this)
+    return response
   }
 }
 
@@ -3964,6 +5124,12 @@ export class GitRef extends BaseClient {
    * The filesystem tree at this ref.
    * @param opts.sshKnownHosts DEPRECATED: This option should be passed to `git` instead.
    * @param opts.sshAuthSocket DEPRECATED: This option should be passed to `git` instead.
+   * @param opts.depth Number of commits of history to fetch.
+   *
+   * Only the tip of the ref is fetched by default, unless it's a commit.
+   * @param opts.sparsePaths Only check out these paths (e.g., ["docs", "go.mod"]).
+   * @param opts.skipSubmodules Skip checking out submodules.
+   * @param opts.lfs Replace Git LFS pointer files with their contents.
    */
   tree = (opts?: GitRefTreeOpts): Directory => {
     return new Directory({
@@ -4035,6 +5201,23 @@ export class GitRepository extends BaseClient {
     })
   }
 
+  /**
+   * Returns the names of the repository's branches.
+   */
+  branches = async (): Promise<string[]> => {
+    const response: Awaited<string[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "branches",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
   /**
    * Returns details of a commit.
    * @param id Identifier of the commit (e.g., "b6315d8f2810962c601af73f86831f6866ea798b").
@@ -4068,6 +5251,25 @@ export class GitRepository extends BaseClient {
       ctx: this._ctx,
     })
   }
+
+  /**
+   * Returns the names of the repository's tags.
+   * @param opts.pattern Glob pattern to filter tags by (e.g., "v1.*").
+   */
+  tags = async (opts?: GitRepositoryTagsOpts): Promise<string[]> => {
+    const response: Awaited<string[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "tags",
+          args: { ...opts },
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
 }
 
 /**
@@ -4114,6 +5316,12 @@ export class Host extends BaseClient {
    * @param path Location of the directory to access (e.g., ".").
    * @param opts.exclude Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
    * @param opts.include Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+   * @param opts.daggerignore Also exclude artifacts that match the patterns in the .daggerignore file at the root of the directory, which uses the same syntax as .gitignore.
+   *
+   * Only the file at the root is read; .daggerignore files in subdirectories are not honored.
+   * @param opts.gitignore Also exclude artifacts that match the patterns in the .gitignore file at the root of the directory.
+   *
+   * Only the file at the root is read; .gitignore files in subdirectories are not honored.
    */
   directory = (path: string, opts?: HostDirectoryOpts): Directory => {
     return new Directory({
@@ -4711,6 +5919,40 @@ export class Module_ extends BaseClient {
 
     return response
   }
+  enums = async (): Promise<TypeDef[]> => {
+    type enums = {
+      id: TypeDefID
+    }
+
+    const response: Awaited<enums[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "enums",
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response.map(
+      (r) =>
+        new TypeDef(
+          {
+            queryTree: [
+              {
+                operation: "loadTypeDefFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id
+        )
+    )
+  }
   generatedCode = (): GeneratedCode => {
     return new GeneratedCode({
       queryTree: [
@@ -4822,6 +6064,40 @@ export class Module_ extends BaseClient {
         )
     )
   }
+  scalars = async (): Promise<TypeDef[]> => {
+    type scalars = {
+      id: TypeDefID
+    }
+
+    const response: Awaited<scalars[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "scalars",
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response.map(
+      (r) =>
+        new TypeDef(
+          {
+            queryTree: [
+              {
+                operation: "loadTypeDefFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id
+        )
+    )
+  }
   sdk = async (): Promise<string> => {
     if (this._sdk) {
       return this._sdk
@@ -4885,23 +6161,41 @@ export class Module_ extends BaseClient {
           operation: "sourceDirectorySubpath",
         },
       ],
-      await this._ctx.connection()
-    )
-
-    return response
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+
+  /**
+   * Retrieves the module with the given description
+   * @param description The description to set
+   */
+  withDescription = (description: string): Module_ => {
+    return new Module_({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withDescription",
+          args: { description },
+        },
+      ],
+      ctx: this._ctx,
+    })
   }
 
   /**
-   * Retrieves the module with the given description
-   * @param description The description to set
+   * This module plus the given Enum type and associated values
    */
-  withDescription = (description: string): Module_ => {
+  withEnum = (enum_: TypeDef): Module_ => {
     return new Module_({
       queryTree: [
         ...this._queryTree,
         {
-          operation: "withDescription",
-          args: { description },
+          operation: "withEnum",
+          args: {
+            enum: enum_,
+          },
         },
       ],
       ctx: this._ctx,
@@ -4940,6 +6234,22 @@ export class Module_ extends BaseClient {
     })
   }
 
+  /**
+   * This module plus the given Scalar type
+   */
+  withScalar = (scalar: TypeDef): Module_ => {
+    return new Module_({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withScalar",
+          args: { scalar },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Retrieves the module with basic configuration loaded, ready for initialization.
    * @param directory The directory containing the module's source code.
@@ -5459,20 +6769,66 @@ export class Client extends BaseClient {
   /**
    * Constructs a cache volume for a given cache key.
    * @param key A string identifier to target this cache volume (e.g., "modules-cache").
+   * @param opts.maxSize Maximum size of the cache volume's contents, in bytes. If the contents exceed it when an exec mounts the volume, they are wiped entirely rather than trimmed.
+   *
+   * The size is only checked when the volume is mounted, so an exec may grow the contents past it.
+   *
+   * Set to 0 for no limit.
+   * @param opts.ttl Number of seconds the cache volume's contents are kept once they stop being used. Older contents are wiped entirely when an exec next mounts the volume.
+   *
+   * Set to 0 for no limit.
    */
-  cacheVolume = (key: string): CacheVolume => {
+  cacheVolume = (key: string, opts?: ClientCacheVolumeOpts): CacheVolume => {
     return new CacheVolume({
       queryTree: [
         ...this._queryTree,
         {
           operation: "cacheVolume",
-          args: { key },
+          args: { key, ...opts },
         },
       ],
       ctx: this._ctx,
     })
   }
 
+  /**
+   * Lists the cache volumes with contents in the engine's local cache.
+   */
+  cacheVolumes = async (): Promise<CacheVolume[]> => {
+    type cacheVolumes = {
+      id: CacheVolumeID
+    }
+
+    const response: Awaited<cacheVolumes[]> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "cacheVolumes",
+        },
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response.map(
+      (r) =>
+        new CacheVolume(
+          {
+            queryTree: [
+              {
+                operation: "loadCacheVolumeFromID",
+                args: { id: r.id },
+              },
+            ],
+            ctx: this._ctx,
+          },
+          r.id
+        )
+    )
+  }
+
   /**
    * Checks if the current Dagger Engine is compatible with an SDK's required version.
    * @param version Version required by the SDK.
@@ -5677,6 +7033,7 @@ export class Client extends BaseClient {
    * @param opts.experimentalServiceHost A service which must be started before the repo is fetched.
    * @param opts.sshKnownHosts Set SSH known hosts
    * @param opts.sshAuthSocket Set SSH auth socket
+   * @param opts.httpAuthToken A secret containing a token used to authenticate to an HTTPS remote.
    */
   git = (url: string, opts?: ClientGitOpts): GitRepository => {
     return new GitRepository({
@@ -5710,6 +7067,13 @@ export class Client extends BaseClient {
    * Returns a file containing an http remote url content.
    * @param url HTTP url to get the content from (e.g., "https://docs.dagger.io").
    * @param opts.experimentalServiceHost A service which must be started before the URL is fetched.
+   * @param opts.checksum Expected digest of the content (e.g., "sha256:...").
+   *
+   * The download fails if the content doesn't match, and a matching download is never fetched again.
+   * @param opts.headers Headers to send with the request.
+   * @param opts.authHeader A secret whose value is sent as the Authorization header.
+   * @param opts.name Name of the downloaded file.
+   * @param opts.permissions Permission given to the downloaded file (e.g., 0600).
    */
   http = (url: string, opts?: ClientHttpOpts): File => {
     return new File({
@@ -5772,6 +7136,38 @@ export class Client extends BaseClient {
     })
   }
 
+  /**
+   * Load a EnumTypeDef from its ID.
+   */
+  loadEnumTypeDefFromID = (id: EnumTypeDefID): EnumTypeDef => {
+    return new EnumTypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadEnumTypeDefFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Load a EnumValueTypeDef from its ID.
+   */
+  loadEnumValueTypeDefFromID = (id: EnumValueTypeDefID): EnumValueTypeDef => {
+    return new EnumValueTypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadEnumValueTypeDefFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load a EnvVariable from its ID.
    */
@@ -5820,6 +7216,22 @@ export class Client extends BaseClient {
     })
   }
 
+  /**
+   * Load a FileInfo from its ID.
+   */
+  loadFileInfoFromID = (id: FileInfoID): FileInfo => {
+    return new FileInfo({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadFileInfoFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load a FunctionArg from its ID.
    */
@@ -6078,6 +7490,22 @@ export class Client extends BaseClient {
     })
   }
 
+  /**
+   * Load a ScalarTypeDef from its ID.
+   */
+  loadScalarTypeDefFromID = (id: ScalarTypeDefID): ScalarTypeDef => {
+    return new ScalarTypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "loadScalarTypeDefFromID",
+          args: { id },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Load a Secret from its ID.
    */
@@ -6289,6 +7717,106 @@ export class Client extends BaseClient {
   }
 }
 
+/**
+ * A definition of a custom scalar defined in a Module.
+ */
+export class ScalarTypeDef extends BaseClient {
+  private readonly _id?: ScalarTypeDefID = undefined
+  private readonly _description?: string = undefined
+  private readonly _name?: string = undefined
+  private readonly _sourceModuleName?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    parent?: { queryTree?: QueryTree[]; ctx: Context },
+    _id?: ScalarTypeDefID,
+    _description?: string,
+    _name?: string,
+    _sourceModuleName?: string
+  ) {
+    super(parent)
+
+    this._id = _id
+    this._description = _description
+    this._name = _name
+    this._sourceModuleName = _sourceModuleName
+  }
+
+  /**
+   * A unique identifier for this ScalarTypeDef.
+   */
+  id = async (): Promise<ScalarTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const response: Awaited<ScalarTypeDefID> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "id",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "description",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "name",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+  sourceModuleName = async (): Promise<string> => {
+    if (this._sourceModuleName) {
+      return this._sourceModuleName
+    }
+
+    const response: Awaited<string> = await computeQuery(
+      [
+        ...this._queryTree,
+        {
+          operation: "sourceModuleName",
+        },
+      ],
+      await this._ctx.connection()
+    )
+
+    return response
+  }
+}
+
 /**
  * A reference to a secret value, which can be handled more safely than the value itself.
  */
@@ -6701,6 +8229,17 @@ export class TypeDef extends BaseClient {
 
     return response
   }
+  asEnum = (): EnumTypeDef => {
+    return new EnumTypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "asEnum",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
   asInput = (): InputTypeDef => {
     return new InputTypeDef({
       queryTree: [
@@ -6745,6 +8284,17 @@ export class TypeDef extends BaseClient {
       ctx: this._ctx,
     })
   }
+  asScalar = (): ScalarTypeDef => {
+    return new ScalarTypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "asScalar",
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
   kind = async (): Promise<TypeDefKind> => {
     if (this._kind) {
       return this._kind
@@ -6798,6 +8348,44 @@ export class TypeDef extends BaseClient {
     })
   }
 
+  /**
+   * Returns a TypeDef of kind Enum with the provided name.
+   *
+   * Note that an enum's values may be omitted if the intent is only to refer to an enum. This is how functions are able to return their own, or any other circular reference.
+   * @param name The name of the enum
+   * @param opts.description A doc string for the enum, if any
+   */
+  withEnum = (name: string, opts?: TypeDefWithEnumOpts): TypeDef => {
+    return new TypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withEnum",
+          args: { name, ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
+  /**
+   * Adds a static value for an Enum TypeDef, failing if the type is not an enum.
+   * @param value The name of the value in the enum
+   * @param opts.description A doc string for the value, if any
+   */
+  withEnumValue = (value: string, opts?: TypeDefWithEnumValueOpts): TypeDef => {
+    return new TypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withEnumValue",
+          args: { value, ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Adds a static field for an Object TypeDef, failing if the type is not an object.
    * @param name The name of the field in the object
@@ -6925,6 +8513,26 @@ export class TypeDef extends BaseClient {
     })
   }
 
+  /**
+   * Returns a TypeDef of kind Scalar with the provided name.
+   *
+   * Values of the scalar are passed between the caller and the module as-is, without any validation by the engine.
+   * @param name The name of the scalar
+   * @param opts.description A doc string for the scalar, if any
+   */
+  withScalar = (name: string, opts?: TypeDefWithScalarOpts): TypeDef => {
+    return new TypeDef({
+      queryTree: [
+        ...this._queryTree,
+        {
+          operation: "withScalar",
+          args: { name, ...opts },
+        },
+      ],
+      ctx: this._ctx,
+    })
+  }
+
   /**
    * Call the provided function with current TypeDef.
    *
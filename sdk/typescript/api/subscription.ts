@@ -0,0 +1,56 @@
+import { subscribeGQL } from "../graphql/client.js"
+import { Container, Service } from "./client.gen.js"
+import { responseError } from "./utils.js"
+
+/**
+ * Follow the output of the last command executed by the container as it's
+ * written.
+ *
+ * If the command is cached, its whole output is yielded at once.
+ */
+export async function* containerStdout(
+  container: Container
+): AsyncGenerator<string> {
+  yield* subscribe(
+    container,
+    `subscription($container: ContainerID!) { containerStdout(container: $container) }`,
+    { container: await container.id() },
+    "containerStdout"
+  )
+}
+
+/**
+ * Follow the combined stdout and stderr of the service as they're written,
+ * starting the service if it isn't running.
+ *
+ * The stream ends when the service exits.
+ */
+export async function* serviceLogs(service: Service): AsyncGenerator<string> {
+  yield* subscribe(
+    service,
+    `subscription($service: ServiceID!) { serviceLogs(service: $service) }`,
+    { service: await service.id() },
+    "serviceLogs"
+  )
+}
+
+async function* subscribe(
+  parent: Container | Service,
+  query: string,
+  variables: Record<string, unknown>,
+  field: string
+): AsyncGenerator<string> {
+  const client = await parent.ctx.connection()
+
+  for await (const response of subscribeGQL(client, query, variables)) {
+    if (response.errors?.length) {
+      throw responseError({ query, variables }, response)
+    }
+
+    const data = response.data as Record<string, string | null> | undefined
+    const value = data?.[field]
+    if (value !== null && value !== undefined) {
+      yield value
+    }
+  }
+}
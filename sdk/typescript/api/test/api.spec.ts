@@ -12,6 +12,7 @@ import {
   ClientContainerOpts,
   connect,
   Container,
+  containerStdout,
   Directory,
   NetworkProtocol,
 } from "../../index.js"
@@ -422,4 +423,22 @@ describe("TypeScript SDK api", function () {
       assert.strictEqual(env, "TCP")
     })
   })
+
+  it("Follow container stdout", async function () {
+    this.timeout(60000)
+
+    await connect(async (client) => {
+      const ctr = client
+        .container()
+        .from("alpine:3.16.2")
+        .withExec(["sh", "-c", `echo ${randomUUID()}; echo hello; echo world`])
+
+      let stdout = ""
+      for await (const chunk of containerStdout(ctr)) {
+        stdout += chunk
+      }
+
+      assert(stdout.endsWith("hello\nworld\n"))
+    })
+  })
 })
@@ -1,5 +1,10 @@
 /* eslint-disable @typescript-eslint/no-explicit-any */
 import { ClientError, gql, GraphQLClient } from "graphql-request"
+import {
+  GraphQLClientResponse,
+  GraphQLRequestContext,
+  GraphQLResponse,
+} from "graphql-request/build/esm/types.js"
 
 import {
   GraphQLRequestError,
@@ -7,9 +12,39 @@ import {
   UnknownDaggerError,
   NotAwaitedRequestError,
   ExecError,
+  ExecErrorReason,
 } from "../common/errors/index.js"
 import { Metadata, QueryTree } from "./client.gen.js"
 
+/**
+ * Convert the first error of a GraphQL response into an SDK error.
+ * @hidden
+ */
+export function responseError(
+  request: GraphQLRequestContext,
+  response: GraphQLResponse,
+  cause?: Error
+): ExecError | GraphQLRequestError {
+  const msg = response.errors?.[0]?.message ?? `API Error`
+  const ext = response.errors?.[0]?.extensions
+
+  if (ext?._type === "EXEC_ERROR") {
+    return new ExecError(msg, {
+      cmd: (ext.cmd as string[]) ?? [],
+      exitCode: (ext.exitCode as number) ?? -1,
+      reason: ext.reason as ExecErrorReason | undefined,
+      stdout: (ext.stdout as string) ?? "",
+      stderr: (ext.stderr as string) ?? "",
+    })
+  }
+
+  return new GraphQLRequestError(msg, {
+    request: request,
+    response: response,
+    cause: cause,
+  })
+}
+
 /**
  * Format argument into GraphQL query format.
  */
@@ -73,21 +108,27 @@ async function computeNestedQuery(
   const isArrayQueryTree = (value: any[]) =>
     value.every((v) => v instanceof Object && isQueryTree(v))
 
-  // Prepare query tree for final query by computing nested queries
-  // and building it with their results.
-  const computeQueryTree = async (value: any): Promise<string> => {
+  // Compute the id of a nested object, or the short reference the engine
+  // returned along with it, which is passed instead to keep queries small.
+  const computeID = async (value: any): Promise<string> => {
     // Resolve sub queries if operation's args is a subquery
     for (const op of value["_queryTree"]) {
       await computeNestedQuery([op], client)
     }
 
     // push an id that will be used by the container
-    return buildQuery([
+    const tree: QueryTree[] = [
       ...value["_queryTree"],
       {
         operation: "id",
       },
-    ])
+    ]
+
+    const { data, extensions } = await request(buildQuery(tree), client)
+    const path = tree.map(({ operation }) => operation).join(".")
+    const ref = (extensions as any)?.idRefs?.[path]
+
+    return ref ?? queryFlatten(data)
   }
 
   // Remove all undefined args and assert args type
@@ -98,10 +139,7 @@ async function computeNestedQuery(
       // Compute nested query for single object
       Object.entries(q.args).map(async ([key, value]: any) => {
         if (value instanceof Object && isQueryTree(value)) {
-          // push an id that will be used by the container
-          const getQueryTree = await computeQueryTree(value)
-
-          q.args[key] = await compute(getQueryTree, client)
+          q.args[key] = await computeID(value)
         }
 
         // Compute nested query for array of object
@@ -109,10 +147,7 @@ async function computeNestedQuery(
           const tmp: any = q.args[key]
 
           for (let i = 0; i < value.length; i++) {
-            // push an id that will be used by the container
-            const getQueryTree = await computeQueryTree(value[i])
-
-            tmp[i] = await compute(getQueryTree, client)
+            tmp[i] = await computeID(value[i])
           }
 
           q.args[key] = tmp
@@ -195,32 +230,28 @@ export async function compute<T>(
   query: string,
   client: GraphQLClient
 ): Promise<T> {
-  let computeQuery: Awaited<T>
+  const { data } = await request(query, client)
+
+  return queryFlatten(data)
+}
+
+/**
+ * Send a GraphQL document to the server
+ * return the whole response, including its extensions
+ */
+async function request(
+  query: string,
+  client: GraphQLClient
+): Promise<GraphQLClientResponse<unknown>> {
   try {
-    computeQuery = await client.request(
+    return await client.rawRequest(
       gql`
         ${query}
       `
     )
   } catch (e: any) {
     if (e instanceof ClientError) {
-      const msg = e.response.errors?.[0]?.message ?? `API Error`
-      const ext = e.response.errors?.[0]?.extensions
-
-      if (ext?._type === "EXEC_ERROR") {
-        throw new ExecError(msg, {
-          cmd: (ext.cmd as string[]) ?? [],
-          exitCode: (ext.exitCode as number) ?? -1,
-          stdout: (ext.stdout as string) ?? "",
-          stderr: (ext.stderr as string) ?? "",
-        })
-      }
-
-      throw new GraphQLRequestError(msg, {
-        request: e.request,
-        response: e.response,
-        cause: e,
-      })
+      throw responseError(e.request, e.response, e)
     }
 
     // Looking for connection error in case the function has not been awaited.
@@ -237,6 +268,4 @@ export async function compute<T>(
       { cause: e }
     )
   }
-
-  return queryFlatten(computeQuery)
 }
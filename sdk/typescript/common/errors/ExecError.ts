@@ -1,9 +1,25 @@
 import { DaggerSDKError, DaggerSDKErrorOptions } from "./DaggerSDKError.js"
 import { ERROR_CODES, ERROR_NAMES } from "./errors-codes.js"
 
+/**
+ * Why an exec was stopped, if it didn't simply exit with a non-zero code.
+ */
+export enum ExecErrorReason {
+  /**
+   * The command ran past its timeout and was killed.
+   */
+  Timeout = "TIMEOUT",
+
+  /**
+   * The command ran past its memory limit and was killed.
+   */
+  OOMKilled = "OOM_KILLED",
+}
+
 interface ExecErrorOptions extends DaggerSDKErrorOptions {
   cmd: string[]
   exitCode: number
+  reason?: ExecErrorReason
   stdout: string
   stderr: string
 }
@@ -25,6 +41,11 @@ export class ExecError extends DaggerSDKError {
    */
   exitCode: number
 
+  /**
+   * Why the command was stopped, if it didn't exit on its own.
+   */
+  reason?: ExecErrorReason
+
   /**
    * The stdout of the command.
    */
@@ -42,6 +63,7 @@ export class ExecError extends DaggerSDKError {
     super(message, options)
     this.cmd = options.cmd
     this.exitCode = options.exitCode
+    this.reason = options.reason
     this.stdout = options.stdout
     this.stderr = options.stderr
   }
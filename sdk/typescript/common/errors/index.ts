@@ -2,7 +2,7 @@ export { DaggerSDKError } from "./DaggerSDKError.js"
 export { UnknownDaggerError } from "./UnknownDaggerError.js"
 export { DockerImageRefValidationError } from "./DockerImageRefValidationError.js"
 export { EngineSessionConnectParamsParseError } from "./EngineSessionConnectParamsParseError.js"
-export { ExecError } from "./ExecError.js"
+export { ExecError, ExecErrorReason } from "./ExecError.js"
 export { GraphQLRequestError } from "./GraphQLRequestError.js"
 export { InitEngineSessionBinaryError } from "./InitEngineSessionBinaryError.js"
 export { TooManyNestedObjectsError } from "./TooManyNestedObjectsError.js"
@@ -0,0 +1,7 @@
+/**
+ * Alias of number to declare a Float argument, field or return value
+ * in a module.
+ *
+ * A plain `number` is registered as an Integer in the Dagger API.
+ */
+export type float = number
@@ -154,7 +154,10 @@ export async function loadArg(
     // Cannot use , to specify multiple matching case so instead we use fallthrough.
     case TypeDefKind.StringKind:
     case TypeDefKind.IntegerKind:
+    case TypeDefKind.FloatKind:
     case TypeDefKind.BooleanKind:
+    case TypeDefKind.EnumKind:
+    case TypeDefKind.ScalarKind:
     case TypeDefKind.VoidKind:
       return value
     default:
@@ -9,10 +9,12 @@ import {
 import { ScanResult } from "../introspector/scanner/scan.js"
 import {
   ConstructorTypeDef,
+  EnumTypeDef,
   FunctionArg,
   FunctionTypedef,
   ListTypeDef,
   ObjectTypeDef,
+  ScalarTypeDef,
   TypeDef as ScannerTypeDef,
 } from "../introspector/scanner/typeDefs.js"
 
@@ -26,6 +28,30 @@ export async function register(
   // Get the current module
   let mod = dag.currentModule()
 
+  // For each scalar scanned, register its type in the module.
+  Object.values(scanResult.scalars).forEach((modScalar) => {
+    mod = mod.withScalar(
+      dag.typeDef().withScalar(modScalar.name, {
+        description: modScalar.description,
+      })
+    )
+  })
+
+  // For each enum scanned, register its type and values in the module.
+  Object.values(scanResult.enums).forEach((modEnum) => {
+    let typeDef = dag.typeDef().withEnum(modEnum.name, {
+      description: modEnum.description,
+    })
+
+    Object.values(modEnum.values).forEach((value) => {
+      typeDef = typeDef.withEnumValue(value.value, {
+        description: value.description,
+      })
+    })
+
+    mod = mod.withEnum(typeDef)
+  })
+
   // For each class scanned, register its type, method and properties in the module.
   Object.values(scanResult.classes).map((modClass) => {
     // Register the class Typedef object in Dagger
@@ -123,6 +149,10 @@ function addTypeDef(type: ScannerTypeDef<TypeDefKind>): TypeDef {
   switch (type.kind) {
     case TypeDefKind.ObjectKind:
       return dag.typeDef().withObject((type as ObjectTypeDef).name)
+    case TypeDefKind.EnumKind:
+      return dag.typeDef().withEnum((type as EnumTypeDef).name)
+    case TypeDefKind.ScalarKind:
+      return dag.typeDef().withScalar((type as ScalarTypeDef).name)
     case TypeDefKind.ListKind:
       return dag.typeDef().withListOf(addTypeDef((type as ListTypeDef).typeDef))
     case TypeDefKind.VoidKind:
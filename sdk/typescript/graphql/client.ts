@@ -1,9 +1,98 @@
 import { GraphQLClient } from "graphql-request"
+import { GraphQLResponse } from "graphql-request/build/esm/types.js"
+import fetch from "node-fetch"
+
+type Endpoint = {
+  url: string
+  headers: Record<string, string>
+}
+
+// graphql-request doesn't expose where a client is connected to, so it's
+// remembered here for subscriptions, which it doesn't support.
+const endpoints = new WeakMap<GraphQLClient, Endpoint>()
 
 export function createGQLClient(port: number, token: string): GraphQLClient {
-  return new GraphQLClient(`http://127.0.0.1:${port}/query`, {
+  const endpoint = {
+    url: `http://127.0.0.1:${port}/query`,
     headers: {
       Authorization: "Basic " + Buffer.from(token + ":").toString("base64"),
     },
+  }
+
+  const client = new GraphQLClient(endpoint.url, {
+    headers: endpoint.headers,
   })
+  endpoints.set(client, endpoint)
+
+  return client
+}
+
+/**
+ * Send a GraphQL subscription to the engine and yield each response as it's
+ * streamed back as a server-sent event, until the stream completes.
+ */
+export async function* subscribeGQL(
+  client: GraphQLClient,
+  query: string,
+  variables: Record<string, unknown>
+): AsyncGenerator<GraphQLResponse> {
+  const endpoint = endpoints.get(client)
+  if (!endpoint) {
+    throw new Error("subscriptions are only supported by engine clients")
+  }
+
+  const resp = await fetch(endpoint.url, {
+    method: "POST",
+    headers: {
+      ...endpoint.headers,
+      "Content-Type": "application/json",
+      Accept: "text/event-stream",
+    },
+    body: JSON.stringify({ query, variables }),
+  })
+  if (!resp.ok || !resp.body) {
+    throw new Error(`subscribe: ${resp.status}: ${await resp.text()}`)
+  }
+
+  const decoder = new TextDecoder()
+  let buf = ""
+  for await (const chunk of resp.body) {
+    buf += decoder.decode(chunk as Buffer, { stream: true }).replace(/\r/g, "")
+
+    let end: number
+    while ((end = buf.indexOf("\n\n")) >= 0) {
+      const { event, data } = parseEvent(buf.slice(0, end))
+      buf = buf.slice(end + 2)
+
+      if (event === "complete") {
+        return
+      }
+      if (event === "next") {
+        yield { ...JSON.parse(data), status: resp.status }
+      }
+    }
+  }
+
+  // the stream always ends with a "complete" event
+  throw new Error("subscribe: stream ended unexpectedly")
+}
+
+function parseEvent(block: string): { event: string; data: string } {
+  let event = ""
+  const data: string[] = []
+  for (const line of block.split("\n")) {
+    const sep = line.indexOf(":")
+    const name = sep < 0 ? line : line.slice(0, sep)
+    const value = sep < 0 ? "" : line.slice(sep + 1).replace(/^ /, "")
+    switch (name) {
+      case "event":
+        event = value
+        break
+      case "data":
+        data.push(value)
+        break
+      // empty names are comments
+    }
+  }
+  return { event, data: data.join("\n") }
 }
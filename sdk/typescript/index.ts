@@ -1,5 +1,7 @@
 export * from "./api/client.gen.js"
+export { containerStdout, serviceLogs } from "./api/subscription.js"
 export * from "./common/errors/index.js"
+export { float } from "./common/types.js"
 export { gql } from "graphql-tag"
 export { GraphQLClient } from "graphql-request"
 export { connect, CallbackFct, connection, close } from "./connect.js"
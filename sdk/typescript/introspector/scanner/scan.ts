@@ -5,20 +5,27 @@ import { serializeSignature, serializeSymbol } from "./serialize.js"
 import {
   ClassTypeDef,
   ConstructorTypeDef,
+  EnumDef,
   FieldTypeDef,
   FunctionArg,
   FunctionTypedef,
+  ScalarDef,
 } from "./typeDefs.js"
 import {
+  CustomTypes,
+  isExported,
   isFunction,
   isObject,
   isOptional,
   isPublicProperty,
+  isScalar,
   typeNameToTypedef,
 } from "./utils.js"
 
 export type ScanResult = {
   classes: { [name: string]: ClassTypeDef }
+  enums: { [name: string]: EnumDef }
+  scalars: { [name: string]: ScalarDef }
   functions: { [name: string]: FunctionTypedef }
 }
 
@@ -43,19 +50,47 @@ export function scan(files: string[]): ScanResult {
 
   const metadata: ScanResult = {
     classes: {},
+    enums: {},
+    scalars: {},
     functions: {},
   }
 
-  for (const file of program.getSourceFiles()) {
+  const sourceFiles = program
+    .getSourceFiles()
     // Ignore type declaration files.
-    if (file.isDeclarationFile) {
-      continue
-    }
+    .filter((file) => !file.isDeclarationFile)
+
+  // Handle enums and scalars first so classes can refer to them.
+  for (const file of sourceFiles) {
+    ts.forEachChild(file, (node) => {
+      if (ts.isEnumDeclaration(node) && isExported(node)) {
+        const enumDef = introspectEnum(checker, node)
+
+        metadata.enums[enumDef.name] = enumDef
+      }
+
+      if (
+        ts.isTypeAliasDeclaration(node) &&
+        isExported(node) &&
+        isScalar(node)
+      ) {
+        const scalarDef = introspectScalar(checker, node)
+
+        metadata.scalars[scalarDef.name] = scalarDef
+      }
+    })
+  }
 
+  const customTypes: CustomTypes = {
+    enums: Object.keys(metadata.enums),
+    scalars: Object.keys(metadata.scalars),
+  }
+
+  for (const file of sourceFiles) {
     ts.forEachChild(file, (node) => {
       // Handle class
       if (ts.isClassDeclaration(node) && isObject(node)) {
-        const classTypeDef = introspectClass(checker, node)
+        const classTypeDef = introspectClass(checker, node, customTypes)
 
         metadata.classes[classTypeDef.name] = classTypeDef
       }
@@ -65,6 +100,83 @@ export function scan(files: string[]): ScanResult {
   return metadata
 }
 
+/**
+ * Introspect an enum and return its metadata.
+ *
+ * Each member of the enum must be initialized with a string, which is
+ * the value sent to and received from the Dagger API.
+ *
+ * This function throws an error if it cannot read its symbol.
+ *
+ * @param checker The typescript compiler checker.
+ * @param node The enum to check.
+ */
+function introspectEnum(
+  checker: ts.TypeChecker,
+  node: ts.EnumDeclaration
+): EnumDef {
+  const enumSymbol = checker.getSymbolAtLocation(node.name)
+  if (!enumSymbol) {
+    throw new UnknownDaggerError(
+      `could not get enum symbol: ${node.name.getText()}`,
+      {}
+    )
+  }
+
+  const { name, description } = serializeSymbol(checker, enumSymbol)
+
+  const metadata: EnumDef = {
+    name,
+    description,
+    values: {},
+  }
+
+  node.members.forEach((member) => {
+    const value = checker.getConstantValue(member)
+    if (typeof value !== "string") {
+      throw new UnknownDaggerError(
+        `enum member ${name}.${member.name.getText()} must be initialized with a string`,
+        {}
+      )
+    }
+
+    const memberSymbol = checker.getSymbolAtLocation(member.name)
+    const description = memberSymbol
+      ? serializeSymbol(checker, memberSymbol).description
+      : ""
+
+    metadata.values[value] = { value, description }
+  })
+
+  return metadata
+}
+
+/**
+ * Introspect a type alias of a primitive type and return its metadata
+ * as a scalar.
+ *
+ * This function throws an error if it cannot read its symbol.
+ *
+ * @param checker The typescript compiler checker.
+ * @param node The type alias to check.
+ */
+function introspectScalar(
+  checker: ts.TypeChecker,
+  node: ts.TypeAliasDeclaration
+): ScalarDef {
+  const scalarSymbol = checker.getSymbolAtLocation(node.name)
+  if (!scalarSymbol) {
+    throw new UnknownDaggerError(
+      `could not get scalar symbol: ${node.name.getText()}`,
+      {}
+    )
+  }
+
+  const { name, description } = serializeSymbol(checker, scalarSymbol)
+
+  return { name, description }
+}
+
 /**
  * Introspect a class and return its metadata.
  *
@@ -75,10 +187,12 @@ export function scan(files: string[]): ScanResult {
  *
  * @param checker The typescript compiler checker.
  * @param node The class to check.
+ * @param customTypes The names of the enums and scalars declared by the module.
  */
 function introspectClass(
   checker: ts.TypeChecker,
-  node: ts.ClassDeclaration
+  node: ts.ClassDeclaration,
+  customTypes: CustomTypes
 ): ClassTypeDef {
   // Throw error if node.name is undefined because we cannot scan its symbol.
   if (!node.name) {
@@ -110,19 +224,19 @@ function introspectClass(
   node.members.forEach((member) => {
     // Handle constructor
     if (ts.isConstructorDeclaration(member)) {
-      metadata.constructor = introspectConstructor(checker, member)
+      metadata.constructor = introspectConstructor(checker, member, customTypes)
     }
 
     // Handle method from the class.
     if (ts.isMethodDeclaration(member) && isFunction(member)) {
-      const fctTypeDef = introspectMethod(checker, member)
+      const fctTypeDef = introspectMethod(checker, member, customTypes)
 
       metadata.methods[fctTypeDef.name] = fctTypeDef
     }
 
     // Handle public properties from the class.
     if (ts.isPropertyDeclaration(member)) {
-      const fieldTypeDef = introspectProperty(checker, member)
+      const fieldTypeDef = introspectProperty(checker, member, customTypes)
 
       metadata.fields[fieldTypeDef.name] = fieldTypeDef
     }
@@ -138,10 +252,12 @@ function introspectClass(
  *
  * @param checker The typescript compiler checker.
  * @param property The method to check.
+ * @param customTypes The names of the enums and scalars declared by the module.
  */
 function introspectProperty(
   checker: ts.TypeChecker,
-  property: ts.PropertyDeclaration
+  property: ts.PropertyDeclaration,
+  customTypes: CustomTypes
 ): FieldTypeDef {
   const propertySymbol = checker.getSymbolAtLocation(property.name)
   if (!propertySymbol) {
@@ -159,7 +275,7 @@ function introspectProperty(
   return {
     name,
     description,
-    typeDef: typeNameToTypedef(typeName),
+    typeDef: typeNameToTypedef(typeName, customTypes),
     isExposed: isPublicProperty(property),
   }
 }
@@ -169,7 +285,8 @@ function introspectProperty(
  */
 function introspectConstructor(
   checker: ts.TypeChecker,
-  constructor: ts.ConstructorDeclaration
+  constructor: ts.ConstructorDeclaration,
+  customTypes: CustomTypes
 ): ConstructorTypeDef {
   const args = constructor.parameters.reduce(
     (acc: { [name: string]: FunctionArg }, param) => {
@@ -185,12 +302,12 @@ function introspectConstructor(
         checker,
         paramSymbol
       )
-      const { optional, defaultValue } = isOptional(paramSymbol)
+      const { optional, defaultValue } = isOptional(checker, paramSymbol)
 
       acc[name] = {
         name,
         description,
-        typeDef: typeNameToTypedef(typeName),
+        typeDef: typeNameToTypedef(typeName, customTypes),
         optional,
         defaultValue,
       }
@@ -213,10 +330,12 @@ function introspectConstructor(
  *
  * @param checker The typescript compiler checker.
  * @param method The method to check.
+ * @param customTypes The names of the enums and scalars declared by the module.
  */
 function introspectMethod(
   checker: ts.TypeChecker,
-  method: ts.MethodDeclaration | ts.ArrowFunction
+  method: ts.MethodDeclaration | ts.ArrowFunction,
+  customTypes: CustomTypes
 ): FunctionTypedef {
   const methodSymbol = checker.getSymbolAtLocation(method.name)
   if (!methodSymbol) {
@@ -241,7 +360,7 @@ function introspectMethod(
       ) => {
         acc[name] = {
           name,
-          typeDef: typeNameToTypedef(typeName),
+          typeDef: typeNameToTypedef(typeName, customTypes),
           description,
           optional,
           defaultValue,
@@ -251,6 +370,6 @@ function introspectMethod(
       },
       {}
     ),
-    returnType: typeNameToTypedef(methodSignature.returnType),
+    returnType: typeNameToTypedef(methodSignature.returnType, customTypes),
   }
 }
@@ -18,7 +18,7 @@ export function serializeSignature(
 ): SignatureMetadata {
   return {
     params: signature.parameters.map((param) => {
-      const { optional, defaultValue } = isOptional(param)
+      const { optional, defaultValue } = isOptional(checker, param)
 
       return {
         ...serializeSymbol(checker, param),
@@ -26,7 +26,11 @@ export function serializeSignature(
         defaultValue,
       }
     }),
-    returnType: serializeType(checker, signature.getReturnType()),
+    returnType: serializeType(
+      checker,
+      signature.getReturnType(),
+      signature.getDeclaration().type
+    ),
   }
 }
 
@@ -54,12 +58,20 @@ export function serializeSymbol(
     symbol.valueDeclaration
   )
 
+  let typeNode: ts.TypeNode | undefined
+  if (
+    ts.isParameter(symbol.valueDeclaration) ||
+    ts.isPropertyDeclaration(symbol.valueDeclaration)
+  ) {
+    typeNode = symbol.valueDeclaration.type
+  }
+
   return {
     name: symbol.getName(),
     description: ts.displayPartsToString(
       symbol.getDocumentationComment(checker)
     ),
-    typeName: serializeType(checker, type),
+    typeName: serializeType(checker, type, typeNode),
     type,
   }
 }
@@ -68,10 +80,23 @@ export function serializeSymbol(
  * Convert the TypeScript type from the compiler API into a readable textual
  * type.
  *
+ * If the declared type node refers to an alias of a primitive type, the
+ * alias name is returned instead since the compiler erases it.
+ *
  * @param checker The typescript compiler checker.
  * @param type The type to convert.
+ * @param typeNode The declared type of the symbol, if any.
  */
-export function serializeType(checker: ts.TypeChecker, type: ts.Type): string {
+export function serializeType(
+  checker: ts.TypeChecker,
+  type: ts.Type,
+  typeNode?: ts.TypeNode
+): string {
+  const aliasName = serializePrimitiveAlias(checker, typeNode)
+  if (aliasName) {
+    return aliasName
+  }
+
   const strType = checker.typeToString(type)
 
   // Remove Promise<> wrapper around type if it's a promise.
@@ -81,3 +106,59 @@ export function serializeType(checker: ts.TypeChecker, type: ts.Type): string {
 
   return strType
 }
+
+/**
+ * Return the name of the primitive type alias (e.g. `type Semver = string`)
+ * referenced by the given type node, looking through `Promise<>` and arrays.
+ *
+ * Returns undefined if the node doesn't refer to such an alias.
+ *
+ * @param checker The typescript compiler checker.
+ * @param typeNode The type node to check.
+ */
+function serializePrimitiveAlias(
+  checker: ts.TypeChecker,
+  typeNode?: ts.TypeNode
+): string | undefined {
+  if (!typeNode) {
+    return undefined
+  }
+
+  if (ts.isArrayTypeNode(typeNode)) {
+    const elementName = serializePrimitiveAlias(checker, typeNode.elementType)
+
+    return elementName ? `${elementName}[]` : undefined
+  }
+
+  if (!ts.isTypeReferenceNode(typeNode)) {
+    return undefined
+  }
+
+  if (
+    typeNode.typeName.getText() === "Promise" &&
+    typeNode.typeArguments?.length === 1
+  ) {
+    return serializePrimitiveAlias(checker, typeNode.typeArguments[0])
+  }
+
+  let symbol = checker.getSymbolAtLocation(typeNode.typeName)
+  if (symbol && symbol.flags & ts.SymbolFlags.Alias) {
+    symbol = checker.getAliasedSymbol(symbol)
+  }
+
+  if (!symbol || !(symbol.flags & ts.SymbolFlags.TypeAlias)) {
+    return undefined
+  }
+
+  const type = checker.getDeclaredTypeOfSymbol(symbol)
+  if (
+    !(
+      type.flags &
+      (ts.TypeFlags.String | ts.TypeFlags.Number | ts.TypeFlags.Boolean)
+    )
+  ) {
+    return undefined
+  }
+
+  return symbol.getName()
+}
@@ -15,6 +15,22 @@ export type ObjectTypeDef = BaseTypeDef & {
   name: string
 }
 
+/**
+ * Extends the base type def if it's an enum to add its name.
+ */
+export type EnumTypeDef = BaseTypeDef & {
+  kind: TypeDefKind.EnumKind
+  name: string
+}
+
+/**
+ * Extends the base type def if it's a scalar to add its name.
+ */
+export type ScalarTypeDef = BaseTypeDef & {
+  kind: TypeDefKind.ScalarKind
+  name: string
+}
+
 /**
  * Extends the base if it's a list to add its subtype.
  */
@@ -28,11 +44,17 @@ export type ListTypeDef = BaseTypeDef & {
  * depending on its type.
  *
  * If it's type of kind list, it transforms the BaseTypeDef into an ObjectTypeDef.
+ * If it's a type of kind enum, it transforms the BaseTypeDef into an EnumTypeDef.
+ * If it's a type of kind scalar, it transforms the BaseTypeDef into a ScalarTypeDef.
  * If it's a type of kind list, it transforms the BaseTypeDef into a ListTypeDef.
  */
 export type TypeDef<T extends BaseTypeDef["kind"]> =
   T extends TypeDefKind.ObjectKind
     ? ObjectTypeDef
+    : T extends TypeDefKind.EnumKind
+    ? EnumTypeDef
+    : T extends TypeDefKind.ScalarKind
+    ? ScalarTypeDef
     : T extends TypeDefKind.ListKind
     ? ListTypeDef
     : BaseTypeDef
@@ -82,3 +104,28 @@ export type ClassTypeDef = {
   constructor?: ConstructorTypeDef
   methods: { [name: string]: FunctionTypedef }
 }
+
+/**
+ * The value of an enum.
+ */
+export type EnumValueDef = {
+  value: string
+  description: string
+}
+
+/**
+ * A type of Enum.
+ */
+export type EnumDef = {
+  name: string
+  description: string
+  values: { [name: string]: EnumValueDef }
+}
+
+/**
+ * A type of Scalar.
+ */
+export type ScalarDef = {
+  name: string
+  description: string
+}
@@ -88,11 +88,15 @@ type OptionalValue = {
  * This includes both optional value defines with `?` and value that
  * have a default value.
  *
- * If there's a default value, its expression is returned in the result.
+ * If there's a default value, its JSON encoding is returned in the result.
  *
+ * @param checker The typescript compiler checker.
  * @param param The param to check.
  */
-export function isOptional(param: ts.Symbol): OptionalValue {
+export function isOptional(
+  checker: ts.TypeChecker,
+  param: ts.Symbol
+): OptionalValue {
   const result: OptionalValue = { optional: false }
 
   const declarations = param.getDeclarations()
@@ -109,7 +113,8 @@ export function isOptional(param: ts.Symbol): OptionalValue {
 
       if (parameterDeclaration.initializer !== undefined) {
         result.defaultValue = formatDefaultValue(
-          parameterDeclaration.initializer.getText()
+          checker,
+          parameterDeclaration.initializer
         )
       }
     }
@@ -118,27 +123,123 @@ export function isOptional(param: ts.Symbol): OptionalValue {
   return result
 }
 
-function formatDefaultValue(value: string): string {
-  const isSingleQuoteString = (): boolean =>
-    value.startsWith("'") && value.endsWith("'")
+/**
+ * Convert the initializer of a parameter into the JSON encoding expected
+ * by the Dagger API for default values.
+ *
+ * String literals are re-encoded (since they may use single quotes) and
+ * enum members are replaced by their value. Other expressions (numbers,
+ * booleans...) are kept as written.
+ *
+ * @param checker The typescript compiler checker.
+ * @param value The initializer expression.
+ */
+function formatDefaultValue(
+  checker: ts.TypeChecker,
+  value: ts.Expression
+): string {
+  if (ts.isStringLiteral(value) || ts.isNoSubstitutionTemplateLiteral(value)) {
+    return JSON.stringify(value.text)
+  }
+
+  if (ts.isArrayLiteralExpression(value)) {
+    const elements = value.elements.map((element) =>
+      formatDefaultValue(checker, element)
+    )
 
-  if (isSingleQuoteString()) {
-    return `"${value.slice(1, value.length - 1)}"`
+    return `[${elements.join(", ")}]`
   }
 
-  return value
+  if (ts.isPropertyAccessExpression(value)) {
+    const enumValue = checker.getConstantValue(value)
+    if (enumValue !== undefined) {
+      return JSON.stringify(enumValue)
+    }
+  }
+
+  return value.getText()
+}
+
+/**
+ * Return true if the given declaration is exported, so it can be
+ * used in the signature of the module's functions.
+ *
+ * @param node The declaration to check.
+ */
+export function isExported(
+  node: ts.EnumDeclaration | ts.TypeAliasDeclaration
+): boolean {
+  return (
+    ts
+      .getModifiers(node)
+      ?.find((m) => m.kind === ts.SyntaxKind.ExportKeyword) !== undefined
+  )
+}
+
+/**
+ * Return true if the given type alias is an alias of a primitive type,
+ * in which case it's registered as a custom scalar.
+ *
+ * Example
+ * ```
+ * export type Semver = string // Return true
+ * export type Pair = [string, string] // Return false
+ * ```
+ *
+ * @param node The type alias to check.
+ */
+export function isScalar(node: ts.TypeAliasDeclaration): boolean {
+  switch (node.type.kind) {
+    case ts.SyntaxKind.StringKeyword:
+    case ts.SyntaxKind.NumberKeyword:
+    case ts.SyntaxKind.BooleanKeyword:
+      return true
+    default:
+      return false
+  }
+}
+
+/**
+ * The names of the custom types declared by the module.
+ */
+export type CustomTypes = {
+  enums: string[]
+  scalars: string[]
 }
 
 /**
  * Convert a typename into a Dagger Typedef using dynamic typing.
+ *
+ * @param typeName The name of the type to convert.
+ * @param customTypes The names of the enums and scalars declared by the module.
  */
-export function typeNameToTypedef(typeName: string): TypeDef<TypeDefKind> {
+export function typeNameToTypedef(
+  typeName: string,
+  customTypes: CustomTypes = { enums: [], scalars: [] }
+): TypeDef<TypeDefKind> {
   // If it's a list, remove the '[]' and recall the function to get
   // the type of list
   if (typeName.endsWith("[]")) {
     return {
       kind: TypeDefKind.ListKind,
-      typeDef: typeNameToTypedef(typeName.slice(0, typeName.length - 2)),
+      typeDef: typeNameToTypedef(
+        typeName.slice(0, typeName.length - 2),
+        customTypes
+      ),
+    }
+  }
+
+  if (customTypes.enums.includes(typeName)) {
+    return {
+      kind: TypeDefKind.EnumKind,
+      name: typeName,
+    }
+  }
+
+  if (customTypes.scalars.includes(typeName)) {
+    return {
+      kind: TypeDefKind.ScalarKind,
+      name: typeName,
     }
   }
 
@@ -147,6 +248,8 @@ export function typeNameToTypedef(typeName: string): TypeDef<TypeDefKind> {
       return { kind: TypeDefKind.StringKind }
     case "number":
       return { kind: TypeDefKind.IntegerKind }
+    case "float":
+      return { kind: TypeDefKind.FloatKind }
     case "boolean":
       return { kind: TypeDefKind.BooleanKind }
     case "void":
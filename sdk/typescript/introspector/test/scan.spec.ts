@@ -43,6 +43,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -55,6 +57,8 @@ describe("scan static TypeScript", function () {
     const result = scan(files)
     const expected: ScanResult = {
       classes: {},
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -112,6 +116,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -161,6 +167,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -273,6 +281,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -357,6 +367,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -406,6 +418,8 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
       functions: {},
     }
 
@@ -462,6 +476,210 @@ describe("scan static TypeScript", function () {
           },
         },
       },
+      enums: {},
+      scalars: {},
+      functions: {},
+    }
+
+    assert.deepEqual(result, expected)
+  })
+
+  it("Should introspect exported enums", async function () {
+    const files = await listFiles(`${rootDirectory}/enums`)
+
+    const result = scan(files)
+    const expected: ScanResult = {
+      classes: {
+        Enums: {
+          name: "Enums",
+          description: "Enums class",
+          constructor: undefined,
+          fields: {},
+          methods: {
+            build: {
+              name: "build",
+              returnType: { kind: TypeDefKind.EnumKind, name: "Mode" },
+              description: "",
+              args: {
+                mode: {
+                  name: "mode",
+                  typeDef: { kind: TypeDefKind.EnumKind, name: "Mode" },
+                  description: "",
+                  optional: false,
+                  defaultValue: undefined,
+                },
+              },
+            },
+          },
+        },
+      },
+      enums: {
+        Mode: {
+          name: "Mode",
+          description: "Build mode",
+          values: {
+            DEBUG: { value: "DEBUG", description: "Debug build" },
+            RELEASE: { value: "RELEASE", description: "" },
+          },
+        },
+      },
+      scalars: {},
+      functions: {},
+    }
+
+    assert.deepEqual(result, expected)
+  })
+
+  it("Should introspect exported scalars", async function () {
+    const files = await listFiles(`${rootDirectory}/scalars`)
+
+    const result = scan(files)
+    const expected: ScanResult = {
+      classes: {
+        Scalars: {
+          name: "Scalars",
+          description: "Scalars class",
+          constructor: undefined,
+          fields: {},
+          methods: {
+            bump: {
+              name: "bump",
+              returnType: { kind: TypeDefKind.ScalarKind, name: "Semver" },
+              description: "",
+              args: {
+                version: {
+                  name: "version",
+                  typeDef: { kind: TypeDefKind.ScalarKind, name: "Semver" },
+                  description: "",
+                  optional: false,
+                  defaultValue: undefined,
+                },
+              },
+            },
+          },
+        },
+      },
+      enums: {},
+      scalars: {
+        Semver: {
+          name: "Semver",
+          description: "A semantic version",
+        },
+      },
+      functions: {},
+    }
+
+    assert.deepEqual(result, expected)
+  })
+
+  it("Should introspect float arguments and return values", async function () {
+    const files = await listFiles(`${rootDirectory}/floats`)
+
+    const result = scan(files)
+    const expected: ScanResult = {
+      classes: {
+        Floats: {
+          name: "Floats",
+          description: "Floats class",
+          constructor: undefined,
+          fields: {},
+          methods: {
+            scale: {
+              name: "scale",
+              returnType: { kind: TypeDefKind.FloatKind },
+              description: "",
+              args: {
+                value: {
+                  name: "value",
+                  typeDef: { kind: TypeDefKind.IntegerKind },
+                  description: "",
+                  optional: false,
+                  defaultValue: undefined,
+                },
+                ratio: {
+                  name: "ratio",
+                  typeDef: { kind: TypeDefKind.FloatKind },
+                  description: "",
+                  optional: false,
+                  defaultValue: undefined,
+                },
+              },
+            },
+          },
+        },
+      },
+      enums: {},
+      scalars: {},
+      functions: {},
+    }
+
+    assert.deepEqual(result, expected)
+  })
+
+  it("Should introspect default values as JSON", async function () {
+    const files = await listFiles(`${rootDirectory}/defaultValues`)
+
+    const result = scan(files)
+    const expected: ScanResult = {
+      classes: {
+        DefaultValues: {
+          name: "DefaultValues",
+          description: "DefaultValues class",
+          constructor: undefined,
+          fields: {},
+          methods: {
+            configure: {
+              name: "configure",
+              returnType: { kind: TypeDefKind.StringKind },
+              description: "",
+              args: {
+                name: {
+                  name: "name",
+                  typeDef: { kind: TypeDefKind.StringKind },
+                  description: "",
+                  optional: true,
+                  defaultValue: '"world"',
+                },
+                level: {
+                  name: "level",
+                  typeDef: { kind: TypeDefKind.EnumKind, name: "Level" },
+                  description: "",
+                  optional: true,
+                  defaultValue: '"HIGH"',
+                },
+                tags: {
+                  name: "tags",
+                  typeDef: {
+                    kind: TypeDefKind.ListKind,
+                    typeDef: { kind: TypeDefKind.StringKind },
+                  },
+                  description: "",
+                  optional: true,
+                  defaultValue: '["a", "b"]',
+                },
+                count: {
+                  name: "count",
+                  typeDef: { kind: TypeDefKind.IntegerKind },
+                  description: "",
+                  optional: true,
+                  defaultValue: "3",
+                },
+              },
+            },
+          },
+        },
+      },
+      enums: {
+        Level: {
+          name: "Level",
+          description: "",
+          values: {
+            LOW: { value: "LOW", description: "" },
+            HIGH: { value: "HIGH", description: "" },
+          },
+        },
+      },
+      scalars: {},
       functions: {},
     }
 
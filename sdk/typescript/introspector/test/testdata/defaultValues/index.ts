@@ -0,0 +1,22 @@
+import { func, object } from '../../../decorators/decorators.js'
+
+export enum Level {
+    Low = "LOW",
+    High = "HIGH",
+}
+
+/**
+ * DefaultValues class
+ */
+@object
+export class DefaultValues {
+    @func
+    configure(
+        name = 'world',
+        level: Level = Level.High,
+        tags: string[] = ['a', "b"],
+        count = 3
+    ): string {
+        return `${name} ${level} ${tags.join(",")} ${count}`
+    }
+}
@@ -0,0 +1,24 @@
+import { func, object } from '../../../decorators/decorators.js'
+
+/**
+ * Build mode
+ */
+export enum Mode {
+    /**
+     * Debug build
+     */
+    Debug = "DEBUG",
+
+    Release = "RELEASE",
+}
+
+/**
+ * Enums class
+ */
+@object
+export class Enums {
+    @func
+    build(mode: Mode): Mode {
+        return mode
+    }
+}
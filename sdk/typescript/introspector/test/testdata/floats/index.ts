@@ -0,0 +1,13 @@
+import { float } from '../../../../common/types.js'
+import { func, object } from '../../../decorators/decorators.js'
+
+/**
+ * Floats class
+ */
+@object
+export class Floats {
+    @func
+    scale(value: number, ratio: float): float {
+        return value * ratio
+    }
+}
@@ -0,0 +1,17 @@
+import { func, object } from '../../../decorators/decorators.js'
+
+/**
+ * A semantic version
+ */
+export type Semver = string
+
+/**
+ * Scalars class
+ */
+@object
+export class Scalars {
+    @func
+    bump(version: Semver): Semver {
+        return version
+    }
+}
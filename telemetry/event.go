@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/engine"
 )
 
 const eventVersion = "2023-02-28.01"
@@ -45,6 +46,9 @@ type OpPayload struct {
 	Completed *time.Time `json:"completed"`
 	Cached    bool       `json:"cached"`
 	Error     string     `json:"error"`
+
+	// Metrics are the latest resource metrics sampled for an exec.
+	Metrics *engine.ExecMetrics `json:"metrics,omitempty"`
 }
 
 func (OpPayload) Type() EventType   { return EventType("op") }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dagger/dagger/core/pipeline"
+	"github.com/dagger/dagger/engine"
 	"github.com/vito/progrock"
 )
 
@@ -16,6 +17,9 @@ type writer struct {
 	// vertex yet.
 	emittedMemberships map[vertexMembership]bool
 
+	// metrics keeps track of the latest resource metrics of each vertex.
+	metrics map[string]*engine.ExecMetrics
+
 	mu sync.Mutex
 }
 
@@ -29,6 +33,7 @@ func NewWriter(t *Telemetry) progrock.Writer {
 		telemetry:          t,
 		pipeliner:          NewPipeliner(),
 		emittedMemberships: map[vertexMembership]bool{},
+		metrics:            map[string]*engine.ExecMetrics{},
 	}
 }
 
@@ -58,6 +63,20 @@ func (t *writer) WriteStatus(ev *progrock.StatusUpdate) error {
 		}
 	}
 
+	for _, meta := range ev.Metas {
+		if meta.Name != engine.ExecMetricsMeta {
+			continue
+		}
+		metrics, err := engine.ExecMetricsFromProto(meta.Data)
+		if err != nil {
+			continue
+		}
+		t.metrics[meta.Vertex] = &metrics
+		if v, found := t.pipeliner.Vertex(meta.Vertex); found {
+			t.maybeEmitOp(ts, v, true)
+		}
+	}
+
 	for _, l := range ev.Logs {
 		t.telemetry.Push(LogPayload{
 			OpID:   l.Vertex,
@@ -116,6 +135,8 @@ func (t *writer) vertexOp(v *progrock.Vertex, pl pipeline.Path) OpPayload {
 		Error:  v.GetError(),
 
 		Inputs: v.Inputs,
+
+		Metrics: t.metrics[v.Id],
 	}
 
 	if v.Started != nil {